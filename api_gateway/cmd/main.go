@@ -4,6 +4,8 @@ import (
 	"api_gateway/internal/app"
 	"api_gateway/internal/config"
 	"api_gateway/logger"
+	"context"
+	"github.com/deeelis/platform/tracing"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -24,6 +26,12 @@ func main() {
 		slog.Int("port", cfg.HTTP.Port),
 	)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), "api_gateway", cfg.Tracing)
+	if err != nil {
+		log.Error("failed to set up tracing", logger.Err(err))
+		os.Exit(1)
+	}
+
 	startTime := time.Now()
 	application, err := app.NewApp(log, cfg)
 	if err != nil {
@@ -54,6 +62,12 @@ func main() {
 	shutdownStart := time.Now()
 	application.Stop()
 
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error("failed to flush traces", logger.Err(err))
+	}
+
 	log.Info("application stopped gracefully",
 		slog.Duration("shutdown_duration", time.Since(shutdownStart)),
 		slog.Duration("uptime", time.Since(startTime)))
@@ -0,0 +1,32 @@
+package clientip
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Key — ключ gRPC-метаданных с адресом клиента: сервисы видят соединение
+// только от шлюза, а адрес нужен им, например, для защиты входа от перебора.
+const Key = "x-client-ip"
+
+type ctxKey struct{}
+
+func WithContext(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, ip)
+}
+
+func FromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(ctxKey{}).(string)
+	return ip
+}
+
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if ip := FromContext(ctx); ip != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, Key, ip)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -3,18 +3,30 @@ package config
 import (
 	"errors"
 	"flag"
+	"github.com/deeelis/platform/tracing"
 	"github.com/ilyakaznacheev/cleanenv"
 	"os"
 	"time"
 )
 
 type Config struct {
-	Env     string         `yaml:"env" env-default:"local"`
-	GRPC    *GRPCConfig    `yaml:"grpc"`
-	HTTP    *HTTPConfig    `yaml:"http"`
-	Auth    *AuthConfig    `yaml:"auth"`
-	Kafka   *KafkaConfig   `yaml:"kafka"`
-	Storage *StorageConfig `yaml:"storage"`
+	Env         string             `yaml:"env" env-default:"local"`
+	GRPC        *GRPCConfig        `yaml:"grpc"`
+	HTTP        *HTTPConfig        `yaml:"http"`
+	Auth        *AuthConfig        `yaml:"auth"`
+	Kafka       *KafkaConfig       `yaml:"kafka"`
+	Storage     *StorageConfig     `yaml:"storage"`
+	Content     *ContentConfig     `yaml:"content"`
+	Upload      *UploadConfig      `yaml:"upload"`
+	S3          *S3Config          `yaml:"s3"`
+	Images      *ImagesConfig      `yaml:"images"`
+	Redis       *RedisConfig       `yaml:"redis"`
+	RateLimit   *RateLimitConfig   `yaml:"rate_limit"`
+	Idempotency *IdempotencyConfig `yaml:"idempotency"`
+	OpenAPI     *OpenAPIConfig     `yaml:"openapi"`
+	API         *APIConfig         `yaml:"api"`
+	Health      *HealthConfig      `yaml:"health"`
+	Tracing     *TracingConfig     `yaml:"tracing"`
 }
 
 type GRPCConfig struct {
@@ -26,13 +38,42 @@ type HTTPConfig struct {
 	ReadTimeout     time.Duration `yaml:"read_timeout" env-default:"10s"`
 	WriteTimeout    time.Duration `yaml:"write_timeout" env-default:"10s"`
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"5s"`
+	// TrustedProxies — адреса прокси, которым можно верить в X-Forwarded-For
+	TrustedProxies []string `yaml:"trusted_proxies"`
+	// AllowedOrigins — значения Origin, с которых браузер может открыть
+	// WebSocket. Без списка WebSocket открывается только не из браузера.
+	AllowedOrigins []string `yaml:"allowed_origins"`
 }
 
 type AuthConfig struct {
-	ServiceAddress string        `yaml:"service_address"`
-	Timeout        time.Duration `yaml:"timeout"`
+	ServiceAddress   string                 `yaml:"service_address"`
+	Timeout          time.Duration          `yaml:"timeout"`
+	Verification     AuthVerificationConfig `yaml:"verification"`
+	ResilienceConfig `yaml:",inline"`
 }
 
+// AuthVerificationConfig: mode — local (подпись токена проверяется в шлюзе
+// открытыми ключами из GetJWKS, набор обновляется раз в JWKSRefresh) или remote
+// (ValidateToken в auth_service). SecretEnv — переменная окружения с ключом HS256
+// для токенов, выпущенных до перехода на EdDSA; пустая — такие токены не принимаются.
+// В обоих режимах проверенные токены держатся в LRU на CacheSize записей,
+// а отозванные приходят из Redis Stream RevocationStream; без него шлюз не стартует.
+type AuthVerificationConfig struct {
+	Mode             string        `yaml:"mode"`
+	SecretEnv        string        `yaml:"secret_env"`
+	JWKSRefresh      time.Duration `yaml:"jwks_refresh"`
+	Leeway           time.Duration `yaml:"leeway"`
+	CacheSize        int           `yaml:"cache_size"`
+	RevocationStream string        `yaml:"revocation_stream"`
+}
+
+type HealthConfig struct {
+	Timeout time.Duration `yaml:"timeout" env-default:"2s"`
+}
+
+// TracingConfig — настройки экспорта трейсов, общие для всех сервисов
+type TracingConfig = tracing.Config
+
 type KafkaConfig struct {
 	Brokers         []string `yaml:"brokers" env-required:"true"`
 	TextTopic       string   `yaml:"text_topic" env-default:"content.text"`
@@ -41,8 +82,117 @@ type KafkaConfig struct {
 }
 
 type StorageConfig struct {
-	ServiceAddress string        `yaml:"service_address"`
-	Timeout        time.Duration `yaml:"timeout"`
+	ServiceAddress   string        `yaml:"service_address"`
+	Timeout          time.Duration `yaml:"timeout"`
+	ResilienceConfig `yaml:",inline"`
+}
+
+// ResilienceConfig — поведение gRPC-клиента при сбоях зависимости. Timeout
+// клиента действует для всех методов, Deadlines переопределяют его по имени
+// метода. LoadBalancing — round_robin (по всем адресам из DNS) или pick_first.
+// Незаданные значения берутся по умолчанию из пакета resilience.
+type ResilienceConfig struct {
+	Deadlines      map[string]time.Duration `yaml:"deadlines"`
+	LoadBalancing  string                   `yaml:"load_balancing"`
+	Retry          RetryConfig              `yaml:"retry"`
+	CircuitBreaker CircuitBreakerConfig     `yaml:"circuit_breaker"`
+}
+
+// RetryConfig применяется только к идемпотентным методам; MaxAttempts: 1 отключает повторы.
+type RetryConfig struct {
+	MaxAttempts       int           `yaml:"max_attempts"`
+	InitialBackoff    time.Duration `yaml:"initial_backoff"`
+	MaxBackoff        time.Duration `yaml:"max_backoff"`
+	BackoffMultiplier float64       `yaml:"backoff_multiplier"`
+}
+
+// CircuitBreakerConfig: после FailureThreshold сбоев подряд вызовы отклоняются
+// сразу в течение OpenTimeout, затем пропускается HalfOpenRequests пробных.
+type CircuitBreakerConfig struct {
+	Disabled         bool          `yaml:"disabled"`
+	FailureThreshold int           `yaml:"failure_threshold"`
+	OpenTimeout      time.Duration `yaml:"open_timeout"`
+	HalfOpenRequests int           `yaml:"half_open_requests"`
+}
+
+// S3Config: PublicEndpoint — адрес хранилища, доступный клиентам; на него
+// выписываются подписанные ссылки. Если не задан, используется Endpoint.
+type S3Config struct {
+	Endpoint       string `yaml:"endpoint"`
+	PublicEndpoint string `yaml:"public_endpoint"`
+	Region         string `yaml:"region" env-default:"us-east-1"`
+	Bucket         string `yaml:"bucket" env-default:"images"`
+	AccessKey      string `yaml:"access_key"`
+	SecretKey      string `yaml:"secret_key"`
+}
+
+// ImagesConfig управляет выдачей изображений из приватного бакета: delivery —
+// redirect (302 на подписанную ссылку со сроком URLTTL) или proxy (шлюз
+// отдаёт объект сам). Moderators — id пользователей, которым видны чужие
+// и отклонённые модерацией изображения.
+type ImagesConfig struct {
+	Delivery   string        `yaml:"delivery" env-default:"redirect"`
+	URLTTL     time.Duration `yaml:"url_ttl" env-default:"5m"`
+	Moderators []string      `yaml:"moderators"`
+}
+
+type RedisConfig struct {
+	URL string `yaml:"url" env-default:"redis://redis:6379/1"`
+}
+
+// RateLimitConfig задаёт token bucket для каждой группы маршрутов
+// и суточную квоту загрузок на пользователя.
+type RateLimitConfig struct {
+	Enabled      bool                    `yaml:"enabled" env-default:"true"`
+	Groups       map[string]*LimitConfig `yaml:"groups"`
+	DailyUploads int                     `yaml:"daily_uploads" env-default:"500"`
+}
+
+type LimitConfig struct {
+	Requests int           `yaml:"requests"`
+	Period   time.Duration `yaml:"period"`
+	Burst    int           `yaml:"burst"`
+}
+
+type IdempotencyConfig struct {
+	TTL         time.Duration `yaml:"ttl" env-default:"24h"`
+	LockTimeout time.Duration `yaml:"lock_timeout" env-default:"1m"`
+}
+
+type OpenAPIConfig struct {
+	ValidateRequests  bool `yaml:"validate_requests" env-default:"true"`
+	ValidateResponses bool `yaml:"validate_responses" env-default:"false"`
+}
+
+// APIConfig управляет версиями публичного API. Пути без префикса версии
+// обслуживаются как устаревшие синонимы /v1 до даты LegacySunset.
+type APIConfig struct {
+	LegacyRoutes     bool      `yaml:"legacy_routes" env-default:"true"`
+	LegacyDeprecated time.Time `yaml:"legacy_deprecated" env-layout:"2006-01-02" env-default:"2026-10-16"`
+	LegacySunset     time.Time `yaml:"legacy_sunset" env-layout:"2006-01-02" env-default:"2027-04-16"`
+}
+
+type ContentConfig struct {
+	MaxBatchSize int `yaml:"max_batch_size" env-default:"100"`
+}
+
+// UploadConfig — политика приёма загрузок. Поля верхнего уровня действуют для всех,
+// Tiers переопределяют их для тарифов (незаданное поле наследуется), Users
+// назначает тариф пользователю по его id.
+type UploadConfig struct {
+	UploadPolicy `yaml:",inline"`
+	Tiers        map[string]*UploadPolicy `yaml:"tiers"`
+	Users        map[string]string        `yaml:"users"`
+}
+
+// UploadPolicy: размеры — в байтах тела запроса, для изображения — в байтах файла.
+// MaxTextRunes ограничивает длину текста в символах, а не в байтах UTF-8.
+type UploadPolicy struct {
+	MaxTextBytes      int64    `yaml:"max_text_bytes" env-default:"262144"`
+	MaxTextRunes      int      `yaml:"max_text_runes" env-default:"20000"`
+	MaxImageBytes     int64    `yaml:"max_image_bytes" env-default:"10485760"`
+	MaxBatchBytes     int64    `yaml:"max_batch_bytes" env-default:"33554432"`
+	AllowedImageTypes []string `yaml:"allowed_image_types" env-default:"image/jpeg,image/png,image/gif,image/webp"`
 }
 
 func MustLoad() (*Config, error) {
@@ -2,6 +2,8 @@ package http_controllers
 
 import (
 	"api_gateway/internal/config"
+	"api_gateway/internal/pages"
+	"api_gateway/internal/problem"
 	"api_gateway/internal/usecases"
 	"api_gateway/internal/usecases/auth_usecase"
 	"api_gateway/logger"
@@ -29,7 +31,12 @@ func NewAuthController(cfg *config.AuthConfig, log *slog.Logger) (*AuthControlle
 	}
 
 	log.Info("auth controller initialized successfully")
-	return &AuthController{cfg: cfg, authUC: uc, log: log}, nil
+	return NewAuthControllerWithUsecase(cfg, uc, log), nil
+}
+
+// NewAuthControllerWithUsecase собирает контроллер поверх готового usecase
+func NewAuthControllerWithUsecase(cfg *config.AuthConfig, uc usecases.AuthUsecase, log *slog.Logger) *AuthController {
+	return &AuthController{cfg: cfg, authUC: uc, log: log}
 }
 
 func (c *AuthController) Register(ctx *gin.Context) {
@@ -54,7 +61,7 @@ func (c *AuthController) Register(ctx *gin.Context) {
 			slog.String("email", req.Email),
 			slog.Int("name_length", len(req.Name)),
 			slog.Bool("has_password", req.Password != ""))
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		problem.BadRequest(ctx, "invalid request")
 		return
 	}
 
@@ -67,7 +74,7 @@ func (c *AuthController) Register(ctx *gin.Context) {
 		log.Error("registration failed",
 			logger.Err(err),
 			slog.String("email", req.Email))
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		problem.Error(ctx, err)
 		return
 	}
 
@@ -98,7 +105,7 @@ func (c *AuthController) Login(ctx *gin.Context) {
 			logger.Err(err),
 			slog.String("email", req.Email),
 			slog.Bool("has_password", req.Password != ""))
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		problem.BadRequest(ctx, "invalid request")
 		return
 	}
 
@@ -110,13 +117,13 @@ func (c *AuthController) Login(ctx *gin.Context) {
 		log.Warn("login failed",
 			logger.Err(err),
 			slog.String("email", req.Email))
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		problem.Error(ctx, err)
 		return
 	}
 
 	log.Info("login successful",
 		slog.String("email", req.Email))
-	ctx.JSON(http.StatusOK, tokens)
+	ctx.JSON(http.StatusOK, presenter(ctx).Tokens(tokens))
 }
 
 func (c *AuthController) RefreshToken(ctx *gin.Context) {
@@ -137,7 +144,7 @@ func (c *AuthController) RefreshToken(ctx *gin.Context) {
 		log.Warn("invalid refresh token request",
 			logger.Err(err),
 			slog.Bool("has_token", req.RefreshToken != ""))
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		problem.BadRequest(ctx, "invalid request")
 		return
 	}
 
@@ -148,10 +155,186 @@ func (c *AuthController) RefreshToken(ctx *gin.Context) {
 	if err != nil {
 		log.Warn("refresh token failed",
 			logger.Err(err))
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		problem.Error(ctx, err)
 		return
 	}
 
 	log.Info("refresh token successful")
-	ctx.JSON(http.StatusOK, tokens)
+	ctx.JSON(http.StatusOK, presenter(ctx).Tokens(tokens))
+}
+
+func (c *AuthController) Logout(ctx *gin.Context) {
+	const op = "http_controllers.AuthController.Logout"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+		slog.String("user_id", ctx.GetString("userID")),
+	)
+
+	log.Info("handling logout request")
+
+	if err := c.authUC.Logout(ctx.Request.Context(), ctx.GetHeader("Authorization")); err != nil {
+		log.Warn("logout failed",
+			logger.Err(err))
+		problem.Error(ctx, err)
+		return
+	}
+
+	log.Info("logout successful")
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *AuthController) LogoutAll(ctx *gin.Context) {
+	const op = "http_controllers.AuthController.LogoutAll"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+		slog.String("user_id", ctx.GetString("userID")),
+	)
+
+	log.Info("handling logout all request")
+
+	if err := c.authUC.LogoutAll(ctx.Request.Context(), ctx.GetHeader("Authorization")); err != nil {
+		log.Warn("logout all failed",
+			logger.Err(err))
+		problem.Error(ctx, err)
+		return
+	}
+
+	log.Info("logout all successful")
+	ctx.Status(http.StatusNoContent)
+}
+
+// VerifyEmailPage и ResetPasswordPage — страницы из ссылок в письмах. Токен
+// лежит в адресе страницы, поэтому он не должен уходить в Referer.
+func (c *AuthController) VerifyEmailPage(ctx *gin.Context) {
+	ctx.Header("Referrer-Policy", "no-referrer")
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", pages.VerifyEmail)
+}
+
+func (c *AuthController) ResetPasswordPage(ctx *gin.Context) {
+	ctx.Header("Referrer-Policy", "no-referrer")
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", pages.ResetPassword)
+}
+
+// JWKS отдаёт открытые ключи подписи токенов для внешних проверяющих.
+func (c *AuthController) JWKS(ctx *gin.Context) {
+	const op = "http_controllers.AuthController.JWKS"
+	log := c.log.With(slog.String("op", op))
+
+	keys, err := c.authUC.GetJWKS(ctx.Request.Context())
+	if err != nil {
+		log.Warn("failed to get jwks",
+			logger.Err(err))
+		problem.Error(ctx, err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, gin.H{"keys": keys})
+}
+
+func (c *AuthController) VerifyEmail(ctx *gin.Context) {
+	const op = "http_controllers.AuthController.VerifyEmail"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+	)
+
+	log.Info("handling email verification request")
+
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn("invalid email verification request",
+			logger.Err(err))
+		problem.BadRequest(ctx, "invalid request")
+		return
+	}
+
+	if err := c.authUC.VerifyEmail(ctx.Request.Context(), req.Token); err != nil {
+		log.Warn("email verification failed",
+			logger.Err(err))
+		problem.Error(ctx, err)
+		return
+	}
+
+	log.Info("email verification successful")
+	ctx.Status(http.StatusNoContent)
+}
+
+// RequestPasswordReset отвечает 202 и для неизвестного email: по ответу нельзя
+// узнать, зарегистрирован ли адрес.
+func (c *AuthController) RequestPasswordReset(ctx *gin.Context) {
+	const op = "http_controllers.AuthController.RequestPasswordReset"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+	)
+
+	log.Info("handling password reset request")
+
+	var req struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn("invalid password reset request",
+			logger.Err(err),
+			slog.String("email", req.Email))
+		problem.BadRequest(ctx, "invalid request")
+		return
+	}
+
+	if err := c.authUC.RequestPasswordReset(ctx.Request.Context(), req.Email); err != nil {
+		log.Error("password reset request failed",
+			logger.Err(err),
+			slog.String("email", req.Email))
+		problem.Error(ctx, err)
+		return
+	}
+
+	log.Info("password reset request accepted")
+	ctx.Status(http.StatusAccepted)
+}
+
+func (c *AuthController) ConfirmPasswordReset(ctx *gin.Context) {
+	const op = "http_controllers.AuthController.ConfirmPasswordReset"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+	)
+
+	log.Info("handling password reset confirmation")
+
+	var req struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required,min=8"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn("invalid password reset confirmation",
+			logger.Err(err),
+			slog.Bool("has_token", req.Token != ""),
+			slog.Bool("has_password", req.Password != ""))
+		problem.BadRequest(ctx, "invalid request")
+		return
+	}
+
+	if err := c.authUC.ConfirmPasswordReset(ctx.Request.Context(), req.Token, req.Password); err != nil {
+		log.Warn("password reset confirmation failed",
+			logger.Err(err))
+		problem.Error(ctx, err)
+		return
+	}
+
+	log.Info("password reset successful")
+	ctx.Status(http.StatusNoContent)
 }
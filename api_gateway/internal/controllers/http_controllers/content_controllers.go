@@ -2,23 +2,34 @@ package http_controllers
 
 import (
 	"api_gateway/internal/config"
-	"api_gateway/internal/domain/errors"
 	"api_gateway/internal/domain/models"
+	"api_gateway/internal/problem"
+	"api_gateway/internal/ratelimit"
+	"api_gateway/internal/ratelimit/redis_ratelimit"
+	"api_gateway/internal/upload"
 	"api_gateway/internal/usecases"
 	"api_gateway/internal/usecases/content_usecase"
 	"api_gateway/logger"
 	"context"
 	"encoding/base64"
+	"fmt"
+	errorsv1 "github.com/deeelis/errors-protos/gen/go/errors"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	"io"
 	"log/slog"
 	"net/http"
-	"strings"
+	"strconv"
+	"time"
+	"unicode/utf8"
 )
 
 type ContentController struct {
 	cfg       *config.Config
 	contentUC usecases.ContentUsecase
+	quota     ratelimit.Quota
+	uploads   *upload.Policies
+	upgrader  websocket.Upgrader
 	log       *slog.Logger
 }
 
@@ -33,9 +44,39 @@ func NewContentController(cfg *config.Config, log *slog.Logger) (*ContentControl
 		return nil, err
 	}
 
+	var quota ratelimit.Quota
+	if cfg.RateLimit.Enabled {
+		quota, err = redis_ratelimit.NewRedisRateLimiter(cfg, log)
+		if err != nil {
+			log.Error("failed to create upload quota", logger.Err(err))
+			return nil, err
+		}
+	}
+
+	controller, err := NewContentControllerWithUsecase(cfg, uc, quota, log)
+	if err != nil {
+		log.Error("invalid upload policy", logger.Err(err))
+		return nil, err
+	}
+
 	log.Info("content controller initialized successfully")
+	return controller, nil
+}
+
+// NewContentControllerWithUsecase собирает контроллер поверх готового usecase.
+// Без quota суточная квота загрузок не проверяется.
+func NewContentControllerWithUsecase(cfg *config.Config, uc usecases.ContentUsecase, quota ratelimit.Quota, log *slog.Logger) (*ContentController, error) {
+	uploads, err := upload.New(cfg.Upload)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ContentController{
+		cfg:       cfg,
 		contentUC: uc,
+		quota:     quota,
+		uploads:   uploads,
+		upgrader:  newUpgrader(cfg.HTTP.AllowedOrigins),
 		log:       log,
 	}, nil
 }
@@ -60,19 +101,37 @@ func (c *ContentController) UploadText(ctx *gin.Context) {
 		log.Warn("invalid request body",
 			logger.Err(err),
 			slog.Int("text_length", len(req.Text)))
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		problem.InvalidBody(ctx, err, "invalid request")
+		return
+	}
+
+	policy := c.uploads.For(userID.(string))
+	runes := utf8.RuneCountInString(req.Text)
+	if runes > policy.MaxTextRunes {
+		log.Warn("text exceeds length limit",
+			slog.String("tier", policy.Tier),
+			slog.Int("text_runes", runes),
+			slog.Int("max_text_runes", policy.MaxTextRunes))
+		problem.TooLarge(ctx, "text too long", int64(policy.MaxTextRunes), "runes")
 		return
 	}
 
 	log.Debug("processing text content",
-		slog.Int("text_length", len(req.Text)))
+		slog.Int("text_length", len(req.Text)),
+		slog.Int("text_runes", runes))
 
-	content, err := c.contentUC.ProcessContent(userID.(string), models.ContentTypeText, req.Text, "text/plain")
+	reservation, ok := c.reserveUploads(ctx, log, userID.(string), 1)
+	if !ok {
+		return
+	}
+
+	content, err := c.contentUC.ProcessContent(ctx, userID.(string), models.ContentTypeText, req.Text, "text/plain")
 	if err != nil {
+		c.releaseUploads(log, reservation, 1)
 		log.Error("failed to process text content",
 			logger.Err(err),
 			slog.String("content_type", string(models.ContentTypeText)))
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		problem.Error(ctx, err)
 		return
 	}
 
@@ -80,10 +139,7 @@ func (c *ContentController) UploadText(ctx *gin.Context) {
 		slog.String("content_id", content.ID),
 		slog.String("content_status", content.Status))
 
-	ctx.JSON(http.StatusAccepted, gin.H{
-		"id":     content.ID,
-		"status": content.Status,
-	})
+	ctx.JSON(http.StatusAccepted, presenter(ctx).Accepted(content))
 }
 
 func (c *ContentController) UploadImage(ctx *gin.Context) {
@@ -102,7 +158,7 @@ func (c *ContentController) UploadImage(ctx *gin.Context) {
 	if err != nil {
 		log.Warn("failed to get image file from request",
 			logger.Err(err))
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid image"})
+		problem.InvalidBody(ctx, err, "invalid image")
 		return
 	}
 
@@ -110,12 +166,22 @@ func (c *ContentController) UploadImage(ctx *gin.Context) {
 		slog.String("filename", file.Filename),
 		slog.Int64("size", file.Size))
 
+	policy := c.uploads.For(userID.(string))
+	if file.Size > policy.MaxImageBytes {
+		log.Warn("image file size exceeds limit",
+			slog.String("tier", policy.Tier),
+			slog.Int64("size_bytes", file.Size),
+			slog.Int64("max_size_bytes", policy.MaxImageBytes))
+		problem.TooLarge(ctx, "image file too large", policy.MaxImageBytes, "bytes")
+		return
+	}
+
 	fileData, err := file.Open()
 	if err != nil {
 		log.Error("failed to open uploaded image file",
 			logger.Err(err),
 			slog.String("filename", file.Filename))
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		problem.Error(ctx, err)
 		return
 	}
 	defer fileData.Close()
@@ -125,24 +191,19 @@ func (c *ContentController) UploadImage(ctx *gin.Context) {
 		log.Error("failed to read image file data",
 			logger.Err(err),
 			slog.String("filename", file.Filename))
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		return
-	}
-
-	if len(imageBytes) > 1<<20 {
-		log.Warn("image file size exceeds limit",
-			slog.Int("size_bytes", len(imageBytes)),
-			slog.Int("max_size_bytes", 1<<20))
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "image file too large, max 1MB"})
+		problem.Error(ctx, err)
 		return
 	}
 
 	mimeType := http.DetectContentType(imageBytes)
-	if !strings.HasPrefix(mimeType, "image/") {
-		log.Warn("uploaded file is not a valid image",
+	if !policy.AllowsImageType(mimeType) {
+		log.Warn("image type is not allowed",
+			slog.String("tier", policy.Tier),
 			slog.String("mime_type", mimeType),
 			slog.String("filename", file.Filename))
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "uploaded file is not an image"})
+		problem.Write(ctx, problem.New(errorsv1.ErrorCode_UNSUPPORTED_MEDIA_TYPE, "image type is not allowed").
+			With("mime_type", mimeType).
+			With("allowed", policy.AllowedImageTypes))
 		return
 	}
 
@@ -150,19 +211,23 @@ func (c *ContentController) UploadImage(ctx *gin.Context) {
 		slog.String("mime_type", mimeType),
 		slog.Int("size_bytes", len(imageBytes)))
 
-	base64Str := base64.StdEncoding.EncodeToString(imageBytes)
+	reservation, ok := c.reserveUploads(ctx, log, userID.(string), 1)
+	if !ok {
+		return
+	}
 
-	content, err := c.contentUC.ProcessContent(
+	content, err := c.contentUC.ProcessImage(
+		ctx,
 		userID.(string),
-		models.ContentTypeImage,
-		base64Str,
+		imageBytes,
 		mimeType,
 	)
 	if err != nil {
+		c.releaseUploads(log, reservation, 1)
 		log.Error("failed to process image content",
 			logger.Err(err),
 			slog.String("content_type", string(models.ContentTypeImage)))
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		problem.Error(ctx, err)
 		return
 	}
 
@@ -170,10 +235,127 @@ func (c *ContentController) UploadImage(ctx *gin.Context) {
 		slog.String("content_id", content.ID),
 		slog.String("content_status", content.Status))
 
-	ctx.JSON(http.StatusAccepted, gin.H{
-		"id":     content.ID,
-		"status": content.Status,
-	})
+	ctx.JSON(http.StatusAccepted, presenter(ctx).Accepted(content))
+}
+
+func (c *ContentController) UploadBatch(ctx *gin.Context) {
+	const op = "http_controllers.ContentController.UploadBatch"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+	)
+	userID, _ := ctx.Get("userID")
+	log = log.With(slog.String("user_id", userID.(string)))
+
+	log.Info("handling batch upload request")
+
+	var req struct {
+		Items []struct {
+			Type  string `json:"type"`
+			Text  string `json:"text"`
+			Image string `json:"image"`
+		} `json:"items" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn("invalid request body", logger.Err(err))
+		problem.InvalidBody(ctx, err, "invalid request")
+		return
+	}
+
+	if len(req.Items) == 0 || len(req.Items) > c.cfg.Content.MaxBatchSize {
+		log.Warn("batch size out of range",
+			slog.Int("batch_size", len(req.Items)),
+			slog.Int("max_batch_size", c.cfg.Content.MaxBatchSize))
+		problem.Write(ctx, problem.New(errorsv1.ErrorCode_INVALID_ARGUMENT, "invalid batch size").
+			With("max_batch_size", c.cfg.Content.MaxBatchSize))
+		return
+	}
+
+	policy := c.uploads.For(userID.(string))
+	results := make([]models.BatchItemResult, len(req.Items))
+	contents := make([]*models.Content, 0, len(req.Items))
+	indexes := make([]int, 0, len(req.Items))
+
+	for i, item := range req.Items {
+		results[i].Index = i
+
+		switch models.ContentType(item.Type) {
+		case models.ContentTypeText:
+			if item.Text == "" {
+				results[i].Error = "text is required"
+				continue
+			}
+			if utf8.RuneCountInString(item.Text) > policy.MaxTextRunes {
+				results[i].Error = fmt.Sprintf("text too long, max %d characters", policy.MaxTextRunes)
+				continue
+			}
+			contents = append(contents, &models.Content{
+				Type:     models.ContentTypeText,
+				Data:     item.Text,
+				DataType: "text/plain",
+			})
+		case models.ContentTypeImage:
+			imageBytes, err := base64.StdEncoding.DecodeString(item.Image)
+			if err != nil || len(imageBytes) == 0 {
+				results[i].Error = "image must be non-empty base64"
+				continue
+			}
+			if int64(len(imageBytes)) > policy.MaxImageBytes {
+				results[i].Error = fmt.Sprintf("image file too large, max %d bytes", policy.MaxImageBytes)
+				continue
+			}
+			mimeType := http.DetectContentType(imageBytes)
+			if !policy.AllowsImageType(mimeType) {
+				results[i].Error = "image type is not allowed: " + mimeType
+				continue
+			}
+			contents = append(contents, &models.Content{
+				Type:     models.ContentTypeImage,
+				DataType: mimeType,
+				Blob:     imageBytes,
+			})
+		default:
+			results[i].Error = "invalid content type"
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+
+	log.Debug("batch validated",
+		slog.Int("batch_size", len(req.Items)),
+		slog.Int("valid_items", len(contents)))
+
+	if len(contents) == 0 {
+		log.Warn("no valid items in batch")
+		problem.Write(ctx, problem.New(errorsv1.ErrorCode_INVALID_ARGUMENT, "no valid items in batch").
+			With("items", results))
+		return
+	}
+
+	reservation, ok := c.reserveUploads(ctx, log, userID.(string), len(contents))
+	if !ok {
+		return
+	}
+
+	processed, err := c.contentUC.ProcessContentBatch(ctx, userID.(string), contents)
+	if err != nil {
+		c.releaseUploads(log, reservation, len(contents))
+		log.Error("failed to process content batch", logger.Err(err))
+		problem.Error(ctx, err)
+		return
+	}
+
+	for j, content := range processed {
+		results[indexes[j]].ID = content.ID
+	}
+
+	log.Info("batch processed successfully",
+		slog.Int("accepted", len(processed)),
+		slog.Int("rejected", len(req.Items)-len(processed)))
+
+	ctx.JSON(http.StatusAccepted, presenter(ctx).BatchResult(results))
 }
 
 func (c *ContentController) GetContent(ctx *gin.Context) {
@@ -194,54 +376,213 @@ func (c *ContentController) GetContent(ctx *gin.Context) {
 
 	if contentID == "" {
 		log.Warn("empty content id in request")
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "content id is required"})
+		problem.BadRequest(ctx, "content id is required")
 		return
 	}
 
-	contentStatus, err := c.contentUC.GetContent(ctx, contentID)
+	contentStatus, err := c.contentUC.GetContent(ctx, userID.(string), contentID)
 	if err != nil {
 		log.Error("failed to retrieve content",
 			logger.Err(err))
 
-		switch err {
-		case errors.ErrInvalidCredentials:
-			log.Warn("invalid credentials for content access")
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid content id"})
-		default:
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		}
+		problem.Error(ctx, err)
 		return
 	}
 
 	log.Debug("content retrieved successfully",
 		slog.String("content_status", contentStatus.Status))
 
-	content := models.Content{
-		ID:     contentStatus.ID,
-		UserID: userID.(string),
-		Type:   models.ContentType(contentStatus.Type),
-		Data:   contentStatus.OriginalContent,
-		Status: contentStatus.Status,
+	log.Info("content request completed successfully")
+	ctx.JSON(http.StatusOK, presenter(ctx).Content(contentStatus))
+}
+
+// GetImage отдаёт изображение из приватного бакета: редиректом на подписанную
+// ссылку или телом ответа, если шлюз настроен проксировать объекты.
+func (c *ContentController) GetImage(ctx *gin.Context) {
+	const op = "http_controllers.ContentController.GetImage"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+	)
+	userID, _ := ctx.Get("userID")
+	contentID := ctx.Param("id")
+	log = log.With(
+		slog.String("user_id", userID.(string)),
+		slog.String("content_id", contentID),
+	)
+
+	log.Info("handling get image request")
+
+	if contentID == "" {
+		log.Warn("empty content id in request")
+		problem.BadRequest(ctx, "content id is required")
+		return
 	}
 
-	response := gin.H{
-		"id":     contentStatus.ID,
-		"status": contentStatus.Status,
-		"type":   contentStatus.Type,
+	image, err := c.contentUC.GetImage(ctx, userID.(string), contentID)
+	if err != nil {
+		log.Error("failed to retrieve image",
+			logger.Err(err))
+
+		problem.Error(ctx, err)
+		return
 	}
 
-	if len(contentStatus.Analysis) > 0 {
-		response["analysis"] = contentStatus.Analysis
-		log.Debug("content analysis included in response",
-			slog.Int("analysis_length", len(contentStatus.Analysis)))
+	// ссылка и содержимое зависят от прав пользователя, кешировать их нельзя
+	ctx.Header("Cache-Control", "private, no-store")
+	if image.URL != "" {
+		log.Info("redirecting to signed image url")
+		ctx.Redirect(http.StatusFound, image.URL)
+		return
 	}
 
-	if contentStatus.Status == "COMPLETED" {
-		response["data"] = content.Data
-		log.Debug("content data included in response",
-			slog.Int("data_length", len(content.Data)))
+	defer image.Body.Close()
+	log.Info("streaming image",
+		slog.String("content_type", image.ContentType),
+		slog.Int64("size_bytes", image.Size))
+	ctx.DataFromReader(http.StatusOK, image.Size, image.ContentType, image.Body, map[string]string{
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func (c *ContentController) ListContent(ctx *gin.Context) {
+	const op = "http_controllers.ContentController.ListContent"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+	)
+	userID, _ := ctx.Get("userID")
+	log = log.With(slog.String("user_id", userID.(string)))
+
+	log.Info("handling list content request")
+
+	var req struct {
+		Status        string     `form:"status" binding:"omitempty,oneof=pending processing completed failed PENDING PROCESSING COMPLETED FAILED"`
+		Type          string     `form:"type" binding:"omitempty,oneof=text image TEXT IMAGE"`
+		CreatedAfter  *time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+		CreatedBefore *time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+		Limit         int        `form:"limit" binding:"omitempty,min=1,max=100"`
+		Cursor        string     `form:"cursor"`
 	}
 
-	log.Info("content request completed successfully")
-	ctx.JSON(http.StatusOK, response)
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		log.Warn("invalid query parameters", logger.Err(err))
+		problem.BadRequest(ctx, "invalid query parameters")
+		return
+	}
+
+	page, err := c.contentUC.ListContent(ctx, userID.(string), &models.ContentFilter{
+		Status:        req.Status,
+		Type:          req.Type,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		Limit:         req.Limit,
+		Cursor:        req.Cursor,
+	})
+	if err != nil {
+		log.Error("failed to list content", logger.Err(err))
+
+		problem.Error(ctx, err)
+		return
+	}
+
+	log.Info("list content request completed successfully",
+		slog.Int("items", len(page.Items)))
+
+	ctx.JSON(http.StatusOK, presenter(ctx).ContentPage(page))
+}
+
+func (c *ContentController) DeleteContent(ctx *gin.Context) {
+	const op = "http_controllers.ContentController.DeleteContent"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+	)
+	userID, _ := ctx.Get("userID")
+	contentID := ctx.Param("id")
+	log = log.With(
+		slog.String("user_id", userID.(string)),
+		slog.String("content_id", contentID),
+	)
+
+	log.Info("handling delete content request")
+
+	purgeAfter, err := c.contentUC.DeleteContent(ctx, userID.(string), contentID)
+	if err != nil {
+		log.Error("failed to delete content", logger.Err(err))
+
+		problem.Error(ctx, err)
+		return
+	}
+
+	log.Info("delete content request completed successfully")
+	ctx.JSON(http.StatusOK, presenter(ctx).Deleted(contentID, purgeAfter))
+}
+
+func (c *ContentController) RestoreContent(ctx *gin.Context) {
+	const op = "http_controllers.ContentController.RestoreContent"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+	)
+	userID, _ := ctx.Get("userID")
+	contentID := ctx.Param("id")
+	log = log.With(
+		slog.String("user_id", userID.(string)),
+		slog.String("content_id", contentID),
+	)
+
+	log.Info("handling restore content request")
+
+	if err := c.contentUC.RestoreContent(ctx, userID.(string), contentID); err != nil {
+		log.Error("failed to restore content", logger.Err(err))
+
+		problem.Error(ctx, err)
+		return
+	}
+
+	log.Info("restore content request completed successfully")
+	ctx.JSON(http.StatusOK, presenter(ctx).Restored(contentID))
+}
+
+// reserveUploads списывает n загрузок из суточной квоты пользователя и
+// возвращает ключ списания для releaseUploads. Возвращает false, если квота
+// исчерпана и ответ уже отправлен.
+func (c *ContentController) reserveUploads(ctx *gin.Context, log *slog.Logger, userID string, n int) (string, bool) {
+	if c.quota == nil {
+		return "", true
+	}
+
+	res, err := c.quota.Consume(ctx.Request.Context(), userID, n)
+	if err != nil {
+		log.Error("upload quota unavailable", logger.Err(err))
+		return "", true
+	}
+	if res.Allowed {
+		return res.Key, true
+	}
+
+	log.Warn("daily upload quota exceeded",
+		slog.Int("used", res.Used),
+		slog.Int("limit", res.Limit),
+		slog.Int("requested", n))
+	ctx.Header("Retry-After", strconv.Itoa(int(time.Until(res.ResetsAt).Seconds())+1))
+	problem.Write(ctx, problem.New(errorsv1.ErrorCode_QUOTA_EXCEEDED, "daily upload quota exceeded").
+		With("used", res.Used).
+		With("limit", res.Limit).
+		With("resets_at", res.ResetsAt))
+	return "", false
+}
+
+func (c *ContentController) releaseUploads(log *slog.Logger, reservation string, n int) {
+	if c.quota == nil || reservation == "" {
+		return
+	}
+	if err := c.quota.Release(context.Background(), reservation, n); err != nil {
+		log.Warn("failed to release upload quota", logger.Err(err))
+	}
 }
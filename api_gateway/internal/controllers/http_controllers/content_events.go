@@ -0,0 +1,311 @@
+package http_controllers
+
+import (
+	"api_gateway/internal/controllers/middleware_controller"
+	"api_gateway/internal/domain/errors"
+	"api_gateway/internal/problem"
+	"api_gateway/logger"
+	"context"
+	errorsv1 "github.com/deeelis/errors-protos/gen/go/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sseHeartbeatInterval = 15 * time.Second
+	wsPingInterval       = 30 * time.Second
+	wsPongWait           = 2 * wsPingInterval
+	wsWriteWait          = 10 * time.Second
+	wsMaxSubscriptions   = 100
+)
+
+// newUpgrader проверяет Origin только по списку allowed. Запрос без Origin
+// пришёл не из браузера и пропускается: подделать чужой Origin может только
+// браузер жертвы, а он заголовок отправляет всегда. Подпротокол с токеном
+// подтверждается без самого токена, см. middleware_controller.WSAuthProtocol.
+func newUpgrader(allowed []string) websocket.Upgrader {
+	return websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		Subprotocols:    []string{middleware_controller.WSAuthProtocol},
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				return true
+			}
+			for _, o := range allowed {
+				if strings.EqualFold(origin, strings.TrimSuffix(o, "/")) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+type wsClientMessage struct {
+	Action    string `json:"action"`
+	ContentID string `json:"content_id"`
+}
+
+func (c *ContentController) StreamContentEvents(ctx *gin.Context) {
+	const op = "http_controllers.ContentController.StreamContentEvents"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+	)
+	userID, _ := ctx.Get("userID")
+	contentID := ctx.Param("id")
+	log = log.With(
+		slog.String("user_id", userID.(string)),
+		slog.String("content_id", contentID),
+	)
+
+	log.Info("handling content events request")
+
+	events, err := c.contentUC.WatchContent(ctx.Request.Context(), userID.(string), contentID)
+	if err != nil {
+		log.Error("failed to watch content", logger.Err(err))
+
+		problem.Error(ctx, err)
+		return
+	}
+
+	// поток живёт дольше, чем WriteTimeout HTTP-сервера
+	if err := http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn("failed to reset write deadline", logger.Err(err))
+	}
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+
+	present := presenter(ctx)
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				log.Info("content event stream ended")
+				return false
+			}
+			ctx.SSEvent("status", present.ContentEvent(event))
+			if event.IsTerminal() {
+				log.Info("content reached terminal state",
+					slog.String("status", event.Status))
+				return false
+			}
+			return true
+		case <-heartbeat.C:
+			ctx.SSEvent("heartbeat", present.Heartbeat(time.Now()))
+			return true
+		}
+	})
+}
+
+func (c *ContentController) StreamContentEventsWS(ctx *gin.Context) {
+	const op = "http_controllers.ContentController.StreamContentEventsWS"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+	)
+	userID, _ := ctx.Get("userID")
+	log = log.With(slog.String("user_id", userID.(string)))
+
+	conn, err := c.upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		log.Warn("websocket upgrade failed", logger.Err(err))
+		return
+	}
+	defer conn.Close()
+
+	log.Info("websocket connection established")
+
+	session := &wsSession{
+		conn:    conn,
+		uc:      c,
+		present: presenter(ctx),
+		userID:  userID.(string),
+		log:     log,
+		send:    make(chan any, 16),
+		watches: make(map[string]*wsWatch),
+	}
+
+	sessionCtx, cancel := context.WithCancel(ctx.Request.Context())
+	defer cancel()
+
+	go session.writeLoop(sessionCtx, cancel)
+
+	for _, id := range strings.Split(ctx.Query("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			session.subscribe(sessionCtx, id)
+		}
+	}
+
+	session.readLoop(sessionCtx)
+	session.unsubscribeAll()
+
+	log.Info("websocket connection closed")
+}
+
+type wsSession struct {
+	conn    *websocket.Conn
+	uc      *ContentController
+	present Presenter
+	userID  string
+	log     *slog.Logger
+	send    chan any
+
+	mu      sync.Mutex
+	watches map[string]*wsWatch
+}
+
+type wsWatch struct {
+	cancel context.CancelFunc
+}
+
+func (s *wsSession) readLoop(ctx context.Context) {
+	s.conn.SetReadLimit(4096)
+	_ = s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		var msg wsClientMessage
+		if err := s.conn.ReadJSON(&msg); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				s.log.Warn("websocket read failed", logger.Err(err))
+			}
+			return
+		}
+
+		switch msg.Action {
+		case "subscribe":
+			s.subscribe(ctx, msg.ContentID)
+		case "unsubscribe":
+			s.unsubscribe(msg.ContentID)
+		default:
+			s.push(ctx, s.present.StreamError(msg.ContentID, "unknown action"))
+		}
+	}
+}
+
+func (s *wsSession) writeLoop(ctx context.Context, cancel context.CancelFunc) {
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = s.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(wsWriteWait))
+			return
+		case msg := <-s.send:
+			_ = s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := s.conn.WriteJSON(msg); err != nil {
+				s.log.Warn("websocket write failed", logger.Err(err))
+				return
+			}
+		case <-ping.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				s.log.Warn("websocket ping failed", logger.Err(err))
+				return
+			}
+		}
+	}
+}
+
+func (s *wsSession) subscribe(ctx context.Context, contentID string) {
+	if contentID == "" {
+		s.push(ctx, s.present.StreamError("", "content id is required"))
+		return
+	}
+
+	s.mu.Lock()
+	if _, ok := s.watches[contentID]; ok {
+		s.mu.Unlock()
+		return
+	}
+	if len(s.watches) >= wsMaxSubscriptions {
+		s.mu.Unlock()
+		s.push(ctx, s.present.StreamError(contentID, "too many subscriptions"))
+		return
+	}
+	watchCtx, cancel := context.WithCancel(ctx)
+	watch := &wsWatch{cancel: cancel}
+	s.watches[contentID] = watch
+	s.mu.Unlock()
+
+	go func() {
+		defer s.release(contentID, watch)
+
+		events, err := s.uc.contentUC.WatchContent(watchCtx, s.userID, contentID)
+		if err != nil {
+			message := "internal server error"
+			if errors.CodeOf(err) == errorsv1.ErrorCode_CONTENT_NOT_FOUND {
+				message = "content not found"
+			}
+			s.push(ctx, s.present.StreamError(contentID, message))
+			return
+		}
+
+		for event := range events {
+			s.push(ctx, s.present.StreamEvent(contentID, event))
+			if event.IsTerminal() {
+				return
+			}
+		}
+	}()
+}
+
+func (s *wsSession) unsubscribe(contentID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if watch, ok := s.watches[contentID]; ok {
+		watch.cancel()
+		delete(s.watches, contentID)
+	}
+}
+
+// release снимает подписку, только если её не успели заменить повторным subscribe.
+func (s *wsSession) release(contentID string, watch *wsWatch) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	watch.cancel()
+	if s.watches[contentID] == watch {
+		delete(s.watches, contentID)
+	}
+}
+
+func (s *wsSession) unsubscribeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, watch := range s.watches {
+		watch.cancel()
+		delete(s.watches, id)
+	}
+}
+
+func (s *wsSession) push(ctx context.Context, msg any) {
+	select {
+	case s.send <- msg:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,30 @@
+package http_controllers
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUpgraderCheckOrigin(t *testing.T) {
+	upgrader := newUpgrader([]string{"http://localhost:3000/"})
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"", true},
+		{"http://localhost:3000", true},
+		// тот же хост, но не из списка
+		{"http://gateway.example", false},
+		{"http://evil.example", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "http://gateway.example/v1/content/events", nil)
+		if tt.origin != "" {
+			r.Header.Set("Origin", tt.origin)
+		}
+		if got := upgrader.CheckOrigin(r); got != tt.want {
+			t.Errorf("CheckOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
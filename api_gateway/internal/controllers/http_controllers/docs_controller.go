@@ -0,0 +1,49 @@
+package http_controllers
+
+import (
+	"api_gateway/internal/openapi"
+	"api_gateway/logger"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+)
+
+type DocsController struct {
+	spec   []byte
+	assets http.FileSystem
+	log    *slog.Logger
+}
+
+func NewDocsController(log *slog.Logger) (*DocsController, error) {
+	const op = "http_controllers.NewDocsController"
+	log = log.With(slog.String("op", op))
+	log.Info("initializing docs controller")
+
+	doc, err := openapi.Load()
+	if err != nil {
+		log.Error("failed to load openapi spec", logger.Err(err))
+		return nil, err
+	}
+
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		log.Error("failed to marshal openapi spec", logger.Err(err))
+		return nil, err
+	}
+
+	log.Info("docs controller initialized successfully")
+	return &DocsController{spec: spec, assets: http.FS(openapi.DocsAssets()), log: log}, nil
+}
+
+func (c *DocsController) Spec(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "application/json", c.spec)
+}
+
+func (c *DocsController) UI(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", openapi.DocsPage)
+}
+
+func (c *DocsController) Asset(ctx *gin.Context) {
+	ctx.FileFromFS(ctx.Param("filepath"), c.assets)
+}
@@ -0,0 +1,55 @@
+package http_controllers
+
+import (
+	"api_gateway/internal/config"
+	auth_client "api_gateway/internal/grpc/auth_client"
+	"api_gateway/internal/grpc/storage_client"
+	"api_gateway/internal/kafka"
+	"api_gateway/logger"
+	"context"
+	"github.com/deeelis/platform/health"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+)
+
+// HealthController отдаёт пробы /healthz и /readyz. Для проверок он держит
+// собственные соединения с auth и storage, чтобы не зависеть от клиентов контроллеров.
+type HealthController struct {
+	checker *health.Checker
+	log     *slog.Logger
+}
+
+func NewHealthController(cfg *config.Config, log *slog.Logger) (*HealthController, error) {
+	const op = "http_controllers.NewHealthController"
+	log = log.With(slog.String("op", op))
+	log.Info("initializing health controller")
+
+	authClient, err := auth_client.NewAuthClient(cfg.Auth, log)
+	if err != nil {
+		log.Error("failed to create auth client", logger.Err(err))
+		return nil, err
+	}
+	storageClient, err := storage_client.NewStorageClient(cfg.Storage, log)
+	if err != nil {
+		log.Error("failed to create storage client", logger.Err(err))
+		return nil, err
+	}
+
+	checker := health.NewChecker(cfg.Health.Timeout, log)
+	checker.Register("auth_service", authClient.Ping)
+	checker.Register("storage_service", storageClient.Ping)
+	checker.Register("kafka", func(ctx context.Context) error {
+		return kafka.Ping(ctx, cfg.Kafka.Brokers)
+	})
+
+	log.Info("health controller initialized successfully")
+	return &HealthController{checker: checker, log: log}, nil
+}
+
+func (c *HealthController) Liveness(ctx *gin.Context) {
+	c.checker.LivenessHandler().ServeHTTP(ctx.Writer, ctx.Request)
+}
+
+func (c *HealthController) Readiness(ctx *gin.Context) {
+	c.checker.ReadinessHandler().ServeHTTP(ctx.Writer, ctx.Request)
+}
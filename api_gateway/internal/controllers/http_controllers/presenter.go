@@ -0,0 +1,44 @@
+package http_controllers
+
+import (
+	"api_gateway/internal/domain/models"
+	"api_gateway/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+const presenterKey = "presenter"
+
+// Presenter переводит доменные модели в тела ответов одной версии API.
+// Контроллеры общие для всех версий, а Presenter каждая версия ставит
+// на свои маршруты через Present.
+type Presenter interface {
+	Tokens(tokens *models.TokenDetails) any
+	Accepted(content *models.Content) any
+	BatchResult(results []models.BatchItemResult) any
+	Content(status *models.ContentStatus) any
+	ContentPage(page *models.ContentPage) any
+	Deleted(contentID string, purgeAfter time.Time) any
+	Restored(contentID string) any
+	ContentEvent(event *models.ContentEvent) any
+	Heartbeat(t time.Time) any
+	StreamEvent(contentID string, event *models.ContentEvent) any
+	StreamError(contentID string, message string) any
+	Usage(quota *ratelimit.QuotaResult, limits map[string]ratelimit.GroupUsage) any
+	Webhook(webhook *models.Webhook) any
+	WebhookList(webhooks []*models.Webhook) any
+	Delivery(delivery *models.WebhookDelivery) any
+	DeliveryList(deliveries []*models.WebhookDelivery) any
+}
+
+// Present подключает Presenter версии к маршрутам группы
+func Present(p Presenter) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set(presenterKey, p)
+		ctx.Next()
+	}
+}
+
+func presenter(ctx *gin.Context) Presenter {
+	return ctx.MustGet(presenterKey).(Presenter)
+}
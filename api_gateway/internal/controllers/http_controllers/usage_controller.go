@@ -0,0 +1,87 @@
+package http_controllers
+
+import (
+	"api_gateway/internal/config"
+	"api_gateway/internal/problem"
+	"api_gateway/internal/ratelimit"
+	"api_gateway/internal/ratelimit/redis_ratelimit"
+	"api_gateway/logger"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+)
+
+// группы, лимиты которых считаются по пользователю
+var userRateLimitGroups = []string{"api", "upload"}
+
+type UsageController struct {
+	cfg     *config.Config
+	quota   ratelimit.Quota
+	limiter ratelimit.Limiter
+	log     *slog.Logger
+}
+
+func NewUsageController(cfg *config.Config, log *slog.Logger) (*UsageController, error) {
+	const op = "http_controllers.NewUsageController"
+	log = log.With(slog.String("op", op))
+	log.Info("initializing usage controller")
+
+	limiter, err := redis_ratelimit.NewRedisRateLimiter(cfg, log)
+	if err != nil {
+		log.Error("failed to create rate limiter", logger.Err(err))
+		return nil, err
+	}
+
+	log.Info("usage controller initialized successfully")
+	return NewUsageControllerWithLimiter(cfg, limiter, limiter, log), nil
+}
+
+// NewUsageControllerWithLimiter собирает контроллер поверх готовых квоты и лимитера
+func NewUsageControllerWithLimiter(cfg *config.Config, quota ratelimit.Quota, limiter ratelimit.Limiter, log *slog.Logger) *UsageController {
+	return &UsageController{
+		cfg:     cfg,
+		quota:   quota,
+		limiter: limiter,
+		log:     log,
+	}
+}
+
+func (c *UsageController) GetUsage(ctx *gin.Context) {
+	const op = "http_controllers.UsageController.GetUsage"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+	)
+	userID, _ := ctx.Get("userID")
+	log = log.With(slog.String("user_id", userID.(string)))
+
+	log.Info("handling usage request")
+
+	quota, err := c.quota.Usage(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		log.Error("failed to get upload usage", logger.Err(err))
+		problem.Error(ctx, err)
+		return
+	}
+
+	limits := make(map[string]ratelimit.GroupUsage, len(userRateLimitGroups))
+	for _, group := range userRateLimitGroups {
+		limit, ok := ratelimit.LimitFor(c.cfg.RateLimit, group)
+		if !ok || !c.cfg.RateLimit.Enabled {
+			continue
+		}
+		res, err := c.limiter.Peek(ctx.Request.Context(), group+":user:"+userID.(string), limit)
+		if err != nil {
+			log.Error("failed to get rate limit state",
+				slog.String("group", group),
+				logger.Err(err))
+			problem.Error(ctx, err)
+			return
+		}
+		limits[group] = ratelimit.GroupUsage{Limit: limit, Result: res}
+	}
+
+	log.Info("usage request completed successfully")
+	ctx.JSON(http.StatusOK, presenter(ctx).Usage(quota, limits))
+}
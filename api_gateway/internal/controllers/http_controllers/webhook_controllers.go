@@ -0,0 +1,192 @@
+package http_controllers
+
+import (
+	"api_gateway/internal/config"
+	"api_gateway/internal/problem"
+	"api_gateway/internal/usecases"
+	"api_gateway/internal/usecases/webhook_usecase"
+	"api_gateway/logger"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+)
+
+type WebhookController struct {
+	cfg       *config.Config
+	webhookUC usecases.WebhookUsecase
+	log       *slog.Logger
+}
+
+func NewWebhookController(cfg *config.Config, log *slog.Logger) (*WebhookController, error) {
+	const op = "http_controllers.NewWebhookController"
+	log = log.With(slog.String("op", op))
+	log.Info("initializing webhook controller")
+
+	uc, err := webhook_usecase.NewWebhookUseCase(cfg, log)
+	if err != nil {
+		log.Error("failed to create webhook use case", logger.Err(err))
+		return nil, err
+	}
+
+	log.Info("webhook controller initialized successfully")
+	return NewWebhookControllerWithUsecase(cfg, uc, log), nil
+}
+
+// NewWebhookControllerWithUsecase собирает контроллер поверх готового usecase
+func NewWebhookControllerWithUsecase(cfg *config.Config, uc usecases.WebhookUsecase, log *slog.Logger) *WebhookController {
+	return &WebhookController{
+		cfg:       cfg,
+		webhookUC: uc,
+		log:       log,
+	}
+}
+
+func (c *WebhookController) CreateWebhook(ctx *gin.Context) {
+	const op = "http_controllers.WebhookController.CreateWebhook"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+	)
+	userID, _ := ctx.Get("userID")
+	log = log.With(slog.String("user_id", userID.(string)))
+
+	log.Info("handling create webhook request")
+
+	var req struct {
+		URL    string `json:"url" binding:"required"`
+		Secret string `json:"secret"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn("invalid request body", logger.Err(err))
+		problem.BadRequest(ctx, "invalid request body")
+		return
+	}
+
+	webhook, err := c.webhookUC.CreateWebhook(ctx, userID.(string), req.URL, req.Secret)
+	if err != nil {
+		log.Error("failed to create webhook", logger.Err(err))
+		problem.Error(ctx, err)
+		return
+	}
+
+	log.Info("create webhook request completed successfully",
+		slog.String("webhook_id", webhook.ID))
+	// секрет отдаётся только при создании
+	ctx.JSON(http.StatusCreated, presenter(ctx).Webhook(webhook))
+}
+
+func (c *WebhookController) ListWebhooks(ctx *gin.Context) {
+	const op = "http_controllers.WebhookController.ListWebhooks"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+	)
+	userID, _ := ctx.Get("userID")
+	log = log.With(slog.String("user_id", userID.(string)))
+
+	log.Info("handling list webhooks request")
+
+	webhooks, err := c.webhookUC.ListWebhooks(ctx, userID.(string))
+	if err != nil {
+		log.Error("failed to list webhooks", logger.Err(err))
+		problem.Error(ctx, err)
+		return
+	}
+
+	log.Info("list webhooks request completed successfully")
+	ctx.JSON(http.StatusOK, presenter(ctx).WebhookList(webhooks))
+}
+
+func (c *WebhookController) DeleteWebhook(ctx *gin.Context) {
+	const op = "http_controllers.WebhookController.DeleteWebhook"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+	)
+	userID, _ := ctx.Get("userID")
+	webhookID := ctx.Param("id")
+	log = log.With(
+		slog.String("user_id", userID.(string)),
+		slog.String("webhook_id", webhookID),
+	)
+
+	log.Info("handling delete webhook request")
+
+	if err := c.webhookUC.DeleteWebhook(ctx, userID.(string), webhookID); err != nil {
+		log.Error("failed to delete webhook", logger.Err(err))
+		problem.Error(ctx, err)
+		return
+	}
+
+	log.Info("delete webhook request completed successfully")
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *WebhookController) ListDeliveries(ctx *gin.Context) {
+	const op = "http_controllers.WebhookController.ListDeliveries"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+	)
+	userID, _ := ctx.Get("userID")
+	webhookID := ctx.Param("id")
+	log = log.With(
+		slog.String("user_id", userID.(string)),
+		slog.String("webhook_id", webhookID),
+	)
+
+	log.Info("handling list webhook deliveries request")
+
+	var query struct {
+		Status string `form:"status"`
+		Limit  int    `form:"limit"`
+	}
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		log.Warn("invalid query parameters", logger.Err(err))
+		problem.BadRequest(ctx, "invalid query parameters")
+		return
+	}
+
+	deliveries, err := c.webhookUC.ListDeliveries(ctx, userID.(string), webhookID, query.Status, query.Limit)
+	if err != nil {
+		log.Error("failed to list webhook deliveries", logger.Err(err))
+		problem.Error(ctx, err)
+		return
+	}
+
+	log.Info("list webhook deliveries request completed successfully")
+	ctx.JSON(http.StatusOK, presenter(ctx).DeliveryList(deliveries))
+}
+
+func (c *WebhookController) Redeliver(ctx *gin.Context) {
+	const op = "http_controllers.WebhookController.Redeliver"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.FullPath()),
+	)
+	userID, _ := ctx.Get("userID")
+	webhookID := ctx.Param("id")
+	deliveryID := ctx.Param("delivery_id")
+	log = log.With(
+		slog.String("user_id", userID.(string)),
+		slog.String("webhook_id", webhookID),
+		slog.String("delivery_id", deliveryID),
+	)
+
+	log.Info("handling webhook redelivery request")
+
+	delivery, err := c.webhookUC.Redeliver(ctx, userID.(string), webhookID, deliveryID)
+	if err != nil {
+		log.Error("failed to redeliver webhook", logger.Err(err))
+		problem.Error(ctx, err)
+		return
+	}
+
+	log.Info("webhook redelivery request completed successfully")
+	ctx.JSON(http.StatusAccepted, presenter(ctx).Delivery(delivery))
+}
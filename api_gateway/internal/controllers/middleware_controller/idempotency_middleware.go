@@ -0,0 +1,225 @@
+package middleware_controller
+
+import (
+	"api_gateway/internal/config"
+	"api_gateway/internal/idempotency"
+	"api_gateway/internal/idempotency/redis_idempotency"
+	"api_gateway/internal/problem"
+	"api_gateway/logger"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	errorsv1 "github.com/deeelis/errors-protos/gen/go/errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeader  = "Idempotency-Key"
+	idempotentReplayed    = "Idempotent-Replayed"
+	maxIdempotencyKeySize = 255
+	maxMultipartMemory    = 32 << 20
+)
+
+type Idempotency struct {
+	cfg   *config.IdempotencyConfig
+	store idempotency.Store
+	log   *slog.Logger
+}
+
+func NewIdempotency(cfg *config.Config, log *slog.Logger) (*Idempotency, error) {
+	const op = "middleware_controller.NewIdempotency"
+	log = log.With(slog.String("op", op))
+	log.Info("initializing idempotency middleware",
+		slog.Duration("ttl", cfg.Idempotency.TTL))
+
+	store, err := redis_idempotency.NewRedisStore(cfg.Redis, log)
+	if err != nil {
+		log.Error("failed to create idempotency store", logger.Err(err))
+		return nil, err
+	}
+
+	return &Idempotency{
+		cfg:   cfg.Idempotency,
+		store: store,
+		log:   log,
+	}, nil
+}
+
+// Middleware сохраняет первый ответ на запрос с заголовком Idempotency-Key
+// и воспроизводит его на повторы с тем же телом. Ключи разделены по пользователям.
+func (i *Idempotency) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			ctx.Next()
+			return
+		}
+
+		log := i.log.With(
+			slog.String("path", ctx.FullPath()),
+			slog.String("request_id", ctx.GetString("request_id")),
+			slog.String("idempotency_key", key),
+		)
+
+		if len(key) > maxIdempotencyKeySize {
+			problem.BadRequest(ctx, "idempotency key is too long")
+			return
+		}
+
+		fingerprint, err := requestFingerprint(ctx.Request)
+		if err != nil {
+			log.Warn("failed to read request body", logger.Err(err))
+			problem.InvalidBody(ctx, err, "invalid request")
+			return
+		}
+
+		storeKey := ctx.GetString("userID") + ":" + ctx.Request.Method + ":" + ctx.FullPath() + ":" + key
+		rec := &idempotency.Record{
+			Token:       newToken(),
+			Fingerprint: fingerprint,
+		}
+
+		acquired, existing, err := i.store.Lock(ctx.Request.Context(), storeKey, rec, i.cfg.LockTimeout)
+		if err != nil {
+			log.Error("idempotency store unavailable", logger.Err(err))
+			problem.Write(ctx, problem.New(errorsv1.ErrorCode_UNAVAILABLE, "idempotency store unavailable"))
+			return
+		}
+
+		if !acquired {
+			switch {
+			case existing.Fingerprint != fingerprint:
+				log.Warn("idempotency key reused with different request")
+				problem.Write(ctx, problem.New(errorsv1.ErrorCode_IDEMPOTENCY_KEY_MISMATCH,
+					"idempotency key was used with a different request"))
+			case !existing.Completed:
+				log.Info("request with the same idempotency key is in progress")
+				ctx.Header("Retry-After", "1")
+				problem.Write(ctx, problem.New(errorsv1.ErrorCode_IDEMPOTENCY_KEY_IN_USE,
+					"request with this idempotency key is in progress"))
+			default:
+				log.Info("replaying stored response",
+					slog.Int("status", existing.StatusCode))
+				ctx.Header(idempotentReplayed, "true")
+				ctx.Data(existing.StatusCode, existing.ContentType, existing.Body)
+				ctx.Abort()
+			}
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+
+		ctx.Next()
+
+		storeCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		status := recorder.Status()
+		// ошибки сервера и лимиты не фиксируются: такой запрос можно повторить
+		if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+			if err := i.store.Release(storeCtx, storeKey, rec.Token); err != nil {
+				log.Warn("failed to release idempotency key", logger.Err(err))
+			}
+			return
+		}
+
+		rec.Completed = true
+		rec.StatusCode = status
+		rec.ContentType = recorder.Header().Get("Content-Type")
+		rec.Body = recorder.body.Bytes()
+		if err := i.store.Complete(storeCtx, storeKey, rec, i.cfg.TTL); err != nil {
+			log.Warn("failed to store idempotent response", logger.Err(err))
+		}
+	}
+}
+
+// requestFingerprint хеширует тело запроса. Multipart-формы хешируются по
+// содержимому частей, так как граница между повторами может меняться.
+func requestFingerprint(r *http.Request) (string, error) {
+	h := sha256.New()
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		if err := r.ParseMultipartForm(maxMultipartMemory); err != nil {
+			return "", err
+		}
+
+		names := make([]string, 0, len(r.MultipartForm.Value))
+		for name := range r.MultipartForm.Value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, v := range r.MultipartForm.Value[name] {
+				writeField(h, "v:"+name, []byte(v))
+			}
+		}
+
+		names = names[:0]
+		for name := range r.MultipartForm.File {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, fh := range r.MultipartForm.File[name] {
+				f, err := fh.Open()
+				if err != nil {
+					return "", err
+				}
+				data, err := io.ReadAll(f)
+				f.Close()
+				if err != nil {
+					return "", err
+				}
+				writeField(h, "f:"+name+":"+fh.Filename, data)
+			}
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	writeField(h, mediaType, body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func writeField(w io.Writer, name string, data []byte) {
+	io.WriteString(w, name)
+	io.WriteString(w, ":"+strconv.Itoa(len(data))+":")
+	w.Write(data)
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,25 @@
+package middleware_controller
+
+import (
+	"api_gateway/internal/metrics"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+// Metrics учитывает задержку запросов по шаблону маршрута и статусу.
+// Стоит перед gin.Recovery, чтобы паника попала в метрики как 500.
+func Metrics() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		done := metrics.TrackInFlight()
+		defer done()
+
+		startTime := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveHTTP(ctx.Request.Method, route, ctx.Writer.Status(), startTime)
+	}
+}
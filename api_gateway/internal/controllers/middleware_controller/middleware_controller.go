@@ -2,23 +2,42 @@ package middleware_controller
 
 import (
 	"api_gateway/internal/config"
+	"api_gateway/internal/domain/errors"
+	"api_gateway/internal/problem"
+	"api_gateway/internal/tokenauth"
+	"api_gateway/internal/tokenauth/redis_revocation"
 	"api_gateway/internal/usecases/auth_usecase"
 	"api_gateway/logger"
 	"context"
+	"fmt"
+	errorsv1 "github.com/deeelis/errors-protos/gen/go/errors"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	"log/slog"
 	"net/http"
+	"os"
 	"time"
 )
 
-func AuthMiddleware(cfg *config.AuthConfig, log *slog.Logger) (gin.HandlerFunc, error) {
+const (
+	VerificationLocal  = "local"
+	VerificationRemote = "remote"
+)
+
+// WSAuthProtocol — подпротокол WebSocket, следом за которым браузер передаёт
+// токен: new WebSocket(url, ["bearer", token]). Заголовок Authorization браузер
+// при открытии WebSocket отправить не может.
+const WSAuthProtocol = "bearer"
+
+func AuthMiddleware(cfg *config.Config, log *slog.Logger) (gin.HandlerFunc, error) {
 	const op = "middleware_controller.AuthMiddleware"
 	log = log.With(slog.String("op", op))
-	log.Info("initializing auth middleware")
+	log.Info("initializing auth middleware",
+		slog.String("verification", cfg.Auth.Verification.Mode))
 
-	uc, err := auth_usecase.NewAuthUsecase(cfg, log)
+	verifier, err := newTokenVerifier(cfg, log)
 	if err != nil {
-		log.Error("failed to create auth usecase", logger.Err(err))
+		log.Error("failed to create token verifier", logger.Err(err))
 		return nil, err
 	}
 
@@ -36,28 +55,27 @@ func AuthMiddleware(cfg *config.AuthConfig, log *slog.Logger) (gin.HandlerFunc,
 
 		log.Info("auth middleware processing request")
 
-		token := ctx.GetHeader("Authorization")
+		token := bearerToken(ctx.Request)
 		if token == "" {
 			log.Warn("missing authorization header",
 				slog.String("client_ip", ctx.ClientIP()))
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "authorization header required",
-			})
+			problem.Write(ctx, problem.New(errorsv1.ErrorCode_UNAUTHENTICATED, "authorization header required"))
 			return
 		}
 
-		validateCtx, cancel := context.WithTimeout(ctx.Request.Context(), 2*time.Second)
-		defer cancel()
-
-		userID, err := uc.ValidateToken(validateCtx, token)
+		userID, err := verifier.ValidateToken(ctx.Request.Context(), token)
 		if err != nil {
 			log.Warn("token validation failed",
 				logger.Err(err),
 				slog.String("client_ip", ctx.ClientIP()),
 				slog.Duration("duration", time.Since(startTime)))
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "invalid token",
-			})
+			// недоступность auth_service — не повод считать токен невалидным
+			switch errors.CodeOf(err) {
+			case errorsv1.ErrorCode_UNAVAILABLE, errorsv1.ErrorCode_INTERNAL:
+				problem.Error(ctx, err)
+			default:
+				problem.Write(ctx, problem.New(errorsv1.ErrorCode_TOKEN_INVALID, "invalid token"))
+			}
 			return
 		}
 
@@ -70,3 +88,69 @@ func AuthMiddleware(cfg *config.AuthConfig, log *slog.Logger) (gin.HandlerFunc,
 		ctx.Next()
 	}, nil
 }
+
+// newTokenVerifier собирает проверку токенов: локальную по JWKS auth_service или
+// через сам auth_service. Лента отзывов подключается в обоих режимах.
+func newTokenVerifier(cfg *config.Config, log *slog.Logger) (*tokenauth.Verifier, error) {
+	vcfg := cfg.Auth.Verification
+
+	var backend tokenauth.Backend
+	switch vcfg.Mode {
+	case VerificationLocal:
+		uc, err := auth_usecase.NewAuthUsecase(cfg.Auth, log)
+		if err != nil {
+			return nil, err
+		}
+		keys := tokenauth.NewKeySet(uc, log)
+		go keys.Run(context.Background(), vcfg.JWKSRefresh)
+
+		// HS256-токены выпускались до перехода на EdDSA и принимаются, только
+		// пока задан общий ключ
+		methods := []string{"EdDSA"}
+		var secret []byte
+		if vcfg.SecretEnv != "" {
+			secret = []byte(os.Getenv(vcfg.SecretEnv))
+		}
+		if len(secret) > 0 {
+			methods = append(methods, "HS256")
+		}
+		backend = tokenauth.NewLocalBackend(keys.Keyfunc(secret), methods, vcfg.Leeway)
+	case VerificationRemote, "":
+		uc, err := auth_usecase.NewAuthUsecase(cfg.Auth, log)
+		if err != nil {
+			return nil, err
+		}
+		backend = tokenauth.NewRemoteBackend(uc)
+	default:
+		return nil, fmt.Errorf("unknown token verification mode %q", vcfg.Mode)
+	}
+
+	// без ленты кеш проверенных токенов пропускал бы отозванные до их истечения
+	if vcfg.RevocationStream == "" {
+		return nil, fmt.Errorf("auth.verification.revocation_stream is required")
+	}
+	store, err := redis_revocation.NewStore(cfg.Redis, vcfg.RevocationStream, log)
+	if err != nil {
+		return nil, err
+	}
+	revocations := tokenauth.NewRevocations(store, log)
+	go revocations.Run(context.Background())
+
+	return tokenauth.NewVerifier(backend, vcfg.CacheSize, revocations, log), nil
+}
+
+func bearerToken(r *http.Request) string {
+	if token := r.Header.Get("Authorization"); token != "" {
+		return token
+	}
+	if !websocket.IsWebSocketUpgrade(r) {
+		return ""
+	}
+	protocols := websocket.Subprotocols(r)
+	for i := 0; i+1 < len(protocols); i++ {
+		if protocols[i] == WSAuthProtocol {
+			return protocols[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,37 @@
+package middleware_controller
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{"authorization header", map[string]string{"Authorization": "Bearer header-token"}, "Bearer header-token"},
+		{"websocket subprotocol", map[string]string{
+			"Connection":             "Upgrade",
+			"Upgrade":                "websocket",
+			"Sec-WebSocket-Protocol": "bearer, ws-token",
+		}, "ws-token"},
+		{"subprotocol without upgrade", map[string]string{"Sec-WebSocket-Protocol": "bearer, ws-token"}, ""},
+		{"bearer without token", map[string]string{
+			"Connection":             "Upgrade",
+			"Upgrade":                "websocket",
+			"Sec-WebSocket-Protocol": "bearer",
+		}, ""},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/v1/content/events", nil)
+		for k, v := range tt.headers {
+			r.Header.Set(k, v)
+		}
+		if got := bearerToken(r); got != tt.want {
+			t.Errorf("%s: bearerToken = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
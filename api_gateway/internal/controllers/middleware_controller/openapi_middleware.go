@@ -0,0 +1,173 @@
+package middleware_controller
+
+import (
+	"api_gateway/internal/config"
+	"api_gateway/internal/openapi"
+	"api_gateway/internal/problem"
+	"api_gateway/logger"
+	"bytes"
+	"context"
+	goerrors "errors"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type OpenAPIValidator struct {
+	cfg    *config.OpenAPIConfig
+	router routers.Router
+	log    *slog.Logger
+}
+
+func NewOpenAPIValidator(cfg *config.Config, log *slog.Logger) (*OpenAPIValidator, error) {
+	const op = "middleware_controller.NewOpenAPIValidator"
+	log = log.With(slog.String("op", op))
+	log.Info("initializing openapi validator",
+		slog.Bool("validate_requests", cfg.OpenAPI.ValidateRequests),
+		slog.Bool("validate_responses", cfg.OpenAPI.ValidateResponses))
+
+	doc, err := openapi.Load()
+	if err != nil {
+		log.Error("failed to load openapi spec", logger.Err(err))
+		return nil, err
+	}
+	// сопоставляем только путь: хост зависит от того, за каким прокси стоит шлюз,
+	// а префикс версии снимает Middleware
+	doc.Servers = nil
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		log.Error("failed to build openapi router", logger.Err(err))
+		return nil, err
+	}
+
+	return &OpenAPIValidator{
+		cfg:    cfg.OpenAPI,
+		router: router,
+		log:    log,
+	}, nil
+}
+
+// Middleware проверяет запрос по спецификации до вызова обработчика.
+// Ответы проверяются только в журнал: к этому моменту они уже отправлены,
+// так что расхождение обработчика со спецификацией видно в логах.
+// basePath — префикс версии, под которым смонтированы маршруты группы.
+func (v *OpenAPIValidator) Middleware(basePath string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !v.cfg.ValidateRequests && !v.cfg.ValidateResponses {
+			ctx.Next()
+			return
+		}
+
+		route, pathParams, err := v.findRoute(ctx.Request, basePath)
+		if err != nil {
+			// маршрута нет в спецификации — решает gin
+			ctx.Next()
+			return
+		}
+
+		log := v.log.With(
+			slog.String("method", ctx.Request.Method),
+			slog.String("path", route.Path),
+			slog.String("request_id", ctx.GetString("request_id")),
+		)
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    ctx.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				// аутентификацию выполняет AuthMiddleware
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+
+		if v.cfg.ValidateRequests {
+			if err := openapi3filter.ValidateRequest(ctx.Request.Context(), input); err != nil {
+				log.Warn("request does not match openapi spec", logger.Err(err))
+				problem.InvalidBody(ctx, err, requestErrorDetails(err))
+				return
+			}
+		}
+
+		if !v.cfg.ValidateResponses || isStreaming(route.Operation) {
+			ctx.Next()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+
+		ctx.Next()
+
+		responseInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 recorder.Status(),
+			Header:                 recorder.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+			Options: &openapi3filter.Options{
+				IncludeResponseStatus: true,
+			},
+		}
+		if err := openapi3filter.ValidateResponse(context.Background(), responseInput); err != nil {
+			log.Error("response does not match openapi spec",
+				slog.Int("status", recorder.Status()),
+				logger.Err(err))
+		}
+	}
+}
+
+// findRoute ищет операцию по пути без префикса версии: в спецификации он вынесен в servers
+func (v *OpenAPIValidator) findRoute(req *http.Request, basePath string) (*routers.Route, map[string]string, error) {
+	if basePath == "" {
+		return v.router.FindRoute(req)
+	}
+
+	trimmed := *req
+	u := *req.URL
+	u.Path = strings.TrimPrefix(u.Path, basePath)
+	u.RawPath = ""
+	trimmed.URL = &u
+	return v.router.FindRoute(&trimmed)
+}
+
+// isStreaming сообщает, что операция отдаёт поток (SSE, WebSocket или файл),
+// такие ответы не буферизуются и не проверяются.
+func isStreaming(op *openapi3.Operation) bool {
+	if op.Responses.Status(http.StatusSwitchingProtocols) != nil {
+		return true
+	}
+	ok := op.Responses.Status(http.StatusOK)
+	if ok == nil || ok.Value == nil {
+		return false
+	}
+	return ok.Value.Content.Get("text/event-stream") != nil || ok.Value.Content.Get("image/*") != nil
+}
+
+func requestErrorDetails(err error) string {
+	reason := err.Error()
+
+	var reqErr *openapi3filter.RequestError
+	if goerrors.As(err, &reqErr) && reqErr.Reason != "" {
+		reason = reqErr.Reason
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if goerrors.As(err, &schemaErr) {
+		reason = schemaErr.Reason
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+			reason = strings.Join(pointer, ".") + ": " + reason
+		}
+	}
+
+	if reqErr != nil && reqErr.Parameter != nil {
+		return "parameter " + reqErr.Parameter.Name + ": " + reason
+	}
+	return reason
+}
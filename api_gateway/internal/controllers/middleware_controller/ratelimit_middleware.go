@@ -0,0 +1,106 @@
+package middleware_controller
+
+import (
+	"api_gateway/internal/config"
+	"api_gateway/internal/problem"
+	"api_gateway/internal/ratelimit"
+	"api_gateway/internal/ratelimit/redis_ratelimit"
+	"api_gateway/logger"
+	"context"
+	"fmt"
+	errorsv1 "github.com/deeelis/errors-protos/gen/go/errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+)
+
+// KeyFunc выбирает, по какому признаку считать запросы.
+type KeyFunc func(ctx *gin.Context) string
+
+func ByClientIP(ctx *gin.Context) string {
+	return "ip:" + ctx.ClientIP()
+}
+
+func ByUserID(ctx *gin.Context) string {
+	return "user:" + ctx.GetString("userID")
+}
+
+type RateLimiter struct {
+	cfg     *config.RateLimitConfig
+	limiter ratelimit.Limiter
+	log     *slog.Logger
+}
+
+func NewRateLimiter(cfg *config.Config, log *slog.Logger) (*RateLimiter, error) {
+	const op = "middleware_controller.NewRateLimiter"
+	log = log.With(slog.String("op", op))
+	log.Info("initializing rate limiter", slog.Bool("enabled", cfg.RateLimit.Enabled))
+
+	limiter, err := redis_ratelimit.NewRedisRateLimiter(cfg, log)
+	if err != nil {
+		log.Error("failed to create rate limiter", logger.Err(err))
+		return nil, err
+	}
+
+	return &RateLimiter{
+		cfg:     cfg.RateLimit,
+		limiter: limiter,
+		log:     log,
+	}, nil
+}
+
+// Limit ограничивает запросы группы маршрутов. При недоступности Redis
+// запрос пропускается: лимитер не должен ронять шлюз.
+func (r *RateLimiter) Limit(group string, key KeyFunc) gin.HandlerFunc {
+	limit, ok := ratelimit.LimitFor(r.cfg, group)
+	if !r.cfg.Enabled || !ok {
+		r.log.Warn("rate limiting disabled for group", slog.String("group", group))
+		return func(ctx *gin.Context) { ctx.Next() }
+	}
+
+	return func(ctx *gin.Context) {
+		log := r.log.With(
+			slog.String("group", group),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.String("request_id", ctx.GetString("request_id")),
+		)
+
+		limitCtx, cancel := context.WithTimeout(ctx.Request.Context(), 500*time.Millisecond)
+		defer cancel()
+
+		res, err := r.limiter.Allow(limitCtx, group+":"+key(ctx), limit)
+		if err != nil {
+			log.Error("rate limiter unavailable", logger.Err(err))
+			ctx.Next()
+			return
+		}
+
+		SetRateLimitHeaders(ctx, limit, res)
+		if !res.Allowed {
+			log.Warn("rate limit exceeded",
+				slog.String("client_ip", ctx.ClientIP()),
+				slog.Duration("retry_after", res.RetryAfter))
+			retryAfter := seconds(res.RetryAfter)
+			ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+			problem.Write(ctx, problem.New(errorsv1.ErrorCode_RATE_LIMITED, "rate limit exceeded").
+				With("retry_after", retryAfter))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func SetRateLimitHeaders(ctx *gin.Context, limit ratelimit.Limit, res *ratelimit.Result) {
+	ctx.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+	ctx.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	ctx.Header("RateLimit-Reset", strconv.Itoa(seconds(res.ResetAfter)))
+	ctx.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d;burst=%d",
+		limit.Requests, seconds(limit.Period), limit.Burst))
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
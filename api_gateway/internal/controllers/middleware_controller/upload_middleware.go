@@ -0,0 +1,57 @@
+package middleware_controller
+
+import (
+	"api_gateway/internal/config"
+	"api_gateway/internal/problem"
+	"api_gateway/internal/upload"
+	"api_gateway/logger"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+)
+
+type UploadLimits struct {
+	policies *upload.Policies
+	log      *slog.Logger
+}
+
+func NewUploadLimits(cfg *config.Config, log *slog.Logger) (*UploadLimits, error) {
+	const op = "middleware_controller.NewUploadLimits"
+	log = log.With(slog.String("op", op))
+
+	policies, err := upload.New(cfg.Upload)
+	if err != nil {
+		log.Error("invalid upload policy", logger.Err(err))
+		return nil, err
+	}
+
+	return &UploadLimits{
+		policies: policies,
+		log:      log,
+	}, nil
+}
+
+// Limit ограничивает тело запроса пределом из политики пользователя. Запрос
+// с заведомо большим Content-Length отклоняется сразу, остальные читаются через
+// http.MaxBytesReader, поэтому лишнее не буферизуется ни валидатором, ни обработчиком.
+// Должен стоять после AuthMiddleware и до всего, что читает тело.
+func (u *UploadLimits) Limit(kind upload.Kind) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		policy := u.policies.For(ctx.GetString("userID"))
+		limit := policy.BodyLimit(kind)
+
+		if ctx.Request.ContentLength > limit {
+			u.log.Warn("upload rejected by content length",
+				slog.String("kind", string(kind)),
+				slog.String("tier", policy.Tier),
+				slog.Int64("content_length", ctx.Request.ContentLength),
+				slog.Int64("limit", limit),
+				slog.String("request_id", ctx.GetString("request_id")))
+			problem.TooLarge(ctx, "request body too large", limit, "bytes")
+			return
+		}
+
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, limit)
+		ctx.Next()
+	}
+}
@@ -1,14 +1,24 @@
 package http_controllers
 
 import (
+	"api_gateway/internal/clientip"
 	"api_gateway/internal/config"
 	"api_gateway/internal/controllers/http_controllers"
 	"api_gateway/internal/controllers/middleware_controller"
+	"api_gateway/internal/problem"
 	"api_gateway/logger"
+	"fmt"
+	errorsv1 "github.com/deeelis/errors-protos/gen/go/errors"
+	"github.com/deeelis/platform/requestid"
+	"github.com/deeelis/platform/tracing"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"log/slog"
-	"math/rand"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -31,6 +41,32 @@ func NewRouter(cfg *config.Config, log *slog.Logger) (*gin.Engine, error) {
 
 	log.Debug("content controller initialized")
 
+	webhookController, err := http_controllers.NewWebhookController(cfg, log)
+	if err != nil {
+		log.Error("failed to create webhook controller", logger.Err(err))
+		return nil, err
+	}
+
+	log.Debug("webhook controller initialized")
+
+	usageController, err := http_controllers.NewUsageController(cfg, log)
+	if err != nil {
+		log.Error("failed to create usage controller", logger.Err(err))
+		return nil, err
+	}
+
+	docsController, err := http_controllers.NewDocsController(log)
+	if err != nil {
+		log.Error("failed to create docs controller", logger.Err(err))
+		return nil, err
+	}
+
+	healthController, err := http_controllers.NewHealthController(cfg, log)
+	if err != nil {
+		log.Error("failed to create health controller", logger.Err(err))
+		return nil, err
+	}
+
 	if cfg.Env == "prod" {
 		gin.SetMode(gin.ReleaseMode)
 		log.Info("running in production mode")
@@ -40,49 +76,122 @@ func NewRouter(cfg *config.Config, log *slog.Logger) (*gin.Engine, error) {
 	}
 
 	router := gin.New()
+	// контекст запроса со спаном otelgin должен доходить до gRPC и Kafka через *gin.Context
+	router.ContextWithFallback = true
+	// от ClientIP зависят лимиты /auth/*, поэтому заголовкам верим только от известных прокси
+	if err := router.SetTrustedProxies(cfg.HTTP.TrustedProxies); err != nil {
+		log.Error("invalid trusted proxies", logger.Err(err))
+		return nil, err
+	}
 	router.Use(
+		otelgin.Middleware(tracing.ServiceName(), otelgin.WithFilter(traced)),
+		middleware_controller.Metrics(),
 		gin.Recovery(),
-		requestLoggerMiddleware(log),
 		requestIDMiddleware(),
+		requestLoggerMiddleware(log),
 	)
 
-	middlewareController, err := middleware_controller.AuthMiddleware(cfg.Auth, log)
+	middlewareController, err := middleware_controller.AuthMiddleware(cfg, log)
 	if err != nil {
 		log.Error("failed to create auth middleware", logger.Err(err))
 		return nil, err
 	}
 	log.Debug("auth middleware initialized")
 
-	SetupRoutes(router, authController, contentController, middlewareController)
+	rateLimiter, err := middleware_controller.NewRateLimiter(cfg, log)
+	if err != nil {
+		log.Error("failed to create rate limiter", logger.Err(err))
+		return nil, err
+	}
+	log.Debug("rate limiter initialized")
+
+	idempotency, err := middleware_controller.NewIdempotency(cfg, log)
+	if err != nil {
+		log.Error("failed to create idempotency middleware", logger.Err(err))
+		return nil, err
+	}
+
+	uploads, err := middleware_controller.NewUploadLimits(cfg, log)
+	if err != nil {
+		log.Error("failed to create upload limits", logger.Err(err))
+		return nil, err
+	}
+
+	validator, err := middleware_controller.NewOpenAPIValidator(cfg, log)
+	if err != nil {
+		log.Error("failed to create openapi validator", logger.Err(err))
+		return nil, err
+	}
+
+	SetupRoutes(router, cfg.API, &Handlers{
+		Auth:         authController,
+		Content:      contentController,
+		Webhook:      webhookController,
+		Usage:        usageController,
+		Docs:         docsController,
+		Health:       healthController,
+		Authenticate: middlewareController,
+		RateLimiter:  rateLimiter,
+		Idempotency:  idempotency,
+		Uploads:      uploads,
+		Validator:    validator,
+	})
 	log.Info("router initialized successfully",
 		slog.Duration("duration", time.Since(startTime)))
 
 	return router, nil
 
 }
-func SetupRoutes(router *gin.Engine, authController *http_controllers.AuthController, contentController *http_controllers.ContentController, middlewareController gin.HandlerFunc) {
-	public := router.Group("/auth")
-	{
-		public.POST("/register", authController.Register)
-		public.POST("/login", authController.Login)
-		public.POST("/refresh", authController.RefreshToken)
+
+// SetupRoutes монтирует каждую версию API под своим префиксом. Общие middleware
+// создаются один раз, поэтому лимиты и квоты у версий и их синонимов общие.
+func SetupRoutes(router *gin.Engine, cfg *config.APIConfig, h *Handlers) {
+	router.GET("/openapi.json", h.Docs.Spec)
+	router.GET("/.well-known/jwks.json", h.Auth.JWKS)
+	router.GET("/docs", h.Docs.UI)
+	router.GET("/docs/assets/*filepath", h.Docs.Asset)
+	router.GET("/verify-email", h.Auth.VerifyEmailPage)
+	router.GET("/reset-password", h.Auth.ResetPasswordPage)
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	router.GET("/healthz", h.Health.Liveness)
+	router.GET("/readyz", h.Health.Readiness)
+
+	for _, version := range versions {
+		version.mount(router.Group(version.prefix), h, h.Validator.Middleware(version.prefix))
 	}
 
-	protected := router.Group("/")
-	protected.Use(middlewareController)
-	{
-		protected.POST("/content/text", contentController.UploadText)
-		protected.POST("/content/image", contentController.UploadImage)
-		protected.GET("/content/:id", contentController.GetContent)
+	if cfg.LegacyRoutes {
+		legacy := router.Group("/", deprecated(cfg, legacyVersion))
+		mountV1(legacy, h, h.Validator.Middleware(""))
 	}
 
 	router.NoRoute(func(c *gin.Context) {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "endpoint not found",
-		})
+		problem.Write(c, problem.New(errorsv1.ErrorCode_NOT_FOUND, "endpoint not found"))
 	})
 }
 
+// deprecated помечает ответы путей без версии заголовками Deprecation (RFC 9745)
+// и Sunset (RFC 8594) и даёт ссылку на тот же путь в актуальной версии.
+func deprecated(cfg *config.APIConfig, successor string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(cfg.LegacyDeprecated.Unix(), 10)
+	sunset := cfg.LegacySunset.UTC().Format(http.TimeFormat)
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunset)
+		c.Header("Link", fmt.Sprintf("<%s%s>; rel=\"successor-version\"", successor, c.Request.URL.Path))
+		c.Next()
+	}
+}
+
+// traced исключает из трейсинга служебные эндпоинты, которые опрашиваются по расписанию
+func traced(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/metrics", "/healthz", "/readyz":
+		return false
+	}
+	return true
+}
+
 func requestLoggerMiddleware(log *slog.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		startTime := time.Now()
@@ -115,26 +224,20 @@ func requestLoggerMiddleware(log *slog.Logger) gin.HandlerFunc {
 	}
 }
 
+// requestIDMiddleware принимает id клиента или выдаёт свой, возвращает его в ответе
+// и кладёт в контекст запроса, откуда он уходит в gRPC-метаданные и заголовки Kafka.
+// Туда же кладётся адрес клиента для auth_service.
 func requestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		requestID := c.GetHeader("X-Request-ID")
-		if requestID == "" {
-			requestID = generateRequestID()
+		requestID := c.GetHeader(requestid.Header)
+		if !requestid.Valid(requestID) {
+			requestID = requestid.New()
 		}
 		c.Set("request_id", requestID)
+		c.Header(requestid.Header, requestID)
+		ctx := requestid.WithContext(c.Request.Context(), requestID)
+		c.Request = c.Request.WithContext(clientip.WithContext(ctx, c.ClientIP()))
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("http.request_id", requestID))
 		c.Next()
 	}
 }
-
-func generateRequestID() string {
-	return time.Now().Format("20060102150405") + "-" + randString(8)
-}
-
-func randString(n int) string {
-	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	b := make([]byte, n)
-	for i := range b {
-		b[i] = letters[rand.Intn(len(letters))]
-	}
-	return string(b)
-}
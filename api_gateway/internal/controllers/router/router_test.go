@@ -0,0 +1,446 @@
+package http_controllers
+
+import (
+	"api_gateway/internal/config"
+	"api_gateway/internal/controllers/http_controllers"
+	"api_gateway/internal/controllers/middleware_controller"
+	"api_gateway/internal/domain/models"
+	"api_gateway/internal/openapi"
+	"api_gateway/internal/ratelimit"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// минимальный PNG: http.DetectContentType узнаёт его по сигнатуре
+var testPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR")
+
+var testTime = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+type fakeAuth struct{}
+
+func (fakeAuth) Register(ctx context.Context, email, password, name string) (*models.TokenDetails, error) {
+	return &models.TokenDetails{UserID: "user-1"}, nil
+}
+
+func (fakeAuth) Login(ctx context.Context, email, password string) (*models.TokenDetails, error) {
+	return &models.TokenDetails{UserID: "user-1", AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func (fakeAuth) ValidateToken(ctx context.Context, token string) (string, error) {
+	return "user-1", nil
+}
+
+func (fakeAuth) RefreshToken(ctx context.Context, refreshToken string) (*models.TokenDetails, error) {
+	return &models.TokenDetails{UserID: "user-1", AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func (fakeAuth) Logout(ctx context.Context, token string) error    { return nil }
+func (fakeAuth) LogoutAll(ctx context.Context, token string) error { return nil }
+
+func (fakeAuth) GetJWKS(ctx context.Context) ([]*models.JWK, error) { return nil, nil }
+
+func (fakeAuth) VerifyEmail(ctx context.Context, token string) error             { return nil }
+func (fakeAuth) RequestPasswordReset(ctx context.Context, email string) error    { return nil }
+func (fakeAuth) ConfirmPasswordReset(ctx context.Context, token, p string) error { return nil }
+
+type fakeContent struct{}
+
+func (fakeContent) ProcessContent(ctx context.Context, userID string, contentType models.ContentType, data string, mimeType string) (*models.Content, error) {
+	return &models.Content{ID: "c1", Status: "PENDING"}, nil
+}
+
+func (fakeContent) ProcessImage(ctx context.Context, userID string, data []byte, mimeType string) (*models.Content, error) {
+	return &models.Content{ID: "c2", Status: "PENDING"}, nil
+}
+
+func (fakeContent) ProcessContentBatch(ctx context.Context, userID string, contents []*models.Content) ([]*models.Content, error) {
+	out := make([]*models.Content, len(contents))
+	for i := range contents {
+		out[i] = &models.Content{ID: "b" + strconv.Itoa(i), Status: "PENDING"}
+	}
+	return out, nil
+}
+
+func (fakeContent) GetContent(ctx context.Context, userID string, contentID string) (*models.ContentStatus, error) {
+	return &models.ContentStatus{
+		ID:              contentID,
+		Type:            "TEXT",
+		Status:          "COMPLETED",
+		OriginalContent: "hello",
+		Analysis:        map[string]interface{}{"toxicity": 0.1},
+	}, nil
+}
+
+func (fakeContent) GetImage(ctx context.Context, userID string, contentID string) (*models.Image, error) {
+	return &models.Image{URL: "https://s3.example.com/images/" + contentID}, nil
+}
+
+func (fakeContent) ListContent(ctx context.Context, userID string, filter *models.ContentFilter) (*models.ContentPage, error) {
+	return &models.ContentPage{
+		Items: []models.ContentSummary{{
+			ID: "c1", Type: "TEXT", Status: "COMPLETED", CreatedAt: testTime, UpdatedAt: testTime,
+		}},
+		NextCursor: "next",
+	}, nil
+}
+
+func (fakeContent) DeleteContent(ctx context.Context, userID string, contentID string) (time.Time, error) {
+	return testTime.Add(30 * 24 * time.Hour), nil
+}
+
+func (fakeContent) RestoreContent(ctx context.Context, userID string, contentID string) error {
+	return nil
+}
+
+func (fakeContent) WatchContent(ctx context.Context, userID string, contentID string) (<-chan *models.ContentEvent, error) {
+	events := make(chan *models.ContentEvent, 1)
+	events <- &models.ContentEvent{ContentID: contentID, Type: "TEXT", Status: "COMPLETED", OccurredAt: testTime}
+	close(events)
+	return events, nil
+}
+
+func (fakeContent) Close() error { return nil }
+
+type fakeWebhooks struct{}
+
+func testDelivery() *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:            "d1",
+		WebhookID:     "w1",
+		ContentID:     "c1",
+		Event:         "content.completed",
+		Status:        "pending",
+		Attempts:      1,
+		ResponseCode:  500,
+		LastError:     "unexpected status 500",
+		NextAttemptAt: testTime,
+		CreatedAt:     testTime,
+		UpdatedAt:     testTime,
+	}
+}
+
+func (fakeWebhooks) CreateWebhook(ctx context.Context, userID string, url string, secret string) (*models.Webhook, error) {
+	return &models.Webhook{ID: "w1", URL: url, Secret: "generated-secret", CreatedAt: testTime}, nil
+}
+
+func (fakeWebhooks) ListWebhooks(ctx context.Context, userID string) ([]*models.Webhook, error) {
+	return []*models.Webhook{{ID: "w1", URL: "https://example.com/hook", CreatedAt: testTime}}, nil
+}
+
+func (fakeWebhooks) DeleteWebhook(ctx context.Context, userID string, webhookID string) error {
+	return nil
+}
+
+func (fakeWebhooks) ListDeliveries(ctx context.Context, userID string, webhookID string, status string, limit int) ([]*models.WebhookDelivery, error) {
+	return []*models.WebhookDelivery{testDelivery()}, nil
+}
+
+func (fakeWebhooks) Redeliver(ctx context.Context, userID string, webhookID string, deliveryID string) (*models.WebhookDelivery, error) {
+	return testDelivery(), nil
+}
+
+func (fakeWebhooks) Close() error { return nil }
+
+type fakeLimiter struct{}
+
+func (fakeLimiter) Allow(ctx context.Context, key string, limit ratelimit.Limit) (*ratelimit.Result, error) {
+	return &ratelimit.Result{Allowed: true, Limit: limit.Burst, Remaining: limit.Burst - 1, ResetAfter: time.Second}, nil
+}
+
+func (fakeLimiter) Peek(ctx context.Context, key string, limit ratelimit.Limit) (*ratelimit.Result, error) {
+	return &ratelimit.Result{Allowed: true, Limit: limit.Burst, Remaining: limit.Burst}, nil
+}
+
+func (fakeLimiter) Consume(ctx context.Context, userID string, n int) (*ratelimit.QuotaResult, error) {
+	return &ratelimit.QuotaResult{Allowed: true, Used: n, Limit: 500, ResetsAt: testTime, Key: "quota"}, nil
+}
+
+func (fakeLimiter) Release(ctx context.Context, key string, n int) error { return nil }
+
+func (fakeLimiter) Usage(ctx context.Context, userID string) (*ratelimit.QuotaResult, error) {
+	return &ratelimit.QuotaResult{Used: 3, Limit: 500, ResetsAt: testTime}, nil
+}
+
+func testConfig() *config.Config {
+	return &config.Config{
+		HTTP:    &config.HTTPConfig{},
+		Auth:    &config.AuthConfig{},
+		Content: &config.ContentConfig{MaxBatchSize: 10},
+		Upload: &config.UploadConfig{UploadPolicy: config.UploadPolicy{
+			MaxTextBytes:      1 << 16,
+			MaxTextRunes:      1000,
+			MaxImageBytes:     1 << 20,
+			MaxBatchBytes:     1 << 20,
+			AllowedImageTypes: []string{"image/png"},
+		}},
+		Redis: &config.RedisConfig{URL: "redis://localhost:6379/1"},
+		// лимитеры запросов отключены, квоту и состояние корзин отдаёт fakeLimiter
+		RateLimit: &config.RateLimitConfig{
+			Groups: map[string]*config.LimitConfig{
+				"api":    {Requests: 300, Period: time.Minute, Burst: 60},
+				"upload": {Requests: 30, Period: time.Minute, Burst: 10},
+			},
+		},
+		Idempotency: &config.IdempotencyConfig{TTL: time.Hour, LockTimeout: time.Minute},
+		OpenAPI:     &config.OpenAPIConfig{ValidateRequests: true},
+		API:         &config.APIConfig{},
+	}
+}
+
+// newTestServer собирает маршруты шлюза поверх поддельных usecase-ов
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := testConfig()
+
+	content, err := http_controllers.NewContentControllerWithUsecase(cfg, fakeContent{}, fakeLimiter{}, log)
+	if err != nil {
+		t.Fatalf("content controller: %v", err)
+	}
+	docs, err := http_controllers.NewDocsController(log)
+	if err != nil {
+		t.Fatalf("docs controller: %v", err)
+	}
+	rateLimiter, err := middleware_controller.NewRateLimiter(cfg, log)
+	if err != nil {
+		t.Fatalf("rate limiter: %v", err)
+	}
+	idempotency, err := middleware_controller.NewIdempotency(cfg, log)
+	if err != nil {
+		t.Fatalf("idempotency: %v", err)
+	}
+	uploads, err := middleware_controller.NewUploadLimits(cfg, log)
+	if err != nil {
+		t.Fatalf("upload limits: %v", err)
+	}
+	validator, err := middleware_controller.NewOpenAPIValidator(cfg, log)
+	if err != nil {
+		t.Fatalf("openapi validator: %v", err)
+	}
+
+	usageCfg := *cfg
+	usageCfg.RateLimit = &config.RateLimitConfig{Enabled: true, Groups: cfg.RateLimit.Groups}
+
+	router := gin.New()
+	SetupRoutes(router, cfg.API, &Handlers{
+		Auth:    http_controllers.NewAuthControllerWithUsecase(cfg.Auth, fakeAuth{}, log),
+		Content: content,
+		Webhook: http_controllers.NewWebhookControllerWithUsecase(cfg, fakeWebhooks{}, log),
+		Usage:   http_controllers.NewUsageControllerWithLimiter(&usageCfg, fakeLimiter{}, fakeLimiter{}, log),
+		Docs:    docs,
+		Health:  &http_controllers.HealthController{},
+		Authenticate: func(ctx *gin.Context) {
+			ctx.Set("userID", "user-1")
+			ctx.Next()
+		},
+		RateLimiter: rateLimiter,
+		Idempotency: idempotency,
+		Uploads:     uploads,
+		Validator:   validator,
+	})
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func imageUpload(t *testing.T) (string, []byte) {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("image", "image.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(testPNG)
+	w.Close()
+	return w.FormDataContentType(), body.Bytes()
+}
+
+// TestResponsesMatchSpec прогоняет каждую операцию спецификации через маршруты
+// шлюза и проверяет ответ по openapi.yaml: расхождение обработчика или DTO
+// со спецификацией ломает тест, а не только пишется в журнал валидатора.
+func TestResponsesMatchSpec(t *testing.T) {
+	srv := newTestServer(t)
+
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("load spec: %v", err)
+	}
+	doc.Servers = nil
+	specRouter, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		t.Fatalf("spec router: %v", err)
+	}
+
+	imageType, imageBody := imageUpload(t)
+	batchBody := `{"items":[{"type":"text","text":"hello"},{"type":"image","image":"` +
+		base64.StdEncoding.EncodeToString(testPNG) + `"},{"type":"text"}]}`
+
+	tests := []struct {
+		method      string
+		path        string
+		contentType string
+		body        string
+		status      int
+		// тело потоковых ответов не проверяется, как и в OpenAPIValidator
+		stream bool
+	}{
+		{http.MethodPost, "/auth/register", "application/json", `{"email":"a@example.com","password":"password1","name":"A"}`, http.StatusCreated, false},
+		{http.MethodPost, "/auth/login", "application/json", `{"email":"a@example.com","password":"password1"}`, http.StatusOK, false},
+		{http.MethodPost, "/auth/refresh", "application/json", `{"refresh_token":"refresh"}`, http.StatusOK, false},
+		{http.MethodPost, "/auth/verify-email", "application/json", `{"token":"token"}`, http.StatusNoContent, false},
+		{http.MethodPost, "/auth/password-reset", "application/json", `{"email":"a@example.com"}`, http.StatusAccepted, false},
+		{http.MethodPost, "/auth/password-reset/confirm", "application/json", `{"token":"token","password":"password2"}`, http.StatusNoContent, false},
+		{http.MethodPost, "/auth/logout", "", "", http.StatusNoContent, false},
+		{http.MethodPost, "/auth/logout-all", "", "", http.StatusNoContent, false},
+		{http.MethodPost, "/content/text", "application/json", `{"text":"hello"}`, http.StatusAccepted, false},
+		{http.MethodPost, "/content/image", imageType, string(imageBody), http.StatusAccepted, false},
+		{http.MethodPost, "/content/batch", "application/json", batchBody, http.StatusAccepted, false},
+		{http.MethodPost, "/content/batch", "application/json", `{"items":[{"type":"text"}]}`, http.StatusBadRequest, false},
+		{http.MethodGet, "/content?status=completed&limit=10", "", "", http.StatusOK, false},
+		{http.MethodGet, "/content/c1", "", "", http.StatusOK, false},
+		{http.MethodDelete, "/content/c1", "", "", http.StatusOK, false},
+		{http.MethodGet, "/content/c1/image", "", "", http.StatusFound, false},
+		{http.MethodPost, "/content/c1/restore", "", "", http.StatusOK, false},
+		{http.MethodGet, "/content/c1/events", "", "", http.StatusOK, true},
+		{http.MethodPost, "/webhooks", "application/json", `{"url":"https://example.com/hook"}`, http.StatusCreated, false},
+		{http.MethodGet, "/webhooks", "", "", http.StatusOK, false},
+		{http.MethodDelete, "/webhooks/w1", "", "", http.StatusNoContent, false},
+		{http.MethodGet, "/webhooks/w1/deliveries?status=pending&limit=10", "", "", http.StatusOK, false},
+		{http.MethodPost, "/webhooks/w1/deliveries/d1/redeliver", "", "", http.StatusAccepted, false},
+		{http.MethodGet, "/me/usage", "", "", http.StatusOK, false},
+	}
+
+	client := srv.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	covered := make(map[string]bool)
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, srv.URL+"/v1"+tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Authorization", "Bearer token")
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, tt.status, body)
+			}
+
+			route, params := findSpecRoute(t, specRouter, tt.method, tt.path, tt.contentType, tt.body)
+			covered[tt.method+" "+route.Path] = true
+			validateResponse(t, route, params, resp, body, tt.stream)
+		})
+	}
+
+	t.Run("GET /content/events", func(t *testing.T) {
+		url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/v1/content/events"
+		conn, resp, err := websocket.DefaultDialer.Dial(url, http.Header{"Authorization": {"Bearer token"}})
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		defer conn.Close()
+
+		route, params := findSpecRoute(t, specRouter, http.MethodGet, "/content/events", "", "")
+		covered[http.MethodGet+" "+route.Path] = true
+		validateResponse(t, route, params, resp, nil, true)
+	})
+
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			if !covered[method+" "+path] {
+				t.Errorf("operation %s %s is not exercised by the test", method, path)
+			}
+		}
+	}
+}
+
+func findSpecRoute(t *testing.T, specRouter routers.Router, method, path, contentType, body string) (*routers.Route, map[string]string) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	route, params, err := specRouter.FindRoute(req)
+	if err != nil {
+		t.Fatalf("operation is not in the spec: %v", err)
+	}
+	return route, params
+}
+
+func validateResponse(t *testing.T, route *routers.Route, params map[string]string, resp *http.Response, body []byte, stream bool) {
+	t.Helper()
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    resp.Request,
+			PathParams: params,
+			Route:      route,
+		},
+		Status: resp.StatusCode,
+		Header: resp.Header,
+		Body:   io.NopCloser(bytes.NewReader(body)),
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+			ExcludeResponseBody:   stream,
+		},
+	}
+	if err := openapi3filter.ValidateResponse(context.Background(), input); err != nil {
+		t.Errorf("response does not match spec: %v", err)
+	}
+}
+
+// TestEmailLinkPages: ссылки из писем auth_service открываются GET-ом и должны
+// вести на страницы, которые отправляют токен в существующие POST-операции.
+func TestEmailLinkPages(t *testing.T) {
+	srv := newTestServer(t)
+
+	for path, endpoint := range map[string]string{
+		"/verify-email":   "/v1/auth/verify-email",
+		"/reset-password": "/v1/auth/password-reset/confirm",
+	} {
+		resp, err := srv.Client().Get(srv.URL + path + "?token=token")
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+			t.Errorf("GET %s = %d %s, want an html page", path, resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+		if resp.Header.Get("Referrer-Policy") != "no-referrer" {
+			t.Errorf("GET %s leaks the token through Referer", path)
+		}
+		if !bytes.Contains(body, []byte(endpoint)) {
+			t.Errorf("page %s does not post to %s", path, endpoint)
+		}
+	}
+}
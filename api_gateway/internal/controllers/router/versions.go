@@ -0,0 +1,83 @@
+package http_controllers
+
+import (
+	"api_gateway/internal/controllers/http_controllers"
+	"api_gateway/internal/controllers/middleware_controller"
+	dtov1 "api_gateway/internal/dto/v1"
+	"api_gateway/internal/upload"
+	"github.com/gin-gonic/gin"
+)
+
+// Handlers — контроллеры и общие middleware, из которых собирается каждая версия API
+type Handlers struct {
+	Auth         *http_controllers.AuthController
+	Content      *http_controllers.ContentController
+	Webhook      *http_controllers.WebhookController
+	Usage        *http_controllers.UsageController
+	Docs         *http_controllers.DocsController
+	Health       *http_controllers.HealthController
+	Authenticate gin.HandlerFunc
+	RateLimiter  *middleware_controller.RateLimiter
+	Idempotency  *middleware_controller.Idempotency
+	Uploads      *middleware_controller.UploadLimits
+	Validator    *middleware_controller.OpenAPIValidator
+}
+
+type apiVersion struct {
+	prefix string
+	mount  func(group *gin.RouterGroup, h *Handlers, validate gin.HandlerFunc)
+}
+
+// versions обслуживаются одновременно; новая версия добавляется сюда
+// со своей функцией монтирования и своим Presenter, старые при этом не меняются
+var versions = []apiVersion{
+	{prefix: "/v1", mount: mountV1},
+}
+
+// legacyVersion — версия, которую повторяют устаревшие пути без префикса
+const legacyVersion = "/v1"
+
+func mountV1(group *gin.RouterGroup, h *Handlers, validate gin.HandlerFunc) {
+	group.Use(http_controllers.Present(dtov1.Presenter{}))
+
+	public := group.Group("/auth")
+	public.Use(h.RateLimiter.Limit("auth", middleware_controller.ByClientIP), validate)
+	{
+		public.POST("/register", h.Auth.Register)
+		public.POST("/login", h.Auth.Login)
+		public.POST("/refresh", h.Auth.RefreshToken)
+		public.POST("/verify-email", h.Auth.VerifyEmail)
+		public.POST("/password-reset", h.Auth.RequestPasswordReset)
+		public.POST("/password-reset/confirm", h.Auth.ConfirmPasswordReset)
+	}
+
+	protected := group.Group("/")
+	protected.Use(h.Authenticate, h.RateLimiter.Limit("api", middleware_controller.ByUserID))
+	// загрузки проверяются валидатором только после ограничения тела: он читает его целиком
+	uploadLimit := h.RateLimiter.Limit("upload", middleware_controller.ByUserID)
+	idempotent := h.Idempotency.Middleware()
+	{
+		protected.POST("/content/text", uploadLimit, h.Uploads.Limit(upload.KindText), validate, idempotent, h.Content.UploadText)
+		protected.POST("/content/image", uploadLimit, h.Uploads.Limit(upload.KindImage), validate, idempotent, h.Content.UploadImage)
+		protected.POST("/content/batch", uploadLimit, h.Uploads.Limit(upload.KindBatch), validate, h.Content.UploadBatch)
+	}
+
+	validated := protected.Group("/", validate)
+	{
+		validated.POST("/auth/logout", h.Auth.Logout)
+		validated.POST("/auth/logout-all", h.Auth.LogoutAll)
+		validated.GET("/content", h.Content.ListContent)
+		validated.GET("/content/events", h.Content.StreamContentEventsWS)
+		validated.GET("/content/:id", h.Content.GetContent)
+		validated.GET("/content/:id/image", h.Content.GetImage)
+		validated.GET("/content/:id/events", h.Content.StreamContentEvents)
+		validated.DELETE("/content/:id", h.Content.DeleteContent)
+		validated.POST("/content/:id/restore", h.Content.RestoreContent)
+		validated.POST("/webhooks", h.Webhook.CreateWebhook)
+		validated.GET("/webhooks", h.Webhook.ListWebhooks)
+		validated.DELETE("/webhooks/:id", h.Webhook.DeleteWebhook)
+		validated.GET("/webhooks/:id/deliveries", h.Webhook.ListDeliveries)
+		validated.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", h.Webhook.Redeliver)
+		validated.GET("/me/usage", h.Usage.GetUsage)
+	}
+}
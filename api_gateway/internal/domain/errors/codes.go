@@ -0,0 +1,99 @@
+package errors
+
+import (
+	"errors"
+
+	errorsv1 "github.com/deeelis/errors-protos/gen/go/errors"
+)
+
+// Error — ошибка с кодом из общего каталога errors-protos. Через errors.Is
+// она совпадает с соответствующей ошибкой пакета, если такая есть.
+type Error struct {
+	Code    errorsv1.ErrorCode
+	Message string
+	cause   error
+}
+
+func New(code errorsv1.ErrorCode, message string, cause error) *Error {
+	return &Error{Code: code, Message: message, cause: cause}
+}
+
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return e.Code.String() + ": " + e.Message
+	}
+	return e.Code.String()
+}
+
+func (e *Error) Unwrap() []error {
+	unwrapped := make([]error, 0, 2)
+	if sentinel, ok := sentinelByCode[e.Code]; ok {
+		unwrapped = append(unwrapped, sentinel)
+	}
+	if e.cause != nil {
+		unwrapped = append(unwrapped, e.cause)
+	}
+	return unwrapped
+}
+
+var sentinelByCode = map[errorsv1.ErrorCode]error{
+	errorsv1.ErrorCode_INTERNAL:                   ErrInternalServer,
+	errorsv1.ErrorCode_INVALID_ARGUMENT:           ErrInvalidInput,
+	errorsv1.ErrorCode_UNAUTHENTICATED:            ErrUnauthorized,
+	errorsv1.ErrorCode_INVALID_CREDENTIALS:        ErrInvalidCredentials,
+	errorsv1.ErrorCode_TOKEN_INVALID:              ErrUnauthorized,
+	errorsv1.ErrorCode_USER_ALREADY_EXISTS:        ErrUserAlreadyExists,
+	errorsv1.ErrorCode_USER_NOT_FOUND:             ErrUserNotFound,
+	errorsv1.ErrorCode_CONTENT_NOT_FOUND:          ErrContentNotFound,
+	errorsv1.ErrorCode_WEBHOOK_NOT_FOUND:          ErrWebhookNotFound,
+	errorsv1.ErrorCode_WEBHOOK_INVALID:            ErrInvalidInput,
+	errorsv1.ErrorCode_DELIVERY_NOT_REDELIVERABLE: ErrNotRedeliverable,
+}
+
+// порядок важен: ошибки проверяются через errors.Is сверху вниз
+var codeBySentinel = []struct {
+	err  error
+	code errorsv1.ErrorCode
+}{
+	{ErrInvalidCredentials, errorsv1.ErrorCode_INVALID_CREDENTIALS},
+	{ErrUserAlreadyExists, errorsv1.ErrorCode_USER_ALREADY_EXISTS},
+	{ErrUserNotFound, errorsv1.ErrorCode_USER_NOT_FOUND},
+	{ErrInvalidInput, errorsv1.ErrorCode_INVALID_ARGUMENT},
+	{ErrInvalidContentType, errorsv1.ErrorCode_INVALID_ARGUMENT},
+	{ErrUnauthorized, errorsv1.ErrorCode_UNAUTHENTICATED},
+	{ErrContentNotFound, errorsv1.ErrorCode_CONTENT_NOT_FOUND},
+	{ErrWebhookNotFound, errorsv1.ErrorCode_WEBHOOK_NOT_FOUND},
+	{ErrNotRedeliverable, errorsv1.ErrorCode_DELIVERY_NOT_REDELIVERABLE},
+	{ErrContentNotReady, errorsv1.ErrorCode_CONFLICT},
+	{ErrImageRejected, errorsv1.ErrorCode_PERMISSION_DENIED},
+	{ErrKafkaUnavailable, errorsv1.ErrorCode_UNAVAILABLE},
+	{ErrInternalServer, errorsv1.ErrorCode_INTERNAL},
+}
+
+// CodeOf возвращает код ошибки из каталога. Ошибки без кода считаются внутренними.
+func CodeOf(err error) errorsv1.ErrorCode {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	for _, s := range codeBySentinel {
+		if errors.Is(err, s.err) {
+			return s.code
+		}
+	}
+	return errorsv1.ErrorCode_INTERNAL
+}
+
+// MessageOf возвращает текст ошибки, который можно показать клиенту.
+func MessageOf(err error) string {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Message
+	}
+	for _, s := range codeBySentinel {
+		if errors.Is(err, s.err) && s.code != errorsv1.ErrorCode_INTERNAL && s.code != errorsv1.ErrorCode_UNAVAILABLE {
+			return s.err.Error()
+		}
+	}
+	return ""
+}
@@ -12,4 +12,8 @@ var (
 	ErrInternalServer     = errors.New("internal server error")
 	ErrInvalidContentType = errors.New("invalid content type")
 	ErrKafkaUnavailable   = errors.New("kafka unavailable")
+	ErrWebhookNotFound    = errors.New("webhook not found")
+	ErrNotRedeliverable   = errors.New("delivery cannot be redelivered")
+	ErrContentNotReady    = errors.New("content is not processed yet")
+	ErrImageRejected      = errors.New("image was rejected by moderation")
 )
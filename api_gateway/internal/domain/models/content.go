@@ -13,9 +13,16 @@ type Content struct {
 	ID        string      `json:"id"`
 	UserID    string      `json:"user_id"`
 	Type      ContentType `json:"type"`
-	Data      string      `json:"data"`
+	Data      string      `json:"data,omitempty"`
 	DataType  string      `json:"data_type"`
+	Bucket    string      `json:"bucket,omitempty"`
+	Key       string      `json:"key,omitempty"`
+	Checksum  string      `json:"checksum,omitempty"`
+	Size      int64       `json:"size,omitempty"`
 	Status    string      `json:"status"`
 	CreatedAt time.Time   `json:"created_at"`
 	UpdatedAt time.Time   `json:"updated_at"`
+
+	// Blob — сырые байты изображения до загрузки в объектное хранилище, в Kafka не попадают
+	Blob []byte `json:"-"`
 }
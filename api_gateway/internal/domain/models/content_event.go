@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type ContentEvent struct {
+	ContentID       string                 `json:"content_id"`
+	Type            string                 `json:"type"`
+	Status          string                 `json:"status"`
+	Analysis        map[string]interface{} `json:"analysis,omitempty"`
+	OriginalContent string                 `json:"data,omitempty"`
+	OccurredAt      time.Time              `json:"occurred_at"`
+}
+
+func (e *ContentEvent) IsTerminal() bool {
+	return e.Status == "COMPLETED" || e.Status == "FAILED"
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type ContentFilter struct {
+	Status        string
+	Type          string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Limit         int
+	Cursor        string
+}
+
+type ContentSummary struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ContentPage struct {
+	Items      []ContentSummary `json:"items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
@@ -5,5 +5,7 @@ type ContentStatus struct {
 	Type            string
 	Status          string
 	OriginalContent string
-	Analysis        map[string]interface{}
+	// ObjectKey — ключ изображения в приватном бакете, наружу не отдаётся
+	ObjectKey string
+	Analysis  map[string]interface{}
 }
@@ -0,0 +1,12 @@
+package models
+
+import "io"
+
+// Image — результат запроса изображения: либо подписанная ссылка URL,
+// либо открытое тело объекта, которое закрывает вызывающий.
+type Image struct {
+	URL         string
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+}
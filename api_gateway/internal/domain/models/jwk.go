@@ -0,0 +1,11 @@
+package models
+
+// JWK — открытый ключ подписи токенов в формате RFC 7517.
+type JWK struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type Webhook struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID            string    `json:"id"`
+	WebhookID     string    `json:"webhook_id"`
+	ContentID     string    `json:"content_id"`
+	Event         string    `json:"event"`
+	Status        string    `json:"status"`
+	Attempts      int       `json:"attempts"`
+	ResponseCode  int       `json:"response_code,omitempty"`
+	LastError     string    `json:"last_error,omitempty"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
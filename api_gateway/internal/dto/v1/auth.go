@@ -0,0 +1,17 @@
+package v1
+
+import "api_gateway/internal/domain/models"
+
+type Tokens struct {
+	UserID       string `json:"user_id,omitempty"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+func NewTokens(tokens *models.TokenDetails) Tokens {
+	return Tokens{
+		UserID:       tokens.UserID,
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}
+}
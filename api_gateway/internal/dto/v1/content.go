@@ -0,0 +1,114 @@
+// Package v1 описывает тела ответов публичного API /v1.
+// Это контракт с клиентами: поля можно только добавлять,
+// переименование и смена типов — только в следующей версии.
+package v1
+
+import (
+	"api_gateway/internal/domain/models"
+	"time"
+)
+
+type Accepted struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func NewAccepted(content *models.Content) Accepted {
+	return Accepted{ID: content.ID, Status: content.Status}
+}
+
+type BatchItem struct {
+	Index int    `json:"index"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type BatchResult struct {
+	Items []BatchItem `json:"items"`
+}
+
+func NewBatchResult(results []models.BatchItemResult) BatchResult {
+	items := make([]BatchItem, len(results))
+	for i, r := range results {
+		items[i] = BatchItem{Index: r.Index, ID: r.ID, Error: r.Error}
+	}
+	return BatchResult{Items: items}
+}
+
+type Content struct {
+	ID       string                 `json:"id"`
+	Status   string                 `json:"status"`
+	Type     string                 `json:"type"`
+	Analysis map[string]interface{} `json:"analysis,omitempty"`
+	// Data отдаётся только после завершения модерации; для изображения это
+	// ссылка на GET /v1/content/{id}/image, сам объект лежит в приватном бакете
+	Data string `json:"data,omitempty"`
+}
+
+func imagePath(contentID string) string {
+	return "/v1/content/" + contentID + "/image"
+}
+
+func NewContent(status *models.ContentStatus) Content {
+	content := Content{
+		ID:     status.ID,
+		Status: status.Status,
+		Type:   status.Type,
+	}
+	if len(status.Analysis) > 0 {
+		content.Analysis = status.Analysis
+	}
+	if status.Status == "COMPLETED" {
+		content.Data = status.OriginalContent
+		if status.Type == "IMAGE" {
+			content.Data = imagePath(status.ID)
+		}
+	}
+	return content
+}
+
+type ContentSummary struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ContentPage struct {
+	Items      []ContentSummary `json:"items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+func NewContentPage(page *models.ContentPage) ContentPage {
+	items := make([]ContentSummary, len(page.Items))
+	for i, item := range page.Items {
+		items[i] = ContentSummary{
+			ID:        item.ID,
+			Type:      item.Type,
+			Status:    item.Status,
+			CreatedAt: item.CreatedAt,
+			UpdatedAt: item.UpdatedAt,
+		}
+	}
+	return ContentPage{Items: items, NextCursor: page.NextCursor}
+}
+
+type Deleted struct {
+	ID         string    `json:"id"`
+	Status     string    `json:"status"`
+	PurgeAfter time.Time `json:"purge_after"`
+}
+
+func NewDeleted(contentID string, purgeAfter time.Time) Deleted {
+	return Deleted{ID: contentID, Status: "DELETED", PurgeAfter: purgeAfter}
+}
+
+type Restored struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func NewRestored(contentID string) Restored {
+	return Restored{ID: contentID, Status: "RESTORED"}
+}
@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"api_gateway/internal/domain/models"
+	"time"
+)
+
+type ContentEvent struct {
+	ContentID  string                 `json:"content_id"`
+	Type       string                 `json:"type"`
+	Status     string                 `json:"status"`
+	Analysis   map[string]interface{} `json:"analysis,omitempty"`
+	Data       string                 `json:"data,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at"`
+}
+
+func NewContentEvent(event *models.ContentEvent) *ContentEvent {
+	data := event.OriginalContent
+	if event.Type == "IMAGE" && event.Status == "COMPLETED" {
+		data = imagePath(event.ContentID)
+	}
+	return &ContentEvent{
+		ContentID:  event.ContentID,
+		Type:       event.Type,
+		Status:     event.Status,
+		Analysis:   event.Analysis,
+		Data:       data,
+		OccurredAt: event.OccurredAt,
+	}
+}
+
+type Heartbeat struct {
+	Time time.Time `json:"time"`
+}
+
+// StreamMessage — сообщение сервера в WebSocket-потоке /content/events
+type StreamMessage struct {
+	ContentID string        `json:"content_id"`
+	Event     *ContentEvent `json:"event,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"api_gateway/internal/domain/models"
+	"api_gateway/internal/ratelimit"
+	"time"
+)
+
+// Presenter отдаёт тела ответов /v1, его ставит на маршруты mountV1
+type Presenter struct{}
+
+func (Presenter) Tokens(tokens *models.TokenDetails) any { return NewTokens(tokens) }
+
+func (Presenter) Accepted(content *models.Content) any { return NewAccepted(content) }
+
+func (Presenter) BatchResult(results []models.BatchItemResult) any { return NewBatchResult(results) }
+
+func (Presenter) Content(status *models.ContentStatus) any { return NewContent(status) }
+
+func (Presenter) ContentPage(page *models.ContentPage) any { return NewContentPage(page) }
+
+func (Presenter) Deleted(contentID string, purgeAfter time.Time) any {
+	return NewDeleted(contentID, purgeAfter)
+}
+
+func (Presenter) Restored(contentID string) any { return NewRestored(contentID) }
+
+func (Presenter) ContentEvent(event *models.ContentEvent) any { return NewContentEvent(event) }
+
+func (Presenter) Heartbeat(t time.Time) any { return Heartbeat{Time: t} }
+
+func (Presenter) StreamEvent(contentID string, event *models.ContentEvent) any {
+	return StreamMessage{ContentID: contentID, Event: NewContentEvent(event)}
+}
+
+func (Presenter) StreamError(contentID string, message string) any {
+	return StreamMessage{ContentID: contentID, Error: message}
+}
+
+func (Presenter) Usage(quota *ratelimit.QuotaResult, limits map[string]ratelimit.GroupUsage) any {
+	return NewUsage(quota, limits)
+}
+
+func (Presenter) Webhook(webhook *models.Webhook) any { return NewWebhook(webhook) }
+
+func (Presenter) WebhookList(webhooks []*models.Webhook) any { return NewWebhookList(webhooks) }
+
+func (Presenter) Delivery(delivery *models.WebhookDelivery) any { return NewDelivery(delivery) }
+
+func (Presenter) DeliveryList(deliveries []*models.WebhookDelivery) any {
+	return NewDeliveryList(deliveries)
+}
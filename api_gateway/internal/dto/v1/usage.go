@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"api_gateway/internal/ratelimit"
+	"math"
+	"time"
+)
+
+type UploadUsage struct {
+	Used      int       `json:"used"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
+
+type RateLimitUsage struct {
+	Limit     int `json:"limit"`
+	Remaining int `json:"remaining"`
+	// Reset — секунды до полного восполнения корзины
+	Reset    int    `json:"reset"`
+	Requests int    `json:"requests"`
+	Period   string `json:"period"`
+}
+
+type Usage struct {
+	Uploads    UploadUsage               `json:"uploads"`
+	RateLimits map[string]RateLimitUsage `json:"rate_limits"`
+}
+
+func NewUsage(quota *ratelimit.QuotaResult, limits map[string]ratelimit.GroupUsage) Usage {
+	usage := Usage{
+		Uploads: UploadUsage{
+			Used:      quota.Used,
+			Limit:     quota.Limit,
+			Remaining: max(quota.Limit-quota.Used, 0),
+			ResetsAt:  quota.ResetsAt,
+		},
+		RateLimits: make(map[string]RateLimitUsage, len(limits)),
+	}
+	for group, g := range limits {
+		usage.RateLimits[group] = RateLimitUsage{
+			Limit:     g.Result.Limit,
+			Remaining: g.Result.Remaining,
+			Reset:     int(math.Ceil(g.Result.ResetAfter.Seconds())),
+			Requests:  g.Limit.Requests,
+			Period:    g.Limit.Period.String(),
+		}
+	}
+	return usage
+}
@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"api_gateway/internal/domain/models"
+	"time"
+)
+
+type Webhook struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+	// Secret возвращается только при создании
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewWebhook(webhook *models.Webhook) Webhook {
+	return Webhook{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Secret:    webhook.Secret,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+type WebhookList struct {
+	Items []Webhook `json:"items"`
+}
+
+func NewWebhookList(webhooks []*models.Webhook) WebhookList {
+	items := make([]Webhook, len(webhooks))
+	for i, webhook := range webhooks {
+		items[i] = NewWebhook(webhook)
+	}
+	return WebhookList{Items: items}
+}
+
+type Delivery struct {
+	ID            string    `json:"id"`
+	WebhookID     string    `json:"webhook_id"`
+	ContentID     string    `json:"content_id"`
+	Event         string    `json:"event"`
+	Status        string    `json:"status"`
+	Attempts      int       `json:"attempts"`
+	ResponseCode  int       `json:"response_code,omitempty"`
+	LastError     string    `json:"last_error,omitempty"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func NewDelivery(delivery *models.WebhookDelivery) Delivery {
+	return Delivery{
+		ID:            delivery.ID,
+		WebhookID:     delivery.WebhookID,
+		ContentID:     delivery.ContentID,
+		Event:         delivery.Event,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		ResponseCode:  delivery.ResponseCode,
+		LastError:     delivery.LastError,
+		NextAttemptAt: delivery.NextAttemptAt,
+		CreatedAt:     delivery.CreatedAt,
+		UpdatedAt:     delivery.UpdatedAt,
+	}
+}
+
+type DeliveryList struct {
+	Items []Delivery `json:"items"`
+}
+
+func NewDeliveryList(deliveries []*models.WebhookDelivery) DeliveryList {
+	items := make([]Delivery, len(deliveries))
+	for i, delivery := range deliveries {
+		items[i] = NewDelivery(delivery)
+	}
+	return DeliveryList{Items: items}
+}
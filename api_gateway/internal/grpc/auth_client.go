@@ -10,5 +10,11 @@ type AuthClient interface {
 	Login(ctx context.Context, email, password string) (*models.TokenDetails, error)
 	ValidateToken(ctx context.Context, token string) (string, error)
 	RefreshToken(ctx context.Context, refreshToken string) (*models.TokenDetails, error)
+	Logout(ctx context.Context, token string) error
+	LogoutAll(ctx context.Context, token string) error
+	GetJWKS(ctx context.Context) ([]*models.JWK, error)
+	VerifyEmail(ctx context.Context, token string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ConfirmPasswordReset(ctx context.Context, token, password string) error
 	Close() error
 }
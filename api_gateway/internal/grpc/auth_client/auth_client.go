@@ -1,15 +1,22 @@
 package grpc
 
 import (
+	"api_gateway/internal/clientip"
 	"api_gateway/internal/config"
 	"api_gateway/internal/domain/errors"
 	"api_gateway/internal/domain/models"
+	"api_gateway/internal/grpc/grpc_errors"
+	"api_gateway/internal/grpc/resilience"
+	"api_gateway/internal/metrics"
 	"api_gateway/logger"
 	"context"
 	"fmt"
+	"github.com/deeelis/platform/requestid"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 	"log/slog"
 	"time"
@@ -32,20 +39,31 @@ func NewAuthClient(cfg *config.AuthConfig, log *slog.Logger) (*AuthClient, error
 		slog.Duration("timeout", cfg.Timeout))
 
 	startTime := time.Now()
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	conn, err := grpc.DialContext(
-		ctx,
-		cfg.ServiceAddress,
+	conn, err := resilience.NewConn(resilience.Target{
+		Name:    "auth",
+		Service: authpb.AuthService_ServiceDesc.ServiceName,
+		Address: cfg.ServiceAddress,
+		Timeout: cfg.Timeout,
+		// Login и Refresh выдают токены, их не повторяем; повторный выход ничего не меняет
+		Idempotent: []string{"ValidateToken", "Logout", "LogoutAll", "GetJWKS"},
+		Config:     cfg.ResilienceConfig,
+	}, log,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+			otelgrpc.WithFilter(filters.Not(filters.HealthCheck())))),
+		grpc.WithChainUnaryInterceptor(
+			requestid.UnaryClientInterceptor(),
+			clientip.UnaryClientInterceptor(),
+			metrics.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(
+			requestid.StreamClientInterceptor(),
+			metrics.StreamClientInterceptor()),
 	)
 	if err != nil {
-		log.Error("failed to connect to auth service",
+		log.Error("failed to create auth client",
 			logger.Err(err),
 			slog.Duration("duration", time.Since(startTime)))
-		return nil, fmt.Errorf("failed to connect to auth service: %w", err)
+		return nil, err
 	}
 
 	log.Info("auth gRPC client initialized successfully",
@@ -70,9 +88,6 @@ func (c *AuthClient) Register(ctx context.Context, email, password, name string)
 	log.Info("registering new user")
 	startTime := time.Now()
 
-	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
-	defer cancel()
-
 	resp, err := c.client.Register(ctx, &authpb.RegisterRequest{
 		Email:    email,
 		Password: password,
@@ -84,7 +99,7 @@ func (c *AuthClient) Register(ctx context.Context, email, password, name string)
 			logger.Err(err),
 			slog.String("grpc_code", grpcStatus.Code().String()),
 			slog.Duration("duration", time.Since(startTime)))
-		return nil, mapGRPCError(err)
+		return nil, grpc_errors.Decode(err)
 	}
 
 	log.Info("user registered successfully",
@@ -106,9 +121,6 @@ func (c *AuthClient) Login(ctx context.Context, email, password string) (*models
 	log.Info("authenticating user")
 	startTime := time.Now()
 
-	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
-	defer cancel()
-
 	resp, err := c.client.Login(ctx, &authpb.LoginRequest{
 		Email:    email,
 		Password: password,
@@ -119,7 +131,7 @@ func (c *AuthClient) Login(ctx context.Context, email, password string) (*models
 			logger.Err(err),
 			slog.String("grpc_code", grpcStatus.Code().String()),
 			slog.Duration("duration", time.Since(startTime)))
-		return nil, mapGRPCError(err)
+		return nil, grpc_errors.Decode(err)
 	}
 
 	log.Info("user authenticated successfully",
@@ -141,9 +153,6 @@ func (c *AuthClient) ValidateToken(ctx context.Context, token string) (string, e
 	log.Debug("validating token")
 	startTime := time.Now()
 
-	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
-	defer cancel()
-
 	resp, err := c.client.ValidateToken(ctx, &authpb.ValidateTokenRequest{
 		Token: token,
 	})
@@ -153,7 +162,7 @@ func (c *AuthClient) ValidateToken(ctx context.Context, token string) (string, e
 			logger.Err(err),
 			slog.String("grpc_code", grpcStatus.Code().String()),
 			slog.Duration("duration", time.Since(startTime)))
-		return "", mapGRPCError(err)
+		return "", grpc_errors.Decode(err)
 	}
 
 	if !resp.Valid {
@@ -179,9 +188,6 @@ func (c *AuthClient) RefreshToken(ctx context.Context, refreshToken string) (*mo
 	log.Info("refreshing token")
 	startTime := time.Now()
 
-	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
-	defer cancel()
-
 	resp, err := c.client.RefreshToken(ctx, &authpb.RefreshTokenRequest{
 		RefreshToken: refreshToken,
 	})
@@ -191,7 +197,7 @@ func (c *AuthClient) RefreshToken(ctx context.Context, refreshToken string) (*mo
 			logger.Err(err),
 			slog.String("grpc_code", grpcStatus.Code().String()),
 			slog.Duration("duration", time.Since(startTime)))
-		return nil, mapGRPCError(err)
+		return nil, grpc_errors.Decode(err)
 	}
 
 	log.Info("token refreshed successfully",
@@ -203,6 +209,195 @@ func (c *AuthClient) RefreshToken(ctx context.Context, refreshToken string) (*mo
 	}, nil
 }
 
+func (c *AuthClient) Logout(ctx context.Context, token string) error {
+	const op = "grpc.AuthClient.Logout"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int("token_length", len(token)),
+	)
+
+	log.Info("logging out session")
+	startTime := time.Now()
+
+	_, err := c.client.Logout(ctx, &authpb.LogoutRequest{
+		Token: token,
+	})
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		log.Error("logout failed",
+			logger.Err(err),
+			slog.String("grpc_code", grpcStatus.Code().String()),
+			slog.Duration("duration", time.Since(startTime)))
+		return grpc_errors.Decode(err)
+	}
+
+	log.Info("session logged out successfully",
+		slog.Duration("duration", time.Since(startTime)))
+
+	return nil
+}
+
+func (c *AuthClient) LogoutAll(ctx context.Context, token string) error {
+	const op = "grpc.AuthClient.LogoutAll"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int("token_length", len(token)),
+	)
+
+	log.Info("logging out all sessions")
+	startTime := time.Now()
+
+	_, err := c.client.LogoutAll(ctx, &authpb.LogoutAllRequest{
+		Token: token,
+	})
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		log.Error("logout failed",
+			logger.Err(err),
+			slog.String("grpc_code", grpcStatus.Code().String()),
+			slog.Duration("duration", time.Since(startTime)))
+		return grpc_errors.Decode(err)
+	}
+
+	log.Info("all sessions logged out successfully",
+		slog.Duration("duration", time.Since(startTime)))
+
+	return nil
+}
+
+func (c *AuthClient) GetJWKS(ctx context.Context) ([]*models.JWK, error) {
+	const op = "grpc.AuthClient.GetJWKS"
+	log := c.log.With(slog.String("op", op))
+
+	log.Debug("fetching jwks")
+	startTime := time.Now()
+
+	resp, err := c.client.GetJWKS(ctx, &authpb.GetJWKSRequest{})
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		log.Error("jwks fetch failed",
+			logger.Err(err),
+			slog.String("grpc_code", grpcStatus.Code().String()),
+			slog.Duration("duration", time.Since(startTime)))
+		return nil, grpc_errors.Decode(err)
+	}
+
+	keys := make([]*models.JWK, 0, len(resp.Keys))
+	for _, key := range resp.Keys {
+		keys = append(keys, &models.JWK{
+			Kid: key.Kid,
+			Kty: key.Kty,
+			Crv: key.Crv,
+			X:   key.X,
+			Alg: key.Alg,
+			Use: key.Use,
+		})
+	}
+
+	log.Debug("jwks fetched",
+		slog.Int("keys", len(keys)),
+		slog.Duration("duration", time.Since(startTime)))
+
+	return keys, nil
+}
+
+func (c *AuthClient) VerifyEmail(ctx context.Context, token string) error {
+	const op = "grpc.AuthClient.VerifyEmail"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int("token_length", len(token)),
+	)
+
+	log.Info("verifying email")
+	startTime := time.Now()
+
+	_, err := c.client.VerifyEmail(ctx, &authpb.VerifyEmailRequest{
+		Token: token,
+	})
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		log.Error("email verification failed",
+			logger.Err(err),
+			slog.String("grpc_code", grpcStatus.Code().String()),
+			slog.Duration("duration", time.Since(startTime)))
+		return grpc_errors.Decode(err)
+	}
+
+	log.Info("email verified successfully",
+		slog.Duration("duration", time.Since(startTime)))
+
+	return nil
+}
+
+func (c *AuthClient) RequestPasswordReset(ctx context.Context, email string) error {
+	const op = "grpc.AuthClient.RequestPasswordReset"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("email", email),
+	)
+
+	log.Info("requesting password reset")
+	startTime := time.Now()
+
+	_, err := c.client.RequestPasswordReset(ctx, &authpb.RequestPasswordResetRequest{
+		Email: email,
+	})
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		log.Error("password reset request failed",
+			logger.Err(err),
+			slog.String("grpc_code", grpcStatus.Code().String()),
+			slog.Duration("duration", time.Since(startTime)))
+		return grpc_errors.Decode(err)
+	}
+
+	log.Info("password reset requested",
+		slog.Duration("duration", time.Since(startTime)))
+
+	return nil
+}
+
+func (c *AuthClient) ConfirmPasswordReset(ctx context.Context, token, password string) error {
+	const op = "grpc.AuthClient.ConfirmPasswordReset"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int("token_length", len(token)),
+	)
+
+	log.Info("confirming password reset")
+	startTime := time.Now()
+
+	_, err := c.client.ConfirmPasswordReset(ctx, &authpb.ConfirmPasswordResetRequest{
+		Token:    token,
+		Password: password,
+	})
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		log.Error("password reset confirmation failed",
+			logger.Err(err),
+			slog.String("grpc_code", grpcStatus.Code().String()),
+			slog.Duration("duration", time.Since(startTime)))
+		return grpc_errors.Decode(err)
+	}
+
+	log.Info("password reset successfully",
+		slog.Duration("duration", time.Since(startTime)))
+
+	return nil
+}
+
+// Ping спрашивает у auth_service статус по протоколу grpc.health.v1 через то же соединение.
+func (c *AuthClient) Ping(ctx context.Context) error {
+	resp, err := healthpb.NewHealthClient(c.conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("auth service is %s", resp.GetStatus())
+	}
+	return nil
+}
+
 func (c *AuthClient) Close() error {
 	const op = "grpc.AuthClient.Close"
 	log := c.log.With(slog.String("op", op))
@@ -216,19 +411,3 @@ func (c *AuthClient) Close() error {
 	log.Info("gRPC connection closed successfully")
 	return nil
 }
-
-func mapGRPCError(err error) error {
-	grpcStatus, _ := status.FromError(err)
-	switch grpcStatus.Code() {
-	case codes.NotFound:
-		return errors.ErrUserNotFound
-	case codes.AlreadyExists:
-		return errors.ErrUserAlreadyExists
-	case codes.Unauthenticated:
-		return errors.ErrInvalidCredentials
-	case codes.InvalidArgument:
-		return errors.ErrInvalidInput
-	default:
-		return errors.ErrInternalServer
-	}
-}
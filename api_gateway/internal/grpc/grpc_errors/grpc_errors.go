@@ -0,0 +1,80 @@
+package grpc_errors
+
+import (
+	"api_gateway/internal/domain/errors"
+	"context"
+	goerrors "errors"
+
+	errorsv1 "github.com/deeelis/errors-protos/gen/go/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Decode переводит ошибку вызова gRPC в *errors.Error. Код берётся из
+// google.rpc.ErrorInfo, а если сервис его не прислал — выводится из кода статуса.
+func Decode(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		if goerrors.Is(err, context.DeadlineExceeded) || goerrors.Is(err, context.Canceled) {
+			return errors.New(errorsv1.ErrorCode_UNAVAILABLE, "", err)
+		}
+		return errors.New(errorsv1.ErrorCode_INTERNAL, "", err)
+	}
+
+	code := codeFromDetails(st)
+	if code == errorsv1.ErrorCode_ERROR_CODE_UNSPECIFIED {
+		code = codeFromStatus(st.Code())
+	}
+
+	message := st.Message()
+	switch code {
+	// чужой контент неотличим от несуществующего
+	case errorsv1.ErrorCode_CONTENT_ACCESS_DENIED:
+		code = errorsv1.ErrorCode_CONTENT_NOT_FOUND
+		message = "content not found"
+	// текст внутренних ошибок сервисов наружу не отдаётся
+	case errorsv1.ErrorCode_INTERNAL, errorsv1.ErrorCode_UNAVAILABLE:
+		message = ""
+	}
+
+	return errors.New(code, message, err)
+}
+
+func codeFromDetails(st *status.Status) errorsv1.ErrorCode {
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok {
+			continue
+		}
+		if code, ok := errorsv1.ErrorCode_value[info.Reason]; ok {
+			return errorsv1.ErrorCode(code)
+		}
+	}
+	return errorsv1.ErrorCode_ERROR_CODE_UNSPECIFIED
+}
+
+func codeFromStatus(c codes.Code) errorsv1.ErrorCode {
+	switch c {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return errorsv1.ErrorCode_INVALID_ARGUMENT
+	case codes.NotFound:
+		return errorsv1.ErrorCode_NOT_FOUND
+	case codes.AlreadyExists, codes.FailedPrecondition, codes.Aborted:
+		return errorsv1.ErrorCode_CONFLICT
+	case codes.PermissionDenied:
+		return errorsv1.ErrorCode_PERMISSION_DENIED
+	case codes.Unauthenticated:
+		return errorsv1.ErrorCode_UNAUTHENTICATED
+	case codes.ResourceExhausted:
+		return errorsv1.ErrorCode_RATE_LIMITED
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return errorsv1.ErrorCode_UNAVAILABLE
+	default:
+		return errorsv1.ErrorCode_INTERNAL
+	}
+}
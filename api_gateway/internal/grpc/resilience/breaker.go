@@ -0,0 +1,163 @@
+package resilience
+
+import (
+	"api_gateway/internal/config"
+	"api_gateway/internal/metrics"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Состояния автомата; значения совпадают с grpc_client_circuit_state
+const (
+	StateClosed   = 0
+	StateHalfOpen = 1
+	StateOpen     = 2
+)
+
+var errCircuitOpen = status.Error(codes.Unavailable, "circuit breaker is open")
+
+// Breaker считает сбои зависимости подряд и на время OpenTimeout перестаёт
+// её вызывать, чтобы запросы шлюза не ждали дедлайна на заведомо упавшем сервисе.
+type Breaker struct {
+	name      string
+	threshold int
+	timeout   time.Duration
+	probes    int
+	log       *slog.Logger
+
+	mu       sync.Mutex
+	state    int
+	failures int
+	inFlight int
+	openedAt time.Time
+}
+
+var (
+	breakersMu sync.Mutex
+	breakers   = map[string]*Breaker{}
+)
+
+// breakerFor возвращает общий автомат зависимости: клиентов одного сервиса
+// в шлюзе несколько, а состояние у сервиса одно.
+func breakerFor(name string, cfg config.CircuitBreakerConfig, log *slog.Logger) *Breaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	if b, ok := breakers[name]; ok {
+		return b
+	}
+
+	b := &Breaker{
+		name:      name,
+		threshold: orDefault(cfg.FailureThreshold, defaultFailureThreshold),
+		timeout:   orDefault(cfg.OpenTimeout, defaultOpenTimeout),
+		probes:    orDefault(cfg.HalfOpenRequests, defaultHalfOpenRequests),
+		log:       log.With(slog.String("target", name)),
+	}
+	metrics.SetCircuitState(name, StateClosed)
+	breakers[name] = b
+	return b
+}
+
+// allow решает, пропустить ли вызов. Открытый автомат по истечении
+// OpenTimeout переходит в полуоткрытое состояние и пропускает пробные вызовы.
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.timeout {
+			return false
+		}
+		b.setState(StateHalfOpen)
+		b.inFlight = 0
+		fallthrough
+	case StateHalfOpen:
+		if b.inFlight >= b.probes {
+			return false
+		}
+		b.inFlight++
+	}
+	return true
+}
+
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen && b.inFlight > 0 {
+		b.inFlight--
+	}
+
+	if !isFailure(err) {
+		b.failures = 0
+		if b.state != StateClosed {
+			b.setState(StateClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.threshold {
+		b.openedAt = time.Now()
+		if b.state != StateOpen {
+			b.setState(StateOpen)
+		}
+	}
+}
+
+func (b *Breaker) setState(state int) {
+	b.state = state
+	metrics.SetCircuitState(b.name, state)
+	b.log.Warn("circuit breaker state changed",
+		slog.Int("state", state),
+		slog.Int("failures", b.failures))
+}
+
+// isFailure отделяет недоступность сервиса от ошибок самого запроса:
+// NOT_FOUND или INVALID_ARGUMENT говорят о том, что сервис жив.
+func isFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *Breaker) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !b.allow() {
+			metrics.ObserveCircuitRejected(b.name)
+			return errCircuitOpen
+		}
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		b.record(err)
+		return err
+	}
+}
+
+// StreamClientInterceptor учитывает только установку потока: обрыв
+// долгой подписки не говорит о недоступности сервиса.
+func (b *Breaker) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if !b.allow() {
+			metrics.ObserveCircuitRejected(b.name)
+			return nil, errCircuitOpen
+		}
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		b.record(err)
+		return stream, err
+	}
+}
@@ -0,0 +1,174 @@
+// Package resilience собирает gRPC-соединение шлюза с зависимостью: балансировку
+// по адресам из DNS, повторы идемпотентных методов, дедлайны по методам
+// и circuit breaker. Соединение устанавливается в фоне, поэтому недоступная
+// при старте зависимость не мешает шлюзу подняться.
+package resilience
+
+import (
+	"api_gateway/internal/config"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	LoadBalancingRoundRobin = "round_robin"
+	LoadBalancingPickFirst  = "pick_first"
+)
+
+const (
+	defaultTimeout           = 5 * time.Second
+	defaultMaxAttempts       = 3
+	defaultInitialBackoff    = 100 * time.Millisecond
+	defaultMaxBackoff        = time.Second
+	defaultBackoffMultiplier = 2.0
+	defaultFailureThreshold  = 5
+	defaultOpenTimeout       = 10 * time.Second
+	defaultHalfOpenRequests  = 1
+)
+
+// Target описывает зависимость: Service — полное имя gRPC-сервиса,
+// Idempotent — методы, которые безопасно повторять.
+type Target struct {
+	Name       string
+	Service    string
+	Address    string
+	Timeout    time.Duration
+	Idempotent []string
+	Config     config.ResilienceConfig
+}
+
+// NewConn создаёт соединение без ожидания готовности. opts клиента
+// (трассировка, request id, метрики) выполняются раньше перехватчиков устойчивости.
+func NewConn(target Target, log *slog.Logger, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	serviceConfig, err := buildServiceConfig(target)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts,
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		grpc.WithChainUnaryInterceptor(deadlineInterceptor(target)),
+	)
+	if !target.Config.CircuitBreaker.Disabled {
+		breaker := breakerFor(target.Name, target.Config.CircuitBreaker, log)
+		opts = append(opts,
+			grpc.WithChainUnaryInterceptor(breaker.UnaryClientInterceptor()),
+			grpc.WithChainStreamInterceptor(breaker.StreamClientInterceptor()),
+		)
+	}
+
+	conn, err := grpc.NewClient(dnsTarget(target.Address), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s client: %w", target.Name, err)
+	}
+	// без этого соединение ждало бы первого вызова
+	conn.Connect()
+	return conn, nil
+}
+
+// dnsTarget включает DNS-резолвер gRPC: он отдаёт балансировщику все адреса
+// реплик, а не один, как системный резолвер при обычном dial.
+func dnsTarget(address string) string {
+	if strings.Contains(address, "://") || strings.HasPrefix(address, "unix:") {
+		return address
+	}
+	return "dns:///" + address
+}
+
+// deadlineInterceptor ставит дедлайн метода, если у вызова нет более раннего.
+func deadlineInterceptor(target Target) grpc.UnaryClientInterceptor {
+	timeout := orDefault(target.Timeout, defaultTimeout)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		d := timeout
+		if override, ok := target.Config.Deadlines[method[strings.LastIndex(method, "/")+1:]]; ok && override > 0 {
+			d = override
+		}
+		if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > d {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, d)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+type serviceConfig struct {
+	LoadBalancingConfig []map[string]struct{} `json:"loadBalancingConfig"`
+	MethodConfig        []methodConfig        `json:"methodConfig,omitempty"`
+}
+
+type methodConfig struct {
+	Name        []methodName `json:"name"`
+	RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+}
+
+type methodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method"`
+}
+
+type retryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+// buildServiceConfig описывает балансировку и повторы в формате
+// gRPC service config. Повторяются только UNAVAILABLE: запрос до сервиса не дошёл
+// или реплика выключается, а вторая попытка уходит на другую реплику.
+func buildServiceConfig(target Target) (string, error) {
+	cfg := target.Config
+
+	policy := orDefault(cfg.LoadBalancing, LoadBalancingRoundRobin)
+	if policy != LoadBalancingRoundRobin && policy != LoadBalancingPickFirst {
+		return "", fmt.Errorf("%s: unknown load balancing policy %q", target.Name, policy)
+	}
+
+	sc := serviceConfig{
+		LoadBalancingConfig: []map[string]struct{}{{policy: {}}},
+	}
+
+	attempts := orDefault(cfg.Retry.MaxAttempts, defaultMaxAttempts)
+	if attempts > 1 && len(target.Idempotent) > 0 {
+		names := make([]methodName, len(target.Idempotent))
+		for i, method := range target.Idempotent {
+			names[i] = methodName{Service: target.Service, Method: method}
+		}
+		sc.MethodConfig = []methodConfig{{
+			Name: names,
+			RetryPolicy: &retryPolicy{
+				MaxAttempts:          attempts,
+				InitialBackoff:       seconds(orDefault(cfg.Retry.InitialBackoff, defaultInitialBackoff)),
+				MaxBackoff:           seconds(orDefault(cfg.Retry.MaxBackoff, defaultMaxBackoff)),
+				BackoffMultiplier:    orDefault(cfg.Retry.BackoffMultiplier, defaultBackoffMultiplier),
+				RetryableStatusCodes: []string{"UNAVAILABLE"},
+			},
+		}}
+	}
+
+	raw, err := json.Marshal(sc)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%gs", d.Seconds())
+}
+
+func orDefault[T comparable](value, fallback T) T {
+	var zero T
+	if value == zero {
+		return fallback
+	}
+	return value
+}
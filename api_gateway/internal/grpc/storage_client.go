@@ -3,10 +3,21 @@ package grpc
 import (
 	"api_gateway/internal/domain/models"
 	"context"
+	"time"
 )
 
 type StorageClient interface {
-	GetContent(ctx context.Context, contentID string) (*models.ContentStatus, error)
-	RegisterContent(ctx context.Context, contentID string, contentType string) error
+	GetContent(ctx context.Context, userID string, contentID string, moderator bool) (*models.ContentStatus, error)
+	RegisterContent(ctx context.Context, content *models.Content) error
+	RegisterContentBatch(ctx context.Context, contents []*models.Content) error
+	ListContent(ctx context.Context, userID string, filter *models.ContentFilter) (*models.ContentPage, error)
+	DeleteContent(ctx context.Context, userID string, contentID string) (time.Time, error)
+	RestoreContent(ctx context.Context, userID string, contentID string) error
+	WatchContent(ctx context.Context, userID string, contentID string) (<-chan *models.ContentEvent, error)
+	CreateWebhook(ctx context.Context, userID string, url string, secret string) (*models.Webhook, error)
+	ListWebhooks(ctx context.Context, userID string) ([]*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, userID string, webhookID string) error
+	ListWebhookDeliveries(ctx context.Context, userID string, webhookID string, status string, limit int) ([]*models.WebhookDelivery, error)
+	RedeliverWebhook(ctx context.Context, userID string, webhookID string, deliveryID string) (*models.WebhookDelivery, error)
 	Close() error
 }
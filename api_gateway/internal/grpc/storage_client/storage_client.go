@@ -2,19 +2,39 @@ package storage_client
 
 import (
 	"api_gateway/internal/config"
+	"api_gateway/internal/domain/errors"
 	"api_gateway/internal/domain/models"
+	"api_gateway/internal/grpc/grpc_errors"
+	"api_gateway/internal/grpc/resilience"
+	"api_gateway/internal/metrics"
 	"api_gateway/logger"
 	"context"
 	"fmt"
+	"github.com/deeelis/platform/requestid"
 	storagepb "github.com/deeelis/storage-protos/gen/go/storage"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
 	"log/slog"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
+// idempotentMethods повторяются при UNAVAILABLE; методы, создающие записи, — нет
+var idempotentMethods = []string{
+	"GetContent",
+	"ListContent",
+	"WatchContent",
+	"ListWebhooks",
+	"ListWebhookDeliveries",
+}
+
 type StorageClient struct {
 	client storagepb.StorageServiceClient
 	conn   *grpc.ClientConn
@@ -30,20 +50,29 @@ func NewStorageClient(cfg *config.StorageConfig, log *slog.Logger) (*StorageClie
 		slog.Duration("timeout", cfg.Timeout))
 
 	startTime := time.Now()
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	conn, err := grpc.DialContext(
-		ctx,
-		cfg.ServiceAddress,
+	conn, err := resilience.NewConn(resilience.Target{
+		Name:       "storage",
+		Service:    storagepb.StorageService_ServiceDesc.ServiceName,
+		Address:    cfg.ServiceAddress,
+		Timeout:    cfg.Timeout,
+		Idempotent: idempotentMethods,
+		Config:     cfg.ResilienceConfig,
+	}, log,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+			otelgrpc.WithFilter(filters.Not(filters.HealthCheck())))),
+		grpc.WithChainUnaryInterceptor(
+			requestid.UnaryClientInterceptor(),
+			metrics.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(
+			requestid.StreamClientInterceptor(),
+			metrics.StreamClientInterceptor()),
 	)
 	if err != nil {
-		log.Error("failed to connect to storage service",
+		log.Error("failed to create storage client",
 			logger.Err(err),
 			slog.Duration("duration", time.Since(startTime)))
-		return nil, fmt.Errorf("failed to connect to storage service: %w", err)
+		return nil, err
 	}
 
 	log.Info("storage client initialized successfully",
@@ -57,21 +86,23 @@ func NewStorageClient(cfg *config.StorageConfig, log *slog.Logger) (*StorageClie
 	}, nil
 }
 
-func (c *StorageClient) GetContent(ctx context.Context, contentID string) (*models.ContentStatus, error) {
+// GetContent возвращает контент владельца; для модератора проверка владельца не выполняется.
+func (c *StorageClient) GetContent(ctx context.Context, userID string, contentID string, moderator bool) (*models.ContentStatus, error) {
 	const op = "storage_client.GetContent"
 	log := c.log.With(
 		slog.String("op", op),
+		slog.String("user_id", userID),
 		slog.String("content_id", contentID),
+		slog.Bool("moderator", moderator),
 	)
 
 	log.Info("getting content from storage")
 	startTime := time.Now()
 
-	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
-	defer cancel()
-
 	resp, err := c.client.GetContent(ctx, &storagepb.ContentRequest{
 		ContentId: contentID,
+		UserId:    userID,
+		Moderator: moderator,
 	})
 	if err != nil {
 		grpcStatus, _ := status.FromError(err)
@@ -79,7 +110,7 @@ func (c *StorageClient) GetContent(ctx context.Context, contentID string) (*mode
 			logger.Err(err),
 			slog.String("grpc_code", grpcStatus.Code().String()),
 			slog.Duration("duration", time.Since(startTime)))
-		return nil, fmt.Errorf("failed to get content status: %w", err)
+		return nil, grpc_errors.Decode(err)
 	}
 
 	contentStatus := &models.ContentStatus{
@@ -99,10 +130,10 @@ func (c *StorageClient) GetContent(ctx context.Context, contentID string) (*mode
 		}
 	case storagepb.ContentType_IMAGE:
 		if image := resp.GetImage(); image != nil {
-			contentStatus.OriginalContent = image.ImageUrl
+			contentStatus.ObjectKey = image.S3Key
 			contentStatus.Analysis = convertMetadata(image.AnalysisMetadata)
 			log.Debug("retrieved image content",
-				slog.String("image_url", image.ImageUrl),
+				slog.String("s3_key", image.S3Key),
 				slog.Int("metadata_items", len(image.AnalysisMetadata)))
 		}
 	}
@@ -114,51 +145,310 @@ func (c *StorageClient) GetContent(ctx context.Context, contentID string) (*mode
 	return contentStatus, nil
 }
 
-func (c *StorageClient) RegisterContent(ctx context.Context, contentID string, contentType string) error {
+func (c *StorageClient) RegisterContent(ctx context.Context, content *models.Content) error {
 	const op = "storage_client.RegisterContent"
 	log := c.log.With(
 		slog.String("op", op),
-		slog.String("content_id", contentID),
-		slog.String("content_type", contentType),
+		slog.String("user_id", content.UserID),
+		slog.String("content_id", content.ID),
+		slog.String("content_type", string(content.Type)),
 	)
 
 	log.Info("registering content in storage")
 	startTime := time.Now()
 
-	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
-	defer cancel()
+	req, err := registerRequest(content)
+	if err != nil {
+		log.Error("invalid content type provided",
+			slog.Duration("duration", time.Since(startTime)))
+		return err
+	}
+
+	_, err = c.client.RegisterContent(ctx, req)
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		log.Error("failed to register content",
+			logger.Err(err),
+			slog.String("grpc_code", grpcStatus.Code().String()),
+			slog.Duration("duration", time.Since(startTime)))
+		return grpc_errors.Decode(err)
+	}
+
+	log.Info("content registered successfully",
+		slog.Duration("duration", time.Since(startTime)))
 
+	return nil
+}
+
+// registerRequest передаёт storage и объект изображения, чтобы тот был
+// известен с регистрации: иначе объект контента, удалённого до вердикта,
+// некому было бы удалить.
+func registerRequest(content *models.Content) (*storagepb.RegisterContentRequest, error) {
 	var ct storagepb.ContentType
-	switch contentType {
-	case "text":
+	switch content.Type {
+	case models.ContentTypeText:
 		ct = storagepb.ContentType_TEXT
-	case "image":
+	case models.ContentTypeImage:
 		ct = storagepb.ContentType_IMAGE
 	default:
-		log.Error("invalid content type provided",
+		return nil, fmt.Errorf("invalid content type: %s", content.Type)
+	}
+
+	return &storagepb.RegisterContentRequest{
+		ContentId: content.ID,
+		Type:      ct,
+		UserId:    content.UserID,
+		Bucket:    content.Bucket,
+		Key:       content.Key,
+		Checksum:  content.Checksum,
+	}, nil
+}
+
+func (c *StorageClient) RegisterContentBatch(ctx context.Context, contents []*models.Content) error {
+	const op = "storage_client.RegisterContentBatch"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int("batch_size", len(contents)),
+	)
+
+	log.Info("registering content batch in storage")
+	startTime := time.Now()
+
+	items := make([]*storagepb.RegisterContentRequest, 0, len(contents))
+	for _, content := range contents {
+		item, err := registerRequest(content)
+		if err != nil {
+			log.Error("invalid content type provided",
+				slog.String("content_id", content.ID),
+				slog.String("content_type", string(content.Type)))
+			return err
+		}
+		items = append(items, item)
+	}
+
+	resp, err := c.client.RegisterContentBatch(ctx, &storagepb.RegisterContentBatchRequest{
+		Items: items,
+	})
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		log.Error("failed to register content batch",
+			logger.Err(err),
+			slog.String("grpc_code", grpcStatus.Code().String()),
 			slog.Duration("duration", time.Since(startTime)))
-		return fmt.Errorf("invalid content type: %s", contentType)
+		return grpc_errors.Decode(err)
 	}
 
-	_, err := c.client.RegisterContent(ctx, &storagepb.RegisterContentRequest{
+	log.Info("content batch registered successfully",
+		slog.Int("registered", int(resp.Registered)),
+		slog.Duration("duration", time.Since(startTime)))
+
+	return nil
+}
+
+func (c *StorageClient) ListContent(ctx context.Context, userID string, filter *models.ContentFilter) (*models.ContentPage, error) {
+	const op = "storage_client.ListContent"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+	)
+
+	log.Info("listing content from storage")
+	startTime := time.Now()
+
+	req := &storagepb.ListContentRequest{
+		UserId:    userID,
+		PageSize:  int32(filter.Limit),
+		PageToken: filter.Cursor,
+	}
+
+	if filter.Status != "" {
+		st, ok := storagepb.ProcessingStatus_value[strings.ToUpper(filter.Status)]
+		if !ok {
+			log.Warn("invalid status filter", slog.String("status", filter.Status))
+			return nil, errors.ErrInvalidInput
+		}
+		req.Status = storagepb.ProcessingStatus(st).Enum()
+	}
+	if filter.Type != "" {
+		ct, ok := storagepb.ContentType_value[strings.ToUpper(filter.Type)]
+		if !ok {
+			log.Warn("invalid type filter", slog.String("type", filter.Type))
+			return nil, errors.ErrInvalidContentType
+		}
+		req.Type = storagepb.ContentType(ct).Enum()
+	}
+	if filter.CreatedAfter != nil {
+		req.CreatedAfter = timestamppb.New(*filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		req.CreatedBefore = timestamppb.New(*filter.CreatedBefore)
+	}
+
+	resp, err := c.client.ListContent(ctx, req)
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		log.Error("failed to list content",
+			logger.Err(err),
+			slog.String("grpc_code", grpcStatus.Code().String()),
+			slog.Duration("duration", time.Since(startTime)))
+		return nil, grpc_errors.Decode(err)
+	}
+
+	page := &models.ContentPage{
+		Items:      make([]models.ContentSummary, 0, len(resp.Items)),
+		NextCursor: resp.NextPageToken,
+	}
+	for _, item := range resp.Items {
+		page.Items = append(page.Items, models.ContentSummary{
+			ID:        item.ContentId,
+			Type:      item.Type.String(),
+			Status:    item.Status.String(),
+			CreatedAt: item.CreatedAt.AsTime(),
+			UpdatedAt: item.UpdatedAt.AsTime(),
+		})
+	}
+
+	log.Info("content listed successfully",
+		slog.Int("items", len(page.Items)),
+		slog.Bool("has_more", page.NextCursor != ""),
+		slog.Duration("duration", time.Since(startTime)))
+
+	return page, nil
+}
+
+func (c *StorageClient) DeleteContent(ctx context.Context, userID string, contentID string) (time.Time, error) {
+	const op = "storage_client.DeleteContent"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("content_id", contentID),
+	)
+
+	log.Info("deleting content in storage")
+	startTime := time.Now()
+
+	resp, err := c.client.DeleteContent(ctx, &storagepb.DeleteContentRequest{
 		ContentId: contentID,
-		Type:      ct,
+		UserId:    userID,
 	})
 	if err != nil {
 		grpcStatus, _ := status.FromError(err)
-		log.Error("failed to register content",
+		log.Error("failed to delete content",
 			logger.Err(err),
 			slog.String("grpc_code", grpcStatus.Code().String()),
 			slog.Duration("duration", time.Since(startTime)))
-		return fmt.Errorf("failed to register content: %w", err)
+		return time.Time{}, grpc_errors.Decode(err)
 	}
 
-	log.Info("content registered successfully",
+	log.Info("content deleted successfully",
+		slog.Time("purge_after", resp.PurgeAfter.AsTime()),
+		slog.Duration("duration", time.Since(startTime)))
+
+	return resp.PurgeAfter.AsTime(), nil
+}
+
+func (c *StorageClient) RestoreContent(ctx context.Context, userID string, contentID string) error {
+	const op = "storage_client.RestoreContent"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("content_id", contentID),
+	)
+
+	log.Info("restoring content in storage")
+	startTime := time.Now()
+
+	_, err := c.client.RestoreContent(ctx, &storagepb.RestoreContentRequest{
+		ContentId: contentID,
+		UserId:    userID,
+	})
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		log.Error("failed to restore content",
+			logger.Err(err),
+			slog.String("grpc_code", grpcStatus.Code().String()),
+			slog.Duration("duration", time.Since(startTime)))
+		return grpc_errors.Decode(err)
+	}
+
+	log.Info("content restored successfully",
 		slog.Duration("duration", time.Since(startTime)))
 
 	return nil
 }
 
+// WatchContent открывает поток событий по контенту. Первое событие читается
+// синхронно, чтобы ошибки доступа вернулись до начала отдачи ответа клиенту.
+// Канал закрывается, когда поток завершён или ctx отменён.
+func (c *StorageClient) WatchContent(ctx context.Context, userID string, contentID string) (<-chan *models.ContentEvent, error) {
+	const op = "storage_client.WatchContent"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("content_id", contentID),
+	)
+
+	log.Info("watching content in storage")
+	startTime := time.Now()
+
+	stream, err := c.client.WatchContent(ctx, &storagepb.WatchContentRequest{
+		ContentId: contentID,
+		UserId:    userID,
+	})
+	if err != nil {
+		return nil, watchError(log, err, startTime)
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		return nil, watchError(log, err, startTime)
+	}
+
+	events := make(chan *models.ContentEvent, 1)
+	events <- convertEvent(first)
+	go func() {
+		defer close(events)
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					log.Warn("content event stream interrupted", logger.Err(err))
+				}
+				log.Info("content event stream closed",
+					slog.Duration("duration", time.Since(startTime)))
+				return
+			}
+			select {
+			case events <- convertEvent(event):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func watchError(log *slog.Logger, err error, startTime time.Time) error {
+	grpcStatus, _ := status.FromError(err)
+	log.Error("failed to watch content",
+		logger.Err(err),
+		slog.String("grpc_code", grpcStatus.Code().String()),
+		slog.Duration("duration", time.Since(startTime)))
+	return grpc_errors.Decode(err)
+}
+
+func convertEvent(event *storagepb.ContentEvent) *models.ContentEvent {
+	return &models.ContentEvent{
+		ContentID:       event.ContentId,
+		Type:            event.Type.String(),
+		Status:          event.Status.String(),
+		Analysis:        convertMetadata(event.AnalysisMetadata),
+		OriginalContent: event.OriginalContent,
+		OccurredAt:      event.OccurredAt.AsTime(),
+	}
+}
+
 func convertMetadata(metadata map[string]string) map[string]interface{} {
 	result := make(map[string]interface{})
 	for k, v := range metadata {
@@ -167,6 +457,18 @@ func convertMetadata(metadata map[string]string) map[string]interface{} {
 	return result
 }
 
+// Ping спрашивает у storage_service статус по протоколу grpc.health.v1 через то же соединение.
+func (c *StorageClient) Ping(ctx context.Context) error {
+	resp, err := healthpb.NewHealthClient(c.conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("storage service is %s", resp.GetStatus())
+	}
+	return nil
+}
+
 func (c *StorageClient) Close() error {
 	const op = "storage_client.Close"
 	log := c.log.With(slog.String("op", op))
@@ -0,0 +1,188 @@
+package storage_client
+
+import (
+	"api_gateway/internal/domain/models"
+	"api_gateway/internal/grpc/grpc_errors"
+	"api_gateway/logger"
+	"context"
+	storagepb "github.com/deeelis/storage-protos/gen/go/storage"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"time"
+)
+
+func (c *StorageClient) CreateWebhook(ctx context.Context, userID string, url string, secret string) (*models.Webhook, error) {
+	const op = "storage_client.CreateWebhook"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+	)
+
+	log.Info("creating webhook in storage")
+	startTime := time.Now()
+
+	resp, err := c.client.CreateWebhook(ctx, &storagepb.CreateWebhookRequest{
+		UserId: userID,
+		Url:    url,
+		Secret: secret,
+	})
+	if err != nil {
+		return nil, webhookError(log, err, startTime, "failed to create webhook")
+	}
+
+	webhook := convertWebhook(resp.Webhook)
+	webhook.Secret = resp.Secret
+
+	log.Info("webhook created successfully",
+		slog.String("webhook_id", webhook.ID),
+		slog.Duration("duration", time.Since(startTime)))
+
+	return webhook, nil
+}
+
+func (c *StorageClient) ListWebhooks(ctx context.Context, userID string) ([]*models.Webhook, error) {
+	const op = "storage_client.ListWebhooks"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+	)
+
+	log.Info("listing webhooks from storage")
+	startTime := time.Now()
+
+	resp, err := c.client.ListWebhooks(ctx, &storagepb.ListWebhooksRequest{
+		UserId: userID,
+	})
+	if err != nil {
+		return nil, webhookError(log, err, startTime, "failed to list webhooks")
+	}
+
+	webhooks := make([]*models.Webhook, 0, len(resp.Webhooks))
+	for _, webhook := range resp.Webhooks {
+		webhooks = append(webhooks, convertWebhook(webhook))
+	}
+
+	log.Info("webhooks listed successfully",
+		slog.Int("items", len(webhooks)),
+		slog.Duration("duration", time.Since(startTime)))
+
+	return webhooks, nil
+}
+
+func (c *StorageClient) DeleteWebhook(ctx context.Context, userID string, webhookID string) error {
+	const op = "storage_client.DeleteWebhook"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("webhook_id", webhookID),
+	)
+
+	log.Info("deleting webhook in storage")
+	startTime := time.Now()
+
+	_, err := c.client.DeleteWebhook(ctx, &storagepb.DeleteWebhookRequest{
+		UserId:    userID,
+		WebhookId: webhookID,
+	})
+	if err != nil {
+		return webhookError(log, err, startTime, "failed to delete webhook")
+	}
+
+	log.Info("webhook deleted successfully",
+		slog.Duration("duration", time.Since(startTime)))
+
+	return nil
+}
+
+func (c *StorageClient) ListWebhookDeliveries(ctx context.Context, userID string, webhookID string, deliveryStatus string, limit int) ([]*models.WebhookDelivery, error) {
+	const op = "storage_client.ListWebhookDeliveries"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("webhook_id", webhookID),
+	)
+
+	log.Info("listing webhook deliveries from storage")
+	startTime := time.Now()
+
+	resp, err := c.client.ListWebhookDeliveries(ctx, &storagepb.ListWebhookDeliveriesRequest{
+		UserId:    userID,
+		WebhookId: webhookID,
+		Status:    deliveryStatus,
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		return nil, webhookError(log, err, startTime, "failed to list webhook deliveries")
+	}
+
+	deliveries := make([]*models.WebhookDelivery, 0, len(resp.Deliveries))
+	for _, delivery := range resp.Deliveries {
+		deliveries = append(deliveries, convertDelivery(delivery))
+	}
+
+	log.Info("webhook deliveries listed successfully",
+		slog.Int("items", len(deliveries)),
+		slog.Duration("duration", time.Since(startTime)))
+
+	return deliveries, nil
+}
+
+func (c *StorageClient) RedeliverWebhook(ctx context.Context, userID string, webhookID string, deliveryID string) (*models.WebhookDelivery, error) {
+	const op = "storage_client.RedeliverWebhook"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("webhook_id", webhookID),
+		slog.String("delivery_id", deliveryID),
+	)
+
+	log.Info("scheduling webhook redelivery in storage")
+	startTime := time.Now()
+
+	resp, err := c.client.RedeliverWebhook(ctx, &storagepb.RedeliverWebhookRequest{
+		UserId:     userID,
+		WebhookId:  webhookID,
+		DeliveryId: deliveryID,
+	})
+	if err != nil {
+		return nil, webhookError(log, err, startTime, "failed to redeliver webhook")
+	}
+
+	log.Info("webhook redelivery scheduled successfully",
+		slog.Duration("duration", time.Since(startTime)))
+
+	return convertDelivery(resp.Delivery), nil
+}
+
+func webhookError(log *slog.Logger, err error, startTime time.Time, msg string) error {
+	grpcStatus, _ := status.FromError(err)
+	log.Error(msg,
+		logger.Err(err),
+		slog.String("grpc_code", grpcStatus.Code().String()),
+		slog.Duration("duration", time.Since(startTime)))
+	return grpc_errors.Decode(err)
+}
+
+func convertWebhook(webhook *storagepb.Webhook) *models.Webhook {
+	return &models.Webhook{
+		ID:        webhook.Id,
+		URL:       webhook.Url,
+		CreatedAt: webhook.CreatedAt.AsTime(),
+	}
+}
+
+func convertDelivery(delivery *storagepb.WebhookDelivery) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:            delivery.Id,
+		WebhookID:     delivery.WebhookId,
+		ContentID:     delivery.ContentId,
+		Event:         delivery.Event,
+		Status:        delivery.Status,
+		Attempts:      int(delivery.Attempts),
+		ResponseCode:  int(delivery.ResponseCode),
+		LastError:     delivery.LastError,
+		NextAttemptAt: delivery.NextAttemptAt.AsTime(),
+		CreatedAt:     delivery.CreatedAt.AsTime(),
+		UpdatedAt:     delivery.UpdatedAt.AsTime(),
+	}
+}
@@ -0,0 +1,26 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Record — состояние запроса с ключом идемпотентности. Пока Completed == false,
+// запрос считается выполняющимся и запись служит блокировкой.
+type Record struct {
+	Token       string `json:"token"`
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+type Store interface {
+	// Lock захватывает ключ. Если ключ уже занят, возвращает существующую запись.
+	Lock(ctx context.Context, key string, rec *Record, ttl time.Duration) (bool, *Record, error)
+	// Complete сохраняет ответ, если блокировка всё ещё принадлежит rec.Token.
+	Complete(ctx context.Context, key string, rec *Record, ttl time.Duration) error
+	// Release снимает блокировку, чтобы запрос можно было повторить.
+	Release(ctx context.Context, key string, token string) error
+}
@@ -0,0 +1,105 @@
+package redis_idempotency
+
+import (
+	"api_gateway/internal/config"
+	"api_gateway/internal/idempotency"
+	"api_gateway/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/deeelis/platform/tracing/redistrace"
+	"github.com/go-redis/redis/v8"
+	"log/slog"
+	"time"
+)
+
+// compareAndSet меняет запись, только если блокировка принадлежит тому же токену:
+// запрос, чья блокировка истекла, не должен затереть чужой результат.
+var compareAndSet = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if not current then
+  return 0
+end
+if cjson.decode(current)['token'] ~= ARGV[1] then
+  return 0
+end
+if ARGV[2] == '' then
+  redis.call('DEL', KEYS[1])
+else
+  redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+end
+return 1
+`)
+
+const keyPrefix = "idempotency:"
+
+type RedisStore struct {
+	client *redis.Client
+	log    *slog.Logger
+}
+
+func NewRedisStore(cfg *config.RedisConfig, log *slog.Logger) (*RedisStore, error) {
+	const op = "redis_idempotency.NewRedisStore"
+	log = log.With(slog.String("op", op))
+
+	opts, err := redis.ParseURL(cfg.URL)
+	if err != nil {
+		log.Error("failed to parse redis url", logger.Err(err))
+		return nil, err
+	}
+
+	client := redis.NewClient(opts)
+	client.AddHook(redistrace.Hook{})
+	return &RedisStore{
+		client: client,
+		log:    log,
+	}, nil
+}
+
+func (s *RedisStore) Lock(ctx context.Context, key string, rec *idempotency.Record, ttl time.Duration) (bool, *idempotency.Record, error) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return false, nil, err
+	}
+
+	// запись может истечь между SETNX и GET, тогда пробуем захватить ещё раз
+	for attempt := 0; attempt < 2; attempt++ {
+		acquired, err := s.client.SetNX(ctx, keyPrefix+key, data, ttl).Result()
+		if err != nil {
+			return false, nil, err
+		}
+		if acquired {
+			return true, nil, nil
+		}
+
+		raw, err := s.client.Get(ctx, keyPrefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return false, nil, err
+		}
+
+		var existing idempotency.Record
+		if err := json.Unmarshal(raw, &existing); err != nil {
+			return false, nil, err
+		}
+		return false, &existing, nil
+	}
+
+	return false, nil, errors.New("idempotency key is contended")
+}
+
+func (s *RedisStore) Complete(ctx context.Context, key string, rec *idempotency.Record, ttl time.Duration) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	return compareAndSet.Run(ctx, s.client, []string{keyPrefix + key},
+		rec.Token, string(data), ttl.Milliseconds()).Err()
+}
+
+func (s *RedisStore) Release(ctx context.Context, key string, token string) error {
+	return compareAndSet.Run(ctx, s.client, []string{keyPrefix + key}, token, "", 0).Err()
+}
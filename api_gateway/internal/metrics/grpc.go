@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_client_handled_total",
+		Help: "RPCs completed by the client, by method and status code.",
+	}, []string{"grpc_type", "grpc_service", "grpc_method", "grpc_code"})
+
+	grpcHandling = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_client_handling_seconds",
+		Help:    "Latency of RPCs issued by the client, until the response or the end of the stream.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_type", "grpc_service", "grpc_method"})
+
+	circuitState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "grpc_client_circuit_state",
+		Help: "Circuit breaker state per dependency: 0 closed, 1 half-open, 2 open.",
+	}, []string{"target"})
+
+	circuitRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_client_circuit_rejected_total",
+		Help: "RPCs rejected without a call because the circuit breaker was open.",
+	}, []string{"target"})
+)
+
+func SetCircuitState(target string, state int) {
+	circuitState.WithLabelValues(target).Set(float64(state))
+}
+
+func ObserveCircuitRejected(target string) {
+	circuitRejected.WithLabelValues(target).Inc()
+}
+
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		startTime := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		observeRPC("unary", method, startTime, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor учитывает поток при его завершении: по io.EOF или ошибке чтения
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		startTime := time.Now()
+		rpcType := streamType(desc)
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			observeRPC(rpcType, method, startTime, err)
+			return nil, err
+		}
+		return &monitoredStream{ClientStream: stream, rpcType: rpcType, method: method, startTime: startTime}, nil
+	}
+}
+
+type monitoredStream struct {
+	grpc.ClientStream
+	rpcType   string
+	method    string
+	startTime time.Time
+	once      sync.Once
+}
+
+func (s *monitoredStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(func() {
+			if errors.Is(err, io.EOF) {
+				observeRPC(s.rpcType, s.method, s.startTime, nil)
+				return
+			}
+			observeRPC(s.rpcType, s.method, s.startTime, err)
+		})
+	}
+	return err
+}
+
+func observeRPC(rpcType string, fullMethod string, startTime time.Time, err error) {
+	service, method := splitMethod(fullMethod)
+	grpcHandled.WithLabelValues(rpcType, service, method, status.Code(err).String()).Inc()
+	grpcHandling.WithLabelValues(rpcType, service, method).Observe(time.Since(startTime).Seconds())
+}
+
+func streamType(desc *grpc.StreamDesc) string {
+	switch {
+	case desc.ClientStreams && desc.ServerStreams:
+		return "bidi_stream"
+	case desc.ClientStreams:
+		return "client_stream"
+	default:
+		return "server_stream"
+	}
+}
+
+// splitMethod разбирает "/package.Service/Method" на сервис и метод
+func splitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.Index(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
@@ -0,0 +1,63 @@
+// Package metrics описывает метрики Prometheus шлюза. Они отдаются
+// на /metrics основного HTTP-сервера.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	ResultOK    = "ok"
+	ResultError = "error"
+)
+
+var (
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency, by method, route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	httpInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "HTTP requests currently being served.",
+	})
+
+	kafkaProduced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_produced_messages_total",
+		Help: "Kafka messages produced, by topic and result.",
+	}, []string{"topic", "result"})
+
+	kafkaProduceDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kafka_produce_duration_seconds",
+		Help:    "Kafka produce latency, one observation per write call.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"topic"})
+)
+
+// TrackInFlight учитывает запрос в http_requests_in_flight до вызова done
+func TrackInFlight() (done func()) {
+	httpInFlight.Inc()
+	return httpInFlight.Dec
+}
+
+// ObserveHTTP учитывает обработанный запрос; route — шаблон маршрута, а не путь,
+// чтобы идентификаторы не раздували число серий
+func ObserveHTTP(method string, route string, status int, startTime time.Time) {
+	httpRequestDuration.WithLabelValues(method, route, strconv.Itoa(status)).
+		Observe(time.Since(startTime).Seconds())
+}
+
+// ObserveProduced учитывает запись count сообщений в topic
+func ObserveProduced(topic string, count int, startTime time.Time, err error) {
+	result := ResultOK
+	if err != nil {
+		result = ResultError
+	}
+	kafkaProduced.WithLabelValues(topic, result).Add(float64(count))
+	kafkaProduceDuration.WithLabelValues(topic).Observe(time.Since(startTime).Seconds())
+}
@@ -0,0 +1,22 @@
+package objectstore
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+type ObjectStore interface {
+	PutObject(ctx context.Context, key string, data []byte, contentType string, metadata map[string]string) error
+	GetObject(ctx context.Context, key string) (*Object, error)
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+	DeleteObject(ctx context.Context, key string) error
+	Bucket() string
+}
+
+// Object — открытый на чтение объект; Body закрывает вызывающий.
+type Object struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+}
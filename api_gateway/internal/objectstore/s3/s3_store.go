@@ -0,0 +1,162 @@
+package s3
+
+import (
+	"api_gateway/internal/config"
+	"api_gateway/internal/objectstore"
+	"api_gateway/logger"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/deeelis/platform/tracing/awstrace"
+	"log/slog"
+	"time"
+)
+
+type S3Store struct {
+	client  *s3.Client
+	presign *s3.PresignClient
+	bucket  string
+	log     *slog.Logger
+}
+
+func NewS3Store(ctx context.Context, cfg *config.S3Config, log *slog.Logger) (*S3Store, error) {
+	const op = "s3.NewS3Store"
+	log = log.With(slog.String("op", op))
+	log.Info("initializing object store",
+		slog.String("endpoint", cfg.Endpoint),
+		slog.String("bucket", cfg.Bucket))
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx,
+		awsconfig.WithEndpointResolverWithOptions(endpointResolver(cfg.Endpoint)),
+		awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+			cfg.AccessKey,
+			cfg.SecretKey,
+			"",
+		)),
+		awsconfig.WithRegion(cfg.Region),
+		awsconfig.WithAPIOptions([]func(*middleware.Stack) error{awstrace.Middleware}),
+	)
+	if err != nil {
+		log.Error("failed to load s3 config", logger.Err(err))
+		return nil, err
+	}
+
+	pathStyle := func(o *s3.Options) {
+		o.UsePathStyle = true
+	}
+	client := s3.NewFromConfig(awsCfg, pathStyle)
+
+	// подпись включает хост, поэтому ссылки для клиентов подписываются
+	// на публичный адрес хранилища, а не на внутренний
+	publicEndpoint := cfg.PublicEndpoint
+	if publicEndpoint == "" {
+		publicEndpoint = cfg.Endpoint
+	}
+	publicCfg := awsCfg.Copy()
+	publicCfg.EndpointResolverWithOptions = endpointResolver(publicEndpoint)
+	presign := s3.NewPresignClient(s3.NewFromConfig(publicCfg, pathStyle))
+
+	_, err = client.CreateBucket(ctx, &s3.CreateBucketInput{
+		Bucket: aws.String(cfg.Bucket),
+	})
+
+	var bne *types.BucketAlreadyExists
+	var bno *types.BucketAlreadyOwnedByYou
+	if err != nil && !errors.As(err, &bne) && !errors.As(err, &bno) {
+		log.Error("failed to create bucket", logger.Err(err))
+		return nil, fmt.Errorf("failed to create bucket: %w", err)
+	}
+
+	log.Info("object store initialized successfully")
+	return &S3Store{
+		client:  client,
+		presign: presign,
+		bucket:  cfg.Bucket,
+		log:     log,
+	}, nil
+}
+
+func endpointResolver(url string) aws.EndpointResolverWithOptions {
+	return aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+		return aws.Endpoint{
+			URL:               url,
+			SigningRegion:     region,
+			HostnameImmutable: true,
+		}, nil
+	})
+}
+
+func (s *S3Store) PutObject(ctx context.Context, key string, data []byte, contentType string, metadata map[string]string) error {
+	const op = "s3.S3Store.PutObject"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("key", key),
+		slog.Int("size_bytes", len(data)),
+	)
+
+	startTime := time.Now()
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+		Metadata:    metadata,
+	})
+	if err != nil {
+		log.Error("failed to put object",
+			logger.Err(err),
+			slog.Duration("duration", time.Since(startTime)))
+		return err
+	}
+
+	log.Debug("object stored",
+		slog.Duration("duration", time.Since(startTime)))
+	return nil
+}
+
+func (s *S3Store) GetObject(ctx context.Context, key string) (*objectstore.Object, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &objectstore.Object{
+		Body:        out.Body,
+		ContentType: aws.ToString(out.ContentType),
+		Size:        aws.ToInt64(out.ContentLength),
+	}, nil
+}
+
+// PresignGet выписывает ссылку на чтение объекта, действующую ttl.
+func (s *S3Store) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	req, err := s.presign.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+func (s *S3Store) DeleteObject(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (s *S3Store) Bucket() string {
+	return s.bucket
+}
@@ -0,0 +1,46 @@
+package openapi
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// спецификация — единственный источник контракта HTTP API:
+// по ней строится документация и проверяются запросы и ответы
+//
+//go:embed openapi.yaml
+var spec []byte
+
+//go:embed docs.html
+var DocsPage []byte
+
+// сборка Redoc вшивается в бинарник, чтобы страница документации
+// не зависела от CDN и работала в закрытом контуре
+//
+//go:generate sh -c "test -f redoc/redoc.standalone.js || wget -qO redoc/redoc.standalone.js https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js"
+//go:embed redoc
+var redoc embed.FS
+
+// DocsAssets — статические файлы страницы документации
+func DocsAssets() fs.FS {
+	assets, _ := fs.Sub(redoc, "redoc")
+	return assets
+}
+
+func Load() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load openapi spec: %w", err)
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %w", err)
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,13 @@
+// Package pages — страницы, на которые ведут ссылки из писем auth_service.
+// Страница только берёт токен из адреса и отправляет его POST-запросом в API:
+// почтовые сканеры открывают ссылки GET-ом, и одноразовый токен не должен
+// гаснуть от такого открытия.
+package pages
+
+import _ "embed"
+
+//go:embed verify-email.html
+var VerifyEmail []byte
+
+//go:embed reset-password.html
+var ResetPassword []byte
@@ -0,0 +1,152 @@
+package problem
+
+import (
+	"api_gateway/internal/domain/errors"
+	"encoding/json"
+	goerrors "errors"
+	"net/http"
+	"strings"
+
+	errorsv1 "github.com/deeelis/errors-protos/gen/go/errors"
+	"github.com/gin-gonic/gin"
+)
+
+const ContentType = "application/problem+json"
+
+type entry struct {
+	status int
+	title  string
+}
+
+// catalogue задаёт HTTP-статус и заголовок для каждого кода из errors-protos.
+var catalogue = map[errorsv1.ErrorCode]entry{
+	errorsv1.ErrorCode_INTERNAL:                   {http.StatusInternalServerError, "Internal server error"},
+	errorsv1.ErrorCode_UNAVAILABLE:                {http.StatusServiceUnavailable, "Service temporarily unavailable"},
+	errorsv1.ErrorCode_INVALID_ARGUMENT:           {http.StatusBadRequest, "Invalid request"},
+	errorsv1.ErrorCode_NOT_FOUND:                  {http.StatusNotFound, "Resource not found"},
+	errorsv1.ErrorCode_CONFLICT:                   {http.StatusConflict, "Conflict"},
+	errorsv1.ErrorCode_PERMISSION_DENIED:          {http.StatusForbidden, "Permission denied"},
+	errorsv1.ErrorCode_UNAUTHENTICATED:            {http.StatusUnauthorized, "Authentication required"},
+	errorsv1.ErrorCode_RATE_LIMITED:               {http.StatusTooManyRequests, "Rate limit exceeded"},
+	errorsv1.ErrorCode_INVALID_CREDENTIALS:        {http.StatusUnauthorized, "Invalid credentials"},
+	errorsv1.ErrorCode_TOKEN_INVALID:              {http.StatusUnauthorized, "Invalid token"},
+	errorsv1.ErrorCode_USER_ALREADY_EXISTS:        {http.StatusConflict, "User already exists"},
+	errorsv1.ErrorCode_USER_NOT_FOUND:             {http.StatusNotFound, "User not found"},
+	errorsv1.ErrorCode_CONTENT_NOT_FOUND:          {http.StatusNotFound, "Content not found"},
+	errorsv1.ErrorCode_CONTENT_ACCESS_DENIED:      {http.StatusNotFound, "Content not found"},
+	errorsv1.ErrorCode_WEBHOOK_NOT_FOUND:          {http.StatusNotFound, "Webhook not found"},
+	errorsv1.ErrorCode_WEBHOOK_INVALID:            {http.StatusBadRequest, "Invalid webhook"},
+	errorsv1.ErrorCode_DELIVERY_NOT_REDELIVERABLE: {http.StatusConflict, "Delivery cannot be redelivered"},
+	errorsv1.ErrorCode_QUOTA_EXCEEDED:             {http.StatusTooManyRequests, "Upload quota exceeded"},
+	errorsv1.ErrorCode_IDEMPOTENCY_KEY_IN_USE:     {http.StatusConflict, "Request with this idempotency key is in progress"},
+	errorsv1.ErrorCode_IDEMPOTENCY_KEY_MISMATCH:   {http.StatusUnprocessableEntity, "Idempotency key reused with a different request"},
+	errorsv1.ErrorCode_PAYLOAD_TOO_LARGE:          {http.StatusRequestEntityTooLarge, "Payload too large"},
+	errorsv1.ErrorCode_UNSUPPORTED_MEDIA_TYPE:     {http.StatusUnsupportedMediaType, "Unsupported media type"},
+}
+
+// Problem — тело ответа об ошибке по RFC 7807. Дополнительные поля
+// передаются через With и выводятся на верхнем уровне объекта.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Code       errorsv1.ErrorCode
+	RequestID  string
+	extensions map[string]any
+}
+
+func New(code errorsv1.ErrorCode, detail string) *Problem {
+	e, ok := catalogue[code]
+	if !ok {
+		code = errorsv1.ErrorCode_INTERNAL
+		e = catalogue[code]
+	}
+
+	return &Problem{
+		Type:   "/problems/" + strings.ReplaceAll(strings.ToLower(code.String()), "_", "-"),
+		Title:  e.title,
+		Status: e.status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// FromError строит ответ по ошибке из любого слоя шлюза.
+func FromError(err error) *Problem {
+	return New(errors.CodeOf(err), errors.MessageOf(err))
+}
+
+func (p *Problem) With(key string, value any) *Problem {
+	if p.extensions == nil {
+		p.extensions = make(map[string]any)
+	}
+	p.extensions[key] = value
+	return p
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	body := make(map[string]any, len(p.extensions)+7)
+	for k, v := range p.extensions {
+		body[k] = v
+	}
+	body["type"] = p.Type
+	body["title"] = p.Title
+	body["status"] = p.Status
+	body["code"] = p.Code.String()
+	if p.Detail != "" {
+		body["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		body["instance"] = p.Instance
+	}
+	if p.RequestID != "" {
+		body["request_id"] = p.RequestID
+	}
+	return json.Marshal(body)
+}
+
+// Write отправляет ответ и прерывает цепочку обработчиков.
+func Write(ctx *gin.Context, p *Problem) {
+	p.Instance = ctx.Request.URL.Path
+	p.RequestID = ctx.GetString("request_id")
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Abort()
+	ctx.Data(p.Status, ContentType, body)
+}
+
+// Error — сокращение для Write(ctx, FromError(err)).
+func Error(ctx *gin.Context, err error) {
+	Write(ctx, FromError(err))
+}
+
+// BadRequest — ответ INVALID_ARGUMENT с пояснением для клиента.
+func BadRequest(ctx *gin.Context, detail string) {
+	Write(ctx, New(errorsv1.ErrorCode_INVALID_ARGUMENT, detail))
+}
+
+// TooLarge — ответ PAYLOAD_TOO_LARGE с пределом, который превысил клиент;
+// unit — bytes или runes.
+func TooLarge(ctx *gin.Context, detail string, limit int64, unit string) {
+	Write(ctx, New(errorsv1.ErrorCode_PAYLOAD_TOO_LARGE, detail).
+		With("limit", limit).
+		With("unit", unit))
+}
+
+// InvalidBody отвечает 413, если чтение тела оборвал http.MaxBytesReader,
+// и INVALID_ARGUMENT с detail в остальных случаях.
+func InvalidBody(ctx *gin.Context, err error, detail string) {
+	var tooLarge *http.MaxBytesError
+	if goerrors.As(err, &tooLarge) {
+		TooLarge(ctx, "request body too large", tooLarge.Limit, "bytes")
+		return
+	}
+	BadRequest(ctx, detail)
+}
@@ -5,15 +5,20 @@ import (
 	e "api_gateway/internal/domain/errors"
 	"api_gateway/internal/domain/models"
 	kafka2 "api_gateway/internal/kafka"
+	"api_gateway/internal/metrics"
 	"api_gateway/logger"
 	"context"
 	"encoding/json"
+	"github.com/deeelis/platform/tracing"
+	"github.com/deeelis/platform/tracing/kafkatrace"
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
 	"log/slog"
 	"time"
 )
 
 type Producer struct {
+	cfg     *config.KafkaConfig
 	toImage *kafka.Conn
 	toText  *kafka.Conn
 	log     *slog.Logger
@@ -85,13 +90,14 @@ func NewProducer(ctx context.Context, cfg *config.KafkaConfig, log *slog.Logger)
 	}
 
 	return &Producer{
+		cfg:     cfg,
 		toText:  conn1,
 		toImage: conn2,
 		log:     log,
 	}, nil
 }
 
-func (p *Producer) ProduceContent(content *models.Content) error {
+func (p *Producer) ProduceContent(ctx context.Context, content *models.Content) error {
 	const op = "kafka.Producer.ProduceContent"
 	log := p.log.With(
 		slog.String("op", op),
@@ -102,11 +108,12 @@ func (p *Producer) ProduceContent(content *models.Content) error {
 	log.Debug("producing content...")
 
 	var conn *kafka.Conn
+	var topic string
 	switch content.Type {
 	case models.ContentTypeText:
-		conn = p.toText
+		conn, topic = p.toText, p.cfg.TextTopic
 	case models.ContentTypeImage:
-		conn = p.toImage
+		conn, topic = p.toImage, p.cfg.ImageTopic
 	default:
 		log.Error("invalid content type", slog.String("type", string(content.Type)))
 		return e.ErrInvalidContentType
@@ -120,7 +127,11 @@ func (p *Producer) ProduceContent(content *models.Content) error {
 	log.Debug("content marshaled successfully")
 
 	startTime := time.Now()
-	err = kafka2.SendToTopic(conn, msg)
+	ctx, span := kafkatrace.StartProducer(ctx, topic, 1)
+	span.SetAttributes(attribute.String("content.id", content.ID))
+	err = kafka2.SendToTopic(ctx, conn, msg)
+	tracing.End(span, err)
+	metrics.ObserveProduced(topic, 1, startTime, err)
 	if err != nil {
 		log.Error("failed to produce message",
 			logger.Err(err),
@@ -134,6 +145,68 @@ func (p *Producer) ProduceContent(content *models.Content) error {
 	return nil
 }
 
+func (p *Producer) ProduceContentBatch(ctx context.Context, contents []*models.Content) error {
+	const op = "kafka.Producer.ProduceContentBatch"
+	log := p.log.With(
+		slog.String("op", op),
+		slog.Int("batch_size", len(contents)),
+	)
+
+	log.Debug("producing content batch...")
+
+	var textMsgs, imageMsgs [][]byte
+	for _, content := range contents {
+		msg, err := json.Marshal(content)
+		if err != nil {
+			log.Error("failed to marshal content",
+				logger.Err(err),
+				slog.String("content_id", content.ID))
+			return err
+		}
+
+		switch content.Type {
+		case models.ContentTypeText:
+			textMsgs = append(textMsgs, msg)
+		case models.ContentTypeImage:
+			imageMsgs = append(imageMsgs, msg)
+		default:
+			log.Error("invalid content type", slog.String("type", string(content.Type)))
+			return e.ErrInvalidContentType
+		}
+	}
+
+	startTime := time.Now()
+	for _, batch := range []struct {
+		conn     *kafka.Conn
+		topic    string
+		messages [][]byte
+	}{
+		{conn: p.toText, topic: p.cfg.TextTopic, messages: textMsgs},
+		{conn: p.toImage, topic: p.cfg.ImageTopic, messages: imageMsgs},
+	} {
+		if len(batch.messages) == 0 {
+			continue
+		}
+		batchStart := time.Now()
+		batchCtx, span := kafkatrace.StartProducer(ctx, batch.topic, len(batch.messages))
+		err := kafka2.SendBatchToTopic(batchCtx, batch.conn, batch.messages)
+		tracing.End(span, err)
+		metrics.ObserveProduced(batch.topic, len(batch.messages), batchStart, err)
+		if err != nil {
+			log.Error("failed to produce batch",
+				logger.Err(err),
+				slog.Duration("duration", time.Since(startTime)))
+			return err
+		}
+	}
+
+	log.Info("content batch successfully produced",
+		slog.Int("text_count", len(textMsgs)),
+		slog.Int("image_count", len(imageMsgs)),
+		slog.Duration("duration", time.Since(startTime)))
+	return nil
+}
+
 func (p *Producer) Close() error {
 	const op = "kafka.Producer.Close"
 	log := p.log.With(slog.String("op", op))
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"api_gateway/internal/config"
+	"context"
+	"time"
+)
+
+// Limit описывает token bucket: Burst токенов ёмкость, Requests токенов восполняется за Period.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	// ResetAfter — время до полного восполнения корзины
+	ResetAfter time.Duration
+}
+
+type QuotaResult struct {
+	Allowed  bool
+	Used     int
+	Limit    int
+	ResetsAt time.Time
+	// Key — счётчик суток, из которого списана квота; его передают в Release,
+	// чтобы возврат после полуночи не ушёл в счётчик новых суток.
+	Key string
+}
+
+// GroupUsage — лимит группы маршрутов и текущее состояние её корзины
+type GroupUsage struct {
+	Limit  Limit
+	Result *Result
+}
+
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+	Peek(ctx context.Context, key string, limit Limit) (*Result, error)
+}
+
+type Quota interface {
+	Consume(ctx context.Context, userID string, n int) (*QuotaResult, error)
+	Release(ctx context.Context, key string, n int) error
+	Usage(ctx context.Context, userID string) (*QuotaResult, error)
+}
+
+// LimitFor возвращает лимит группы маршрутов из конфига. Если Burst не задан,
+// ёмкость корзины равна Requests.
+func LimitFor(cfg *config.RateLimitConfig, group string) (Limit, bool) {
+	lc, ok := cfg.Groups[group]
+	if !ok || lc == nil || lc.Requests <= 0 || lc.Period <= 0 {
+		return Limit{}, false
+	}
+
+	burst := lc.Burst
+	if burst <= 0 {
+		burst = lc.Requests
+	}
+
+	return Limit{
+		Requests: lc.Requests,
+		Period:   lc.Period,
+		Burst:    burst,
+	}, true
+}
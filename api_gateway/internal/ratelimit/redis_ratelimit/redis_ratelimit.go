@@ -0,0 +1,203 @@
+package redis_ratelimit
+
+import (
+	"api_gateway/internal/config"
+	"api_gateway/internal/ratelimit"
+	"api_gateway/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/deeelis/platform/tracing/redistrace"
+	"github.com/go-redis/redis/v8"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+)
+
+// tokenBucket атомарно восполняет и списывает токены. Состояние корзины —
+// хеш {tokens, ts}, время передаётся из приложения в миллисекундах.
+var tokenBucket = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil then
+  tokens = burst
+  ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+if tokens >= cost then
+  tokens = tokens - cost
+  allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// dailyQuota увеличивает счётчик, только если квота ещё не исчерпана.
+var dailyQuota = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local n = tonumber(ARGV[2])
+local used = tonumber(redis.call('GET', KEYS[1]) or '0')
+if used + n > limit then
+  return {0, used}
+end
+used = redis.call('INCRBY', KEYS[1], n)
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+return {1, used}
+`)
+
+// releaseQuota возвращает до n списаний, не опуская счётчик ниже нуля
+// и не создавая его заново, если он уже истёк.
+var releaseQuota = redis.NewScript(`
+local used = tonumber(redis.call('GET', KEYS[1]) or '0')
+local n = math.min(used, tonumber(ARGV[1]))
+if n <= 0 then
+  return used
+end
+return redis.call('DECRBY', KEYS[1], n)
+`)
+
+type RedisRateLimiter struct {
+	client       *redis.Client
+	dailyUploads int
+	log          *slog.Logger
+}
+
+func NewRedisRateLimiter(cfg *config.Config, log *slog.Logger) (*RedisRateLimiter, error) {
+	const op = "redis_ratelimit.NewRedisRateLimiter"
+	log = log.With(slog.String("op", op))
+
+	opts, err := redis.ParseURL(cfg.Redis.URL)
+	if err != nil {
+		log.Error("failed to parse redis url", logger.Err(err))
+		return nil, err
+	}
+
+	client := redis.NewClient(opts)
+	client.AddHook(redistrace.Hook{})
+	return &RedisRateLimiter{
+		client:       client,
+		dailyUploads: cfg.RateLimit.DailyUploads,
+		log:          log,
+	}, nil
+}
+
+func (r *RedisRateLimiter) Allow(ctx context.Context, key string, limit ratelimit.Limit) (*ratelimit.Result, error) {
+	return r.take(ctx, key, limit, 1)
+}
+
+// Peek возвращает состояние корзины, не списывая токенов.
+func (r *RedisRateLimiter) Peek(ctx context.Context, key string, limit ratelimit.Limit) (*ratelimit.Result, error) {
+	return r.take(ctx, key, limit, 0)
+}
+
+func (r *RedisRateLimiter) take(ctx context.Context, key string, limit ratelimit.Limit, cost int) (*ratelimit.Result, error) {
+	if limit.Requests <= 0 || limit.Period <= 0 || limit.Burst <= 0 {
+		return nil, fmt.Errorf("invalid limit %+v", limit)
+	}
+
+	// токенов в миллисекунду
+	rate := float64(limit.Requests) / float64(limit.Period.Milliseconds())
+	now := time.Now().UnixMilli()
+
+	res, err := tokenBucket.Run(ctx, r.client, []string{"ratelimit:" + key},
+		rate, limit.Burst, now, cost).Slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(res) != 2 {
+		return nil, errors.New("unexpected token bucket reply")
+	}
+
+	allowed, _ := res[0].(int64)
+	tokensStr, _ := res[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token count %q: %w", tokensStr, err)
+	}
+
+	result := &ratelimit.Result{
+		Allowed:    allowed == 1,
+		Limit:      limit.Burst,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: msDuration((float64(limit.Burst) - tokens) / rate),
+	}
+	if !result.Allowed {
+		result.RetryAfter = msDuration((float64(cost) - tokens) / rate)
+	}
+
+	return result, nil
+}
+
+func (r *RedisRateLimiter) Consume(ctx context.Context, userID string, n int) (*ratelimit.QuotaResult, error) {
+	now := time.Now().UTC()
+	resetsAt := nextMidnight(now)
+	key := quotaKey(userID, now)
+
+	res, err := dailyQuota.Run(ctx, r.client, []string{key},
+		r.dailyUploads, n, int(resetsAt.Sub(now).Seconds())+3600).Slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(res) != 2 {
+		return nil, errors.New("unexpected quota reply")
+	}
+
+	allowed, _ := res[0].(int64)
+	used, _ := res[1].(int64)
+
+	return &ratelimit.QuotaResult{
+		Allowed:  allowed == 1,
+		Used:     int(used),
+		Limit:    r.dailyUploads,
+		ResetsAt: resetsAt,
+		Key:      key,
+	}, nil
+}
+
+// Release возвращает квоту, если загрузка не была принята. key — из результата Consume.
+func (r *RedisRateLimiter) Release(ctx context.Context, key string, n int) error {
+	return releaseQuota.Run(ctx, r.client, []string{key}, n).Err()
+}
+
+func (r *RedisRateLimiter) Usage(ctx context.Context, userID string) (*ratelimit.QuotaResult, error) {
+	now := time.Now().UTC()
+
+	used, err := r.client.Get(ctx, quotaKey(userID, now)).Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	return &ratelimit.QuotaResult{
+		Allowed:  used < r.dailyUploads,
+		Used:     used,
+		Limit:    r.dailyUploads,
+		ResetsAt: nextMidnight(now),
+	}, nil
+}
+
+func quotaKey(userID string, now time.Time) string {
+	return "quota:uploads:" + userID + ":" + now.Format("20060102")
+}
+
+func nextMidnight(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+}
+
+func msDuration(ms float64) time.Duration {
+	if ms <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(ms)) * time.Millisecond
+}
@@ -0,0 +1,103 @@
+package tokenauth
+
+import (
+	"api_gateway/internal/domain/errors"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// tokenTypeAccess — значение claim typ у access-токена; refresh-токен
+// не должен открывать доступ к API.
+const tokenTypeAccess = "access"
+
+type tokenClaims struct {
+	UserID string `json:"user_id"`
+	Type   string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+func (c *tokenClaims) toClaims() *Claims {
+	claims := &Claims{UserID: c.UserID, ID: c.ID}
+	if c.IssuedAt != nil {
+		claims.IssuedAt = c.IssuedAt.Time
+	}
+	if c.ExpiresAt != nil {
+		claims.ExpiresAt = c.ExpiresAt.Time
+	}
+	return claims
+}
+
+// LocalBackend проверяет подпись и срок токена в шлюзе. Keyfunc выбирает
+// ключ по заголовку токена, methods — допустимые алгоритмы подписи.
+type LocalBackend struct {
+	parser  *jwt.Parser
+	keyfunc jwt.Keyfunc
+	leeway  time.Duration
+}
+
+func NewLocalBackend(keyfunc jwt.Keyfunc, methods []string, leeway time.Duration) *LocalBackend {
+	return &LocalBackend{
+		// срок проверяется ниже с допуском на расхождение часов
+		parser:  jwt.NewParser(jwt.WithValidMethods(methods), jwt.WithoutClaimsValidation()),
+		keyfunc: keyfunc,
+		leeway:  leeway,
+	}
+}
+
+func (b *LocalBackend) Verify(_ context.Context, token string) (*Claims, error) {
+	claims := &tokenClaims{}
+	if _, err := b.parser.ParseWithClaims(token, claims, b.keyfunc); err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrUnauthorized, err)
+	}
+
+	now := time.Now()
+	switch {
+	case claims.UserID == "":
+		return nil, fmt.Errorf("%w: missing user_id", errors.ErrUnauthorized)
+	case claims.Type != tokenTypeAccess:
+		return nil, fmt.Errorf("%w: not an access token", errors.ErrUnauthorized)
+	case !claims.VerifyExpiresAt(now.Add(-b.leeway), true):
+		return nil, fmt.Errorf("%w: token expired", errors.ErrUnauthorized)
+	case !claims.VerifyNotBefore(now.Add(b.leeway), false):
+		return nil, fmt.Errorf("%w: token not valid yet", errors.ErrUnauthorized)
+	}
+	return claims.toClaims(), nil
+}
+
+// RemoteValidator — проверка токена в auth_service.
+type RemoteValidator interface {
+	ValidateToken(ctx context.Context, token string) (string, error)
+}
+
+// RemoteBackend спрашивает auth_service, а срок и jti для кеша берёт
+// из самого токена: подпись к этому моменту уже проверена сервисом.
+type RemoteBackend struct {
+	validator RemoteValidator
+	parser    *jwt.Parser
+}
+
+func NewRemoteBackend(validator RemoteValidator) *RemoteBackend {
+	return &RemoteBackend{
+		validator: validator,
+		parser:    jwt.NewParser(),
+	}
+}
+
+func (b *RemoteBackend) Verify(ctx context.Context, token string) (*Claims, error) {
+	userID, err := b.validator.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := &tokenClaims{}
+	if _, _, err := b.parser.ParseUnverified(token, parsed); err != nil || parsed.ExpiresAt == nil {
+		// без срока такой токен не кешируется
+		return &Claims{UserID: userID, IssuedAt: time.Now()}, nil
+	}
+	claims := parsed.toClaims()
+	claims.UserID = userID
+	return claims, nil
+}
@@ -0,0 +1,70 @@
+package tokenauth
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const defaultCacheSize = 10000
+
+// Cache — LRU проверенных токенов ограниченного размера. Запись живёт
+// не дольше срока действия самого токена.
+type Cache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key    string
+	claims *Claims
+}
+
+func NewCache(size int) *Cache {
+	if size <= 0 {
+		size = defaultCacheSize
+	}
+	return &Cache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+func (c *Cache) Get(key string, now time.Time) (*Claims, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if !entry.claims.ExpiresAt.After(now) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.claims, true
+}
+
+func (c *Cache) Add(key string, claims *Claims) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*cacheEntry).claims = claims
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, claims: claims})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
@@ -0,0 +1,159 @@
+package tokenauth
+
+import (
+	"api_gateway/internal/domain/errors"
+	"api_gateway/internal/domain/models"
+	"api_gateway/logger"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// refetchInterval — не чаще этого токен с незнакомым kid вызывает
+// внеочередную загрузку ключей: иначе поток мусорных токенов превратился бы
+// в поток запросов к auth_service.
+const refetchInterval = 10 * time.Second
+
+const defaultRefreshInterval = 5 * time.Minute
+
+// JWKSource — откуда берутся открытые ключи подписи.
+type JWKSource interface {
+	GetJWKS(ctx context.Context) ([]*models.JWK, error)
+}
+
+// KeySet — открытые ключи Ed25519 из JWKS auth_service по kid.
+type KeySet struct {
+	source JWKSource
+	log    *slog.Logger
+
+	mu          sync.RWMutex
+	keys        map[string]ed25519.PublicKey
+	lastRefresh time.Time
+}
+
+func NewKeySet(source JWKSource, log *slog.Logger) *KeySet {
+	return &KeySet{
+		source: source,
+		log:    log.With(slog.String("op", "tokenauth.KeySet")),
+		keys:   make(map[string]ed25519.PublicKey),
+	}
+}
+
+// Refresh заменяет набор ключей загруженным. Ключи неизвестного типа
+// пропускаются.
+func (k *KeySet) Refresh(ctx context.Context) error {
+	jwks, err := k.source.GetJWKS(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]ed25519.PublicKey, len(jwks))
+	for _, jwk := range jwks {
+		if jwk.Kty != "OKP" || jwk.Crv != "Ed25519" {
+			continue
+		}
+		publicKey, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(publicKey) != ed25519.PublicKeySize {
+			k.log.Warn("skipping malformed jwk", slog.String("kid", jwk.Kid))
+			continue
+		}
+		keys[jwk.Kid] = publicKey
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys = keys
+	k.lastRefresh = time.Now()
+	return nil
+}
+
+// Run перезагружает ключи раз в interval, пока не отменён ctx. Первая
+// загрузка выполняется сразу; при ошибке остаётся прежний набор.
+func (k *KeySet) Run(ctx context.Context, interval time.Duration) {
+	if err := k.Refresh(ctx); err != nil {
+		k.log.Warn("failed to load jwks", logger.Err(err))
+	}
+	if interval <= 0 {
+		interval = defaultRefreshInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.Refresh(ctx); err != nil {
+				k.log.Warn("failed to refresh jwks", logger.Err(err))
+			}
+		}
+	}
+}
+
+// Keyfunc выбирает ключ проверки по заголовку токена: Ed25519 — по kid,
+// HMAC — legacySecret, если он задан. Незнакомый kid означает, что ключ
+// могли повернуть после последней загрузки, поэтому набор загружается заново.
+func (k *KeySet) Keyfunc(legacySecret []byte) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			if len(legacySecret) == 0 {
+				return nil, fmt.Errorf("%w: hmac tokens are not accepted", errors.ErrUnauthorized)
+			}
+			return legacySecret, nil
+		case *jwt.SigningMethodEd25519:
+			kid, _ := token.Header["kid"].(string)
+			if kid == "" {
+				return nil, fmt.Errorf("%w: missing kid", errors.ErrUnauthorized)
+			}
+			if key, ok := k.key(kid); ok {
+				return key, nil
+			}
+			if k.refetch() {
+				if key, ok := k.key(kid); ok {
+					return key, nil
+				}
+			}
+			return nil, fmt.Errorf("%w: unknown kid %q", errors.ErrUnauthorized, kid)
+		default:
+			return nil, fmt.Errorf("%w: unexpected signing method", errors.ErrUnauthorized)
+		}
+	}
+}
+
+func (k *KeySet) key(kid string) (ed25519.PublicKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+func (k *KeySet) refetch() bool {
+	k.mu.RLock()
+	recent := time.Since(k.lastRefresh) < refetchInterval
+	k.mu.RUnlock()
+	if recent {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := k.Refresh(ctx); err != nil {
+		k.log.Warn("failed to refetch jwks", logger.Err(err))
+		// попытка не удалась, но и повторять её на каждый запрос не стоит
+		k.mu.Lock()
+		k.lastRefresh = time.Now()
+		k.mu.Unlock()
+		return false
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package redis_revocation
+
+import (
+	"api_gateway/internal/config"
+	"api_gateway/internal/tokenauth"
+	"api_gateway/logger"
+	"context"
+	"errors"
+	"github.com/deeelis/platform/denylist"
+	"github.com/deeelis/platform/tracing/redistrace"
+	"log/slog"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const readBlock = 5 * time.Second
+
+// Store читает отзывы, которые auth_service пишет в Redis в формате пакета
+// denylist. Издатель сам обрезает поток по сроку жизни access-токена,
+// поэтому лента читается с начала.
+type Store struct {
+	client *redis.Client
+	stream string
+}
+
+func NewStore(cfg *config.RedisConfig, stream string, log *slog.Logger) (*Store, error) {
+	const op = "redis_revocation.NewStore"
+	log = log.With(slog.String("op", op), slog.String("stream", stream))
+
+	opts, err := redis.ParseURL(cfg.URL)
+	if err != nil {
+		log.Error("failed to parse redis url", logger.Err(err))
+		return nil, err
+	}
+
+	client := redis.NewClient(opts)
+	client.AddHook(redistrace.Hook{})
+	return &Store{
+		client: client,
+		stream: stream,
+	}, nil
+}
+
+func (s *Store) Read(ctx context.Context, after string) ([]*denylist.Revocation, string, error) {
+	streams, err := s.client.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{s.stream, after},
+		Count:   tokenauth.RevocationBatch,
+		Block:   readBlock,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, after, nil
+		}
+		return nil, after, err
+	}
+
+	var revs []*denylist.Revocation
+	for _, stream := range streams {
+		for _, msg := range stream.Messages {
+			after = msg.ID
+			revs = append(revs, denylist.Parse(msg.Values))
+		}
+	}
+	return revs, after, nil
+}
+
+func (s *Store) IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error) {
+	return denylist.IsRevoked(ctx, s.client, tokenID, userID, issuedAt)
+}
+
+func (s *Store) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,152 @@
+package tokenauth
+
+import (
+	"api_gateway/logger"
+	"context"
+	"github.com/deeelis/platform/denylist"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// RevocationBatch — сколько записей ленты RevocationStore.Read отдаёт за раз
+	RevocationBatch = 500
+
+	retryInterval = time.Second
+	purgeInterval = time.Minute
+)
+
+// RevocationStore — лента отзывов и прямая проверка отзыва в хранилище.
+type RevocationStore interface {
+	// Read ждёт записи ленты после after и возвращает не больше
+	// RevocationBatch из них вместе с id последней.
+	Read(ctx context.Context, after string) ([]*denylist.Revocation, string, error)
+	IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error)
+}
+
+type userRevocation struct {
+	before time.Time
+	until  time.Time
+}
+
+// Revocations — копия ленты отзывов в памяти процесса. Пока лента не дочитана
+// до конца или чтение из неё сбоит, копия может отставать, и проверки уходят
+// в store; ошибка store — отказ, а не пропуск токена. Конец ленты — чтение,
+// вернувшее меньше RevocationBatch записей.
+type Revocations struct {
+	store  RevocationStore
+	log    *slog.Logger
+	synced atomic.Bool
+
+	mu     sync.RWMutex
+	tokens map[string]time.Time
+	users  map[string]userRevocation
+}
+
+func NewRevocations(store RevocationStore, log *slog.Logger) *Revocations {
+	return &Revocations{
+		store:  store,
+		log:    log.With(slog.String("op", "tokenauth.Revocations")),
+		tokens: make(map[string]time.Time),
+		users:  make(map[string]userRevocation),
+	}
+}
+
+// Run читает ленту с начала, пока не отменён ctx. После сбоя чтение
+// продолжается с последней полученной записи.
+func (r *Revocations) Run(ctx context.Context) {
+	lastID := "0"
+	lastPurge := time.Now()
+
+	for ctx.Err() == nil {
+		revs, last, err := r.store.Read(ctx, lastID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if r.synced.Swap(false) {
+				r.log.Warn("revocation feed lost, checking store directly", logger.Err(err))
+			} else {
+				r.log.Warn("failed to read revocation feed", logger.Err(err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+			continue
+		}
+
+		lastID = last
+		for _, rev := range revs {
+			r.Add(rev)
+		}
+		if len(revs) < RevocationBatch && !r.synced.Swap(true) {
+			r.log.Info("revocation feed synced", slog.String("last_id", lastID))
+		}
+
+		if now := time.Now(); now.Sub(lastPurge) >= purgeInterval {
+			r.Purge(now)
+			lastPurge = now
+		}
+	}
+}
+
+func (r *Revocations) Add(rev *denylist.Revocation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if rev.TokenID != "" {
+		if rev.Until.After(r.tokens[rev.TokenID]) {
+			r.tokens[rev.TokenID] = rev.Until
+		}
+	}
+	if rev.UserID != "" && !rev.Before.IsZero() {
+		current := r.users[rev.UserID]
+		if rev.Before.After(current.before) {
+			current.before = rev.Before
+		}
+		if rev.Until.After(current.until) {
+			current.until = rev.Until
+		}
+		r.users[rev.UserID] = current
+	}
+}
+
+func (r *Revocations) Revoked(ctx context.Context, claims *Claims) (bool, error) {
+	if !r.synced.Load() {
+		return r.store.IsRevoked(ctx, claims.ID, claims.UserID, claims.IssuedAt)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if claims.ID != "" {
+		if _, ok := r.tokens[claims.ID]; ok {
+			return true, nil
+		}
+	}
+	if user, ok := r.users[claims.UserID]; ok && claims.IssuedAt.Before(user.before) {
+		return true, nil
+	}
+	return false, nil
+}
+
+// Purge забывает отзывы, чьи токены уже истекли.
+func (r *Revocations) Purge(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, until := range r.tokens {
+		if until.Before(now) {
+			delete(r.tokens, id)
+		}
+	}
+	for id, user := range r.users {
+		if user.until.Before(now) {
+			delete(r.users, id)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package tokenauth
+
+import (
+	"context"
+	"errors"
+	"github.com/deeelis/platform/denylist"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+type readResult struct {
+	revs []*denylist.Revocation
+	err  error
+}
+
+// fakeRevocationStore отдаёт ленту из reads; когда они кончаются, Read ждёт
+// отмены ctx, как XRead с блокировкой.
+type fakeRevocationStore struct {
+	reads      chan readResult
+	revoked    bool
+	lookupErr  error
+	lookupUsed int
+}
+
+func (f *fakeRevocationStore) Read(ctx context.Context, after string) ([]*denylist.Revocation, string, error) {
+	select {
+	case res := <-f.reads:
+		return res.revs, after, res.err
+	case <-ctx.Done():
+		return nil, after, ctx.Err()
+	}
+}
+
+func (f *fakeRevocationStore) IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error) {
+	f.lookupUsed++
+	return f.revoked, f.lookupErr
+}
+
+func waitSynced(t *testing.T, r *Revocations, want bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for r.synced.Load() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("synced = %v, want %v", !want, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRevocationsCheckStoreUntilSynced(t *testing.T) {
+	store := &fakeRevocationStore{reads: make(chan readResult, 1), lookupErr: errors.New("redis down")}
+	r := NewRevocations(store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	claims := &Claims{ID: "jti-1", UserID: "user-1", IssuedAt: time.Now()}
+
+	if _, err := r.Revoked(context.Background(), claims); err == nil {
+		t.Fatal("Revoked before sync with store down: want error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx)
+
+	store.reads <- readResult{revs: []*denylist.Revocation{{TokenID: "jti-1", Until: time.Now().Add(time.Hour)}}}
+	waitSynced(t, r, true)
+
+	revoked, err := r.Revoked(context.Background(), claims)
+	if err != nil || !revoked {
+		t.Fatalf("Revoked after sync = %v, %v; want true, nil", revoked, err)
+	}
+	if store.lookupUsed != 1 {
+		t.Errorf("store lookups = %d, want 1", store.lookupUsed)
+	}
+}
+
+func TestRevocationsFallBackWhenFeedFails(t *testing.T) {
+	store := &fakeRevocationStore{reads: make(chan readResult, 1), revoked: true}
+	r := NewRevocations(store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx)
+
+	store.reads <- readResult{}
+	waitSynced(t, r, true)
+
+	// отзыв мог прийти, пока лента недоступна: решает хранилище
+	store.reads <- readResult{err: errors.New("connection reset")}
+	waitSynced(t, r, false)
+
+	revoked, err := r.Revoked(context.Background(), &Claims{ID: "jti-2", UserID: "user-1", IssuedAt: time.Now()})
+	if err != nil || !revoked {
+		t.Fatalf("Revoked with feed down = %v, %v; want true, nil", revoked, err)
+	}
+}
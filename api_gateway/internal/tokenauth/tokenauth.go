@@ -0,0 +1,86 @@
+// Package tokenauth проверяет access-токены в шлюзе без обращения к auth_service
+// на каждый запрос: проверенные токены кешируются, отозванные приходят лентой отзывов.
+package tokenauth
+
+import (
+	"api_gateway/internal/domain/errors"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	errorsv1 "github.com/deeelis/errors-protos/gen/go/errors"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Claims — то, что шлюзу нужно от проверенного access-токена.
+type Claims struct {
+	UserID string
+	// ID — jti токена; у токенов, выпущенных до его появления, пуст
+	ID        string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// Backend проверяет токен, которого нет в кеше.
+type Backend interface {
+	Verify(ctx context.Context, token string) (*Claims, error)
+}
+
+type Verifier struct {
+	backend     Backend
+	cache       *Cache
+	revocations *Revocations
+	log         *slog.Logger
+}
+
+func NewVerifier(backend Backend, cacheSize int, revocations *Revocations, log *slog.Logger) *Verifier {
+	return &Verifier{
+		backend:     backend,
+		cache:       NewCache(cacheSize),
+		revocations: revocations,
+		log:         log,
+	}
+}
+
+// ValidateToken возвращает id пользователя. Отзыв проверяется и для токенов
+// из кеша: запись в кеше могла появиться раньше, чем пришёл отзыв.
+func (v *Verifier) ValidateToken(ctx context.Context, token string) (string, error) {
+	token = strings.TrimSpace(strings.TrimPrefix(token, "Bearer "))
+	if token == "" {
+		return "", errors.ErrUnauthorized
+	}
+
+	key := cacheKey(token)
+	claims, ok := v.cache.Get(key, time.Now())
+	if !ok {
+		var err error
+		claims, err = v.backend.Verify(ctx, token)
+		if err != nil {
+			return "", err
+		}
+		if !claims.ExpiresAt.IsZero() {
+			v.cache.Add(key, claims)
+		}
+	}
+
+	revoked, err := v.revocations.Revoked(ctx, claims)
+	if err != nil {
+		// без списка отзывов токен нельзя считать действительным
+		return "", errors.New(errorsv1.ErrorCode_UNAVAILABLE, "revocation check unavailable", err)
+	}
+	if revoked {
+		v.log.Info("revoked token rejected",
+			slog.String("user_id", claims.UserID),
+			slog.String("jti", claims.ID))
+		return "", fmt.Errorf("%w: token revoked", errors.ErrUnauthorized)
+	}
+	return claims.UserID, nil
+}
+
+// cacheKey не хранит сам токен в памяти дольше, чем нужно для проверки.
+func cacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
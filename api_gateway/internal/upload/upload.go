@@ -0,0 +1,157 @@
+package upload
+
+import (
+	"api_gateway/internal/config"
+	"fmt"
+	"strings"
+)
+
+// Kind — вид загрузки, по которому выбирается лимит тела запроса.
+type Kind string
+
+const (
+	KindText  Kind = "text"
+	KindImage Kind = "image"
+	KindBatch Kind = "batch"
+)
+
+// DefaultTier — имя базовой политики для пользователей без назначенного тарифа.
+const DefaultTier = "default"
+
+// multipartOverhead — запас на границы и заголовки частей multipart-формы
+// сверх размера самого файла.
+const multipartOverhead = 64 << 10
+
+// Policy — действующие для пользователя лимиты загрузки.
+type Policy struct {
+	Tier              string
+	MaxTextBytes      int64
+	MaxTextRunes      int
+	MaxImageBytes     int64
+	MaxBatchBytes     int64
+	AllowedImageTypes []string
+}
+
+// Policies хранит базовую политику и политики тарифов с уже применёнными
+// переопределениями, так что на запрос приходится только поиск в map.
+type Policies struct {
+	base  *Policy
+	tiers map[string]*Policy
+	users map[string]string
+}
+
+func New(cfg *config.UploadConfig) (*Policies, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("upload policy is not configured")
+	}
+
+	base := &Policy{
+		Tier:              DefaultTier,
+		MaxTextBytes:      cfg.MaxTextBytes,
+		MaxTextRunes:      cfg.MaxTextRunes,
+		MaxImageBytes:     cfg.MaxImageBytes,
+		MaxBatchBytes:     cfg.MaxBatchBytes,
+		AllowedImageTypes: normalizeTypes(cfg.AllowedImageTypes),
+	}
+	if err := base.validate(); err != nil {
+		return nil, err
+	}
+
+	tiers := make(map[string]*Policy, len(cfg.Tiers))
+	for name, override := range cfg.Tiers {
+		tiers[name] = base.merge(name, override)
+	}
+
+	for userID, tier := range cfg.Users {
+		if _, ok := tiers[tier]; !ok {
+			return nil, fmt.Errorf("upload policy: user %s refers to unknown tier %q", userID, tier)
+		}
+	}
+
+	return &Policies{
+		base:  base,
+		tiers: tiers,
+		users: cfg.Users,
+	}, nil
+}
+
+// For возвращает политику тарифа пользователя или базовую, если тариф не назначен.
+func (p *Policies) For(userID string) *Policy {
+	if tier, ok := p.users[userID]; ok {
+		return p.tiers[tier]
+	}
+	return p.base
+}
+
+// BodyLimit — предел тела запроса для вида загрузки.
+func (p *Policy) BodyLimit(kind Kind) int64 {
+	switch kind {
+	case KindText:
+		return p.MaxTextBytes
+	case KindImage:
+		return p.MaxImageBytes + multipartOverhead
+	default:
+		return p.MaxBatchBytes
+	}
+}
+
+func (p *Policy) AllowsImageType(mimeType string) bool {
+	mimeType = strings.ToLower(mimeType)
+	for _, allowed := range p.AllowedImageTypes {
+		if allowed == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Policy) validate() error {
+	switch {
+	case p.MaxTextBytes <= 0:
+		return fmt.Errorf("upload policy: max_text_bytes must be positive")
+	case p.MaxTextRunes <= 0:
+		return fmt.Errorf("upload policy: max_text_runes must be positive")
+	case p.MaxImageBytes <= 0:
+		return fmt.Errorf("upload policy: max_image_bytes must be positive")
+	case p.MaxBatchBytes <= 0:
+		return fmt.Errorf("upload policy: max_batch_bytes must be positive")
+	case len(p.AllowedImageTypes) == 0:
+		return fmt.Errorf("upload policy: allowed_image_types must not be empty")
+	}
+	return nil
+}
+
+// merge накладывает переопределения тарифа на базовую политику; незаданные поля наследуются.
+func (p *Policy) merge(tier string, override *config.UploadPolicy) *Policy {
+	merged := *p
+	merged.Tier = tier
+	if override == nil {
+		return &merged
+	}
+	if override.MaxTextBytes > 0 {
+		merged.MaxTextBytes = override.MaxTextBytes
+	}
+	if override.MaxTextRunes > 0 {
+		merged.MaxTextRunes = override.MaxTextRunes
+	}
+	if override.MaxImageBytes > 0 {
+		merged.MaxImageBytes = override.MaxImageBytes
+	}
+	if override.MaxBatchBytes > 0 {
+		merged.MaxBatchBytes = override.MaxBatchBytes
+	}
+	if len(override.AllowedImageTypes) > 0 {
+		merged.AllowedImageTypes = normalizeTypes(override.AllowedImageTypes)
+	}
+	return &merged
+}
+
+func normalizeTypes(types []string) []string {
+	normalized := make([]string, 0, len(types))
+	for _, t := range types {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			normalized = append(normalized, t)
+		}
+	}
+	return normalized
+}
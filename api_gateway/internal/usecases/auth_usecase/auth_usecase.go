@@ -7,6 +7,7 @@ import (
 	"api_gateway/logger"
 	"context"
 	"log/slog"
+	"strings"
 	"time"
 
 	"api_gateway/internal/domain/errors"
@@ -166,6 +167,82 @@ func (uc *AuthUsecase) RefreshToken(ctx context.Context, refreshToken string) (*
 	return tokenDetails, nil
 }
 
+func (uc *AuthUsecase) Logout(ctx context.Context, token string) error {
+	const op = "auth_usecase.Logout"
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.Int("token_length", len(token)),
+	)
+
+	log.Info("logging out session")
+	startTime := time.Now()
+
+	token = strings.TrimSpace(strings.TrimPrefix(token, "Bearer "))
+	if token == "" {
+		log.Warn("empty token provided",
+			slog.Duration("duration", time.Since(startTime)))
+		return errors.ErrUnauthorized
+	}
+
+	if err := uc.client.Logout(ctx, token); err != nil {
+		log.Error("logout failed",
+			logger.Err(err),
+			slog.Duration("duration", time.Since(startTime)))
+		return err
+	}
+
+	log.Info("session logged out successfully",
+		slog.Duration("duration", time.Since(startTime)))
+
+	return nil
+}
+
+func (uc *AuthUsecase) LogoutAll(ctx context.Context, token string) error {
+	const op = "auth_usecase.LogoutAll"
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.Int("token_length", len(token)),
+	)
+
+	log.Info("logging out all sessions")
+	startTime := time.Now()
+
+	token = strings.TrimSpace(strings.TrimPrefix(token, "Bearer "))
+	if token == "" {
+		log.Warn("empty token provided",
+			slog.Duration("duration", time.Since(startTime)))
+		return errors.ErrUnauthorized
+	}
+
+	if err := uc.client.LogoutAll(ctx, token); err != nil {
+		log.Error("logout failed",
+			logger.Err(err),
+			slog.Duration("duration", time.Since(startTime)))
+		return err
+	}
+
+	log.Info("all sessions logged out successfully",
+		slog.Duration("duration", time.Since(startTime)))
+
+	return nil
+}
+
+func (uc *AuthUsecase) GetJWKS(ctx context.Context) ([]*models.JWK, error) {
+	return uc.client.GetJWKS(ctx)
+}
+
+func (uc *AuthUsecase) VerifyEmail(ctx context.Context, token string) error {
+	return uc.client.VerifyEmail(ctx, token)
+}
+
+func (uc *AuthUsecase) RequestPasswordReset(ctx context.Context, email string) error {
+	return uc.client.RequestPasswordReset(ctx, email)
+}
+
+func (uc *AuthUsecase) ConfirmPasswordReset(ctx context.Context, token, password string) error {
+	return uc.client.ConfirmPasswordReset(ctx, token, password)
+}
+
 func validateCredentials(email, password, name string) error {
 	// TODO: добавить валидацию email и password
 	if email == "" || password == "" || name == "" {
@@ -3,10 +3,18 @@ package usecases
 import (
 	"api_gateway/internal/domain/models"
 	"context"
+	"time"
 )
 
 type ContentUsecase interface {
-	ProcessContent(userID string, contentType models.ContentType, data string, mimeType string) (*models.Content, error)
-	GetContent(ctx context.Context, contentID string) (*models.ContentStatus, error)
+	ProcessContent(ctx context.Context, userID string, contentType models.ContentType, data string, mimeType string) (*models.Content, error)
+	ProcessImage(ctx context.Context, userID string, data []byte, mimeType string) (*models.Content, error)
+	ProcessContentBatch(ctx context.Context, userID string, contents []*models.Content) ([]*models.Content, error)
+	GetContent(ctx context.Context, userID string, contentID string) (*models.ContentStatus, error)
+	GetImage(ctx context.Context, userID string, contentID string) (*models.Image, error)
+	ListContent(ctx context.Context, userID string, filter *models.ContentFilter) (*models.ContentPage, error)
+	DeleteContent(ctx context.Context, userID string, contentID string) (time.Time, error)
+	RestoreContent(ctx context.Context, userID string, contentID string) error
+	WatchContent(ctx context.Context, userID string, contentID string) (<-chan *models.ContentEvent, error)
 	Close() error
 }
@@ -6,19 +6,33 @@ import (
 	"api_gateway/internal/domain/models"
 	"api_gateway/internal/grpc"
 	"api_gateway/internal/grpc/storage_client"
+	"api_gateway/internal/objectstore"
+	"api_gateway/internal/objectstore/s3"
 	kafka3 "api_gateway/internal/producer/kafka"
 	"api_gateway/logger"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	errorsv1 "github.com/deeelis/errors-protos/gen/go/errors"
 	"github.com/google/uuid"
 	"log/slog"
 	"time"
 )
 
+// Способы выдачи изображений из приватного бакета
+const (
+	DeliveryRedirect = "redirect"
+	DeliveryProxy    = "proxy"
+)
+
 type ContentUseCase struct {
-	cfg      *config.Config
-	producer *kafka3.Producer
-	storage  grpc.StorageClient
-	log      *slog.Logger
+	cfg        *config.Config
+	producer   *kafka3.Producer
+	storage    grpc.StorageClient
+	objects    objectstore.ObjectStore
+	moderators map[string]struct{}
+	log        *slog.Logger
 }
 
 func NewContentUseCase(ctx context.Context, cfg *config.Config, log *slog.Logger) (*ContentUseCase, error) {
@@ -30,6 +44,21 @@ func NewContentUseCase(ctx context.Context, cfg *config.Config, log *slog.Logger
 	defer func() {
 		log.Info("initialization completed", slog.Duration("duration", time.Since(start)))
 	}()
+
+	if cfg.Images == nil {
+		return nil, fmt.Errorf("images delivery is not configured")
+	}
+	if cfg.Images.Delivery != DeliveryRedirect && cfg.Images.Delivery != DeliveryProxy {
+		return nil, fmt.Errorf("unknown images delivery %q", cfg.Images.Delivery)
+	}
+	if cfg.Images.Delivery == DeliveryRedirect && cfg.Images.URLTTL <= 0 {
+		return nil, fmt.Errorf("images url_ttl must be positive")
+	}
+	moderators := make(map[string]struct{}, len(cfg.Images.Moderators))
+	for _, id := range cfg.Images.Moderators {
+		moderators[id] = struct{}{}
+	}
+
 	producer, err := kafka3.NewProducer(ctx, cfg.Kafka, log)
 	if err != nil {
 		log.Error("kafka producer init failed", logger.Err(err))
@@ -42,45 +71,79 @@ func NewContentUseCase(ctx context.Context, cfg *config.Config, log *slog.Logger
 		log.Error("storage client init failed", logger.Err(err))
 		return nil, err
 	}
+
+	objects, err := s3.NewS3Store(ctx, cfg.S3, log)
+	if err != nil {
+		log.Error("object store init failed", logger.Err(err))
+		return nil, err
+	}
 	return &ContentUseCase{
-		producer: producer,
-		log:      log,
-		storage:  client,
-		cfg:      cfg,
+		producer:   producer,
+		log:        log,
+		storage:    client,
+		objects:    objects,
+		moderators: moderators,
+		cfg:        cfg,
 	}, nil
 }
 
-func (uc *ContentUseCase) ProcessContent(userID string, contentType models.ContentType, data string, mimeType string) (*models.Content, error) {
+func (uc *ContentUseCase) ProcessContent(ctx context.Context, userID string, contentType models.ContentType, data string, mimeType string) (*models.Content, error) {
+	return uc.process(ctx, &models.Content{
+		ID:       uuid.New().String(),
+		UserID:   userID,
+		Type:     contentType,
+		Data:     data,
+		DataType: mimeType,
+	})
+}
+
+func (uc *ContentUseCase) ProcessImage(ctx context.Context, userID string, data []byte, mimeType string) (*models.Content, error) {
+	return uc.process(ctx, &models.Content{
+		ID:       uuid.New().String(),
+		UserID:   userID,
+		Type:     models.ContentTypeImage,
+		DataType: mimeType,
+		Blob:     data,
+	})
+}
+
+func (uc *ContentUseCase) process(ctx context.Context, content *models.Content) (*models.Content, error) {
 	const op = "content_usecase.ProcessContent"
 	log := uc.log.With(
 		slog.String("op", op),
-		slog.String("user_id", userID),
-		slog.String("content_type", string(contentType)),
-		slog.String("mime_type", mimeType),
+		slog.String("user_id", content.UserID),
+		slog.String("content_id", content.ID),
+		slog.String("content_type", string(content.Type)),
+		slog.String("mime_type", content.DataType),
 	)
 
 	start := time.Now()
+	// обрыв соединения клиента не должен прерывать приём на середине,
+	// но трейс запроса должен дойти до storage и Kafka
+	ctx = context.WithoutCancel(ctx)
 
-	content := &models.Content{
-		ID:       uuid.New().String(),
-		UserID:   userID,
-		Type:     contentType,
-		Data:     data,
-		DataType: mimeType,
+	if content.Blob != nil {
+		log.Debug("storing image", slog.Int("size_bytes", len(content.Blob)))
+		if err := uc.storeImage(ctx, content); err != nil {
+			log.Error("image upload failed",
+				logger.Err(err),
+				slog.Duration("duration", time.Since(start)))
+			return nil, errors.ErrInternalServer
+		}
 	}
 
-	log.Debug("registering content", slog.String("content_id", content.ID))
-	if err := uc.storage.RegisterContent(context.Background(), content.ID, string(content.Type)); err != nil {
+	log.Debug("registering content")
+	if err := uc.storage.RegisterContent(ctx, content); err != nil {
 		log.Error("content registration failed",
 			logger.Err(err),
 			slog.Duration("duration", time.Since(start)))
+		uc.discardImages(ctx, content)
 		return nil, errors.ErrInternalServer
 	}
 
 	log.Debug("producing content",
-		slog.String("content_id", content.ID),
-		slog.Int("data_size", len(data)))
-	if err := uc.producer.ProduceContent(content); err != nil {
+		slog.Int("data_size", len(content.Data)))
+	if err := uc.producer.ProduceContent(ctx, content); err != nil {
 		log.Error("content production failed",
 			logger.Err(err),
 			slog.Duration("duration", time.Since(start)))
@@ -88,24 +151,120 @@ func (uc *ContentUseCase) ProcessContent(userID string, contentType models.Conte
 	}
 
 	log.Info("content processed",
-		slog.String("content_id", content.ID),
 		slog.Duration("duration", time.Since(start)))
 	return content, nil
 }
 
-func (uc *ContentUseCase) GetContent(ctx context.Context, contentID string) (*models.ContentStatus, error) {
+// storeImage кладёт байты изображения в объектное хранилище и оставляет
+// в content только ссылку на объект: в Kafka уходят bucket, key, checksum и size.
+func (uc *ContentUseCase) storeImage(ctx context.Context, content *models.Content) error {
+	sum := sha256.Sum256(content.Blob)
+	checksum := hex.EncodeToString(sum[:])
+	key := fmt.Sprintf("images/%s/%s", time.Now().UTC().Format("2006/01/02"), content.ID)
+
+	err := uc.objects.PutObject(ctx, key, content.Blob, content.DataType, map[string]string{
+		"content-id": content.ID,
+		"user-id":    content.UserID,
+		"sha256":     checksum,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload image: %w", err)
+	}
+
+	content.Bucket = uc.objects.Bucket()
+	content.Key = key
+	content.Checksum = checksum
+	content.Size = int64(len(content.Blob))
+	content.Blob = nil
+	return nil
+}
+
+// discardImages удаляет загруженные объекты, если контент так и не был зарегистрирован.
+func (uc *ContentUseCase) discardImages(ctx context.Context, contents ...*models.Content) {
+	for _, content := range contents {
+		if content.Key == "" {
+			continue
+		}
+		if err := uc.objects.DeleteObject(ctx, content.Key); err != nil {
+			uc.log.Warn("failed to discard orphaned image",
+				slog.String("content_id", content.ID),
+				slog.String("key", content.Key),
+				logger.Err(err))
+		}
+	}
+}
+
+func (uc *ContentUseCase) ProcessContentBatch(ctx context.Context, userID string, contents []*models.Content) ([]*models.Content, error) {
+	const op = "content_usecase.ProcessContentBatch"
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.Int("batch_size", len(contents)),
+	)
+
+	if len(contents) == 0 {
+		log.Warn("empty batch")
+		return nil, errors.ErrInvalidInput
+	}
+
+	start := time.Now()
+	ctx = context.WithoutCancel(ctx)
+
+	for _, content := range contents {
+		content.ID = uuid.New().String()
+		content.UserID = userID
+	}
+
+	for _, content := range contents {
+		if content.Blob == nil {
+			continue
+		}
+		if err := uc.storeImage(ctx, content); err != nil {
+			log.Error("image upload failed",
+				logger.Err(err),
+				slog.String("content_id", content.ID),
+				slog.Duration("duration", time.Since(start)))
+			uc.discardImages(ctx, contents...)
+			return nil, errors.ErrInternalServer
+		}
+	}
+
+	log.Debug("registering content batch")
+	if err := uc.storage.RegisterContentBatch(ctx, contents); err != nil {
+		log.Error("content batch registration failed",
+			logger.Err(err),
+			slog.Duration("duration", time.Since(start)))
+		uc.discardImages(ctx, contents...)
+		return nil, errors.ErrInternalServer
+	}
+
+	log.Debug("producing content batch")
+	if err := uc.producer.ProduceContentBatch(ctx, contents); err != nil {
+		log.Error("content batch production failed",
+			logger.Err(err),
+			slog.Duration("duration", time.Since(start)))
+		return nil, errors.ErrInternalServer
+	}
+
+	log.Info("content batch processed",
+		slog.Duration("duration", time.Since(start)))
+	return contents, nil
+}
+
+func (uc *ContentUseCase) GetContent(ctx context.Context, userID string, contentID string) (*models.ContentStatus, error) {
 	const op = "content_usecase.GetContent"
 	log := uc.log.With(
 		slog.String("op", op),
+		slog.String("user_id", userID),
 		slog.String("content_id", contentID),
 	)
 
 	if contentID == "" {
 		log.Warn("empty content id")
-		return nil, errors.ErrInvalidCredentials
+		return nil, errors.ErrInvalidInput
 	}
 	start := time.Now()
-	status, err := uc.storage.GetContent(ctx, contentID)
+	status, err := uc.storage.GetContent(ctx, userID, contentID, false)
 	if err != nil {
 		log.Error("content fetch failed",
 			logger.Err(err),
@@ -119,6 +278,180 @@ func (uc *ContentUseCase) GetContent(ctx context.Context, contentID string) (*mo
 	return status, nil
 }
 
+// GetImage отдаёт изображение владельцу, когда модерация пропустила его, а модератору —
+// любое, включая отклонённые и ещё не проверенные.
+func (uc *ContentUseCase) GetImage(ctx context.Context, userID string, contentID string) (*models.Image, error) {
+	const op = "content_usecase.GetImage"
+	_, moderator := uc.moderators[userID]
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("content_id", contentID),
+		slog.Bool("moderator", moderator),
+	)
+
+	if contentID == "" {
+		log.Warn("empty content id")
+		return nil, errors.ErrInvalidInput
+	}
+
+	start := time.Now()
+	status, err := uc.storage.GetContent(ctx, userID, contentID, moderator)
+	if err != nil {
+		log.Error("content fetch failed",
+			logger.Err(err),
+			slog.Duration("duration", time.Since(start)))
+		return nil, err
+	}
+
+	if status.Type != "IMAGE" || status.ObjectKey == "" {
+		log.Warn("content has no image", slog.String("content_type", status.Type))
+		return nil, errors.New(errorsv1.ErrorCode_CONTENT_NOT_FOUND, "content is not an image", nil)
+	}
+
+	if !moderator {
+		switch {
+		case status.Status == "PENDING" || status.Status == "PROCESSING":
+			log.Info("image is not moderated yet", slog.String("status", status.Status))
+			return nil, errors.ErrContentNotReady
+		case status.Status != "COMPLETED" || status.Analysis["is_nsfw"] != "false":
+			log.Info("image is hidden by moderation", slog.String("status", status.Status))
+			return nil, errors.ErrImageRejected
+		}
+	}
+
+	image := &models.Image{}
+	if uc.cfg.Images.Delivery == DeliveryProxy {
+		object, err := uc.objects.GetObject(ctx, status.ObjectKey)
+		if err != nil {
+			log.Error("image download failed",
+				logger.Err(err),
+				slog.Duration("duration", time.Since(start)))
+			return nil, errors.ErrInternalServer
+		}
+		image.Body = object.Body
+		image.ContentType = object.ContentType
+		image.Size = object.Size
+	} else {
+		image.URL, err = uc.objects.PresignGet(ctx, status.ObjectKey, uc.cfg.Images.URLTTL)
+		if err != nil {
+			log.Error("image url signing failed",
+				logger.Err(err),
+				slog.Duration("duration", time.Since(start)))
+			return nil, errors.ErrInternalServer
+		}
+	}
+
+	log.Info("image access granted",
+		slog.String("delivery", uc.cfg.Images.Delivery),
+		slog.Duration("duration", time.Since(start)))
+	return image, nil
+}
+
+func (uc *ContentUseCase) ListContent(ctx context.Context, userID string, filter *models.ContentFilter) (*models.ContentPage, error) {
+	const op = "content_usecase.ListContent"
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+	)
+
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		log.Warn("empty created_at range")
+		return nil, errors.ErrInvalidInput
+	}
+
+	start := time.Now()
+	page, err := uc.storage.ListContent(ctx, userID, filter)
+	if err != nil {
+		log.Error("content list failed",
+			logger.Err(err),
+			slog.Duration("duration", time.Since(start)))
+		return nil, err
+	}
+
+	log.Info("content listed",
+		slog.Int("items", len(page.Items)),
+		slog.Duration("duration", time.Since(start)))
+	return page, nil
+}
+
+func (uc *ContentUseCase) DeleteContent(ctx context.Context, userID string, contentID string) (time.Time, error) {
+	const op = "content_usecase.DeleteContent"
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("content_id", contentID),
+	)
+
+	if contentID == "" {
+		log.Warn("empty content id")
+		return time.Time{}, errors.ErrInvalidInput
+	}
+
+	start := time.Now()
+	purgeAfter, err := uc.storage.DeleteContent(ctx, userID, contentID)
+	if err != nil {
+		log.Error("content delete failed",
+			logger.Err(err),
+			slog.Duration("duration", time.Since(start)))
+		return time.Time{}, err
+	}
+
+	log.Info("content deleted",
+		slog.Time("purge_after", purgeAfter),
+		slog.Duration("duration", time.Since(start)))
+	return purgeAfter, nil
+}
+
+func (uc *ContentUseCase) RestoreContent(ctx context.Context, userID string, contentID string) error {
+	const op = "content_usecase.RestoreContent"
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("content_id", contentID),
+	)
+
+	if contentID == "" {
+		log.Warn("empty content id")
+		return errors.ErrInvalidInput
+	}
+
+	start := time.Now()
+	if err := uc.storage.RestoreContent(ctx, userID, contentID); err != nil {
+		log.Error("content restore failed",
+			logger.Err(err),
+			slog.Duration("duration", time.Since(start)))
+		return err
+	}
+
+	log.Info("content restored",
+		slog.Duration("duration", time.Since(start)))
+	return nil
+}
+
+func (uc *ContentUseCase) WatchContent(ctx context.Context, userID string, contentID string) (<-chan *models.ContentEvent, error) {
+	const op = "content_usecase.WatchContent"
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("content_id", contentID),
+	)
+
+	if contentID == "" {
+		log.Warn("empty content id")
+		return nil, errors.ErrInvalidInput
+	}
+
+	events, err := uc.storage.WatchContent(ctx, userID, contentID)
+	if err != nil {
+		log.Error("content watch failed", logger.Err(err))
+		return nil, err
+	}
+
+	log.Info("content watch started")
+	return events, nil
+}
+
 func (uc *ContentUseCase) Close() error {
 	const op = "content_usecase.Close"
 	log := uc.log.With(slog.String("op", op))
@@ -0,0 +1,65 @@
+package content_usecase
+
+import (
+	"api_gateway/internal/config"
+	"api_gateway/internal/domain/errors"
+	"api_gateway/internal/domain/models"
+	"api_gateway/internal/grpc"
+	"api_gateway/internal/objectstore"
+	"context"
+	stderrors "errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// fakeStorage отдаёт одну запись; доступ по владельцу проверяет storage_service,
+// поэтому здесь он не повторяется.
+type fakeStorage struct {
+	grpc.StorageClient
+	status *models.ContentStatus
+}
+
+func (f *fakeStorage) GetContent(ctx context.Context, userID string, contentID string, moderator bool) (*models.ContentStatus, error) {
+	return f.status, nil
+}
+
+type fakeObjects struct {
+	objectstore.ObjectStore
+}
+
+func (fakeObjects) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return "https://images.example/" + key, nil
+}
+
+func newImageUseCase(status *models.ContentStatus) *ContentUseCase {
+	return &ContentUseCase{
+		cfg:        &config.Config{Images: &config.ImagesConfig{Delivery: DeliveryRedirect, URLTTL: time.Minute}},
+		storage:    &fakeStorage{status: status},
+		objects:    fakeObjects{},
+		moderators: map[string]struct{}{"moderator-1": {}},
+		log:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+}
+
+func TestGetImageProcessing(t *testing.T) {
+	uc := newImageUseCase(&models.ContentStatus{
+		ID:        "i1",
+		Type:      "IMAGE",
+		Status:    "PROCESSING",
+		ObjectKey: "images/user-1/i1",
+	})
+
+	image, err := uc.GetImage(context.Background(), "moderator-1", "i1")
+	if err != nil {
+		t.Fatalf("moderator GetImage: %v", err)
+	}
+	if image.URL != "https://images.example/images/user-1/i1" {
+		t.Errorf("moderator URL = %q", image.URL)
+	}
+
+	if _, err := uc.GetImage(context.Background(), "user-1", "i1"); !stderrors.Is(err, errors.ErrContentNotReady) {
+		t.Errorf("owner GetImage error = %v, want %v", err, errors.ErrContentNotReady)
+	}
+}
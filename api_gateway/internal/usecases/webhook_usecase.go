@@ -0,0 +1,15 @@
+package usecases
+
+import (
+	"api_gateway/internal/domain/models"
+	"context"
+)
+
+type WebhookUsecase interface {
+	CreateWebhook(ctx context.Context, userID string, url string, secret string) (*models.Webhook, error)
+	ListWebhooks(ctx context.Context, userID string) ([]*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, userID string, webhookID string) error
+	ListDeliveries(ctx context.Context, userID string, webhookID string, status string, limit int) ([]*models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, userID string, webhookID string, deliveryID string) (*models.WebhookDelivery, error)
+	Close() error
+}
@@ -0,0 +1,172 @@
+package webhook_usecase
+
+import (
+	"api_gateway/internal/config"
+	"api_gateway/internal/domain/errors"
+	"api_gateway/internal/domain/models"
+	"api_gateway/internal/grpc"
+	"api_gateway/internal/grpc/storage_client"
+	"api_gateway/logger"
+	"context"
+	"log/slog"
+	"time"
+)
+
+type WebhookUseCase struct {
+	cfg     *config.Config
+	storage grpc.StorageClient
+	log     *slog.Logger
+}
+
+func NewWebhookUseCase(cfg *config.Config, log *slog.Logger) (*WebhookUseCase, error) {
+	const op = "webhook_usecase.New"
+	log = log.With(slog.String("op", op))
+	log.Info("initializing", slog.String("storage_addr", cfg.Storage.ServiceAddress))
+
+	client, err := storage_client.NewStorageClient(cfg.Storage, log)
+	if err != nil {
+		log.Error("storage client init failed", logger.Err(err))
+		return nil, err
+	}
+
+	return &WebhookUseCase{
+		cfg:     cfg,
+		storage: client,
+		log:     log,
+	}, nil
+}
+
+func (uc *WebhookUseCase) CreateWebhook(ctx context.Context, userID string, url string, secret string) (*models.Webhook, error) {
+	const op = "webhook_usecase.CreateWebhook"
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+	)
+
+	if url == "" {
+		log.Warn("empty webhook url")
+		return nil, errors.ErrInvalidInput
+	}
+
+	start := time.Now()
+	webhook, err := uc.storage.CreateWebhook(ctx, userID, url, secret)
+	if err != nil {
+		log.Error("webhook creation failed",
+			logger.Err(err),
+			slog.Duration("duration", time.Since(start)))
+		return nil, err
+	}
+
+	log.Info("webhook created",
+		slog.String("webhook_id", webhook.ID),
+		slog.Duration("duration", time.Since(start)))
+	return webhook, nil
+}
+
+func (uc *WebhookUseCase) ListWebhooks(ctx context.Context, userID string) ([]*models.Webhook, error) {
+	const op = "webhook_usecase.ListWebhooks"
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+	)
+
+	start := time.Now()
+	webhooks, err := uc.storage.ListWebhooks(ctx, userID)
+	if err != nil {
+		log.Error("webhook listing failed",
+			logger.Err(err),
+			slog.Duration("duration", time.Since(start)))
+		return nil, err
+	}
+
+	log.Info("webhooks listed",
+		slog.Int("items", len(webhooks)),
+		slog.Duration("duration", time.Since(start)))
+	return webhooks, nil
+}
+
+func (uc *WebhookUseCase) DeleteWebhook(ctx context.Context, userID string, webhookID string) error {
+	const op = "webhook_usecase.DeleteWebhook"
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("webhook_id", webhookID),
+	)
+
+	if webhookID == "" {
+		log.Warn("empty webhook id")
+		return errors.ErrInvalidInput
+	}
+
+	start := time.Now()
+	if err := uc.storage.DeleteWebhook(ctx, userID, webhookID); err != nil {
+		log.Error("webhook delete failed",
+			logger.Err(err),
+			slog.Duration("duration", time.Since(start)))
+		return err
+	}
+
+	log.Info("webhook deleted",
+		slog.Duration("duration", time.Since(start)))
+	return nil
+}
+
+func (uc *WebhookUseCase) ListDeliveries(ctx context.Context, userID string, webhookID string, status string, limit int) ([]*models.WebhookDelivery, error) {
+	const op = "webhook_usecase.ListDeliveries"
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("webhook_id", webhookID),
+	)
+
+	if webhookID == "" {
+		log.Warn("empty webhook id")
+		return nil, errors.ErrInvalidInput
+	}
+
+	start := time.Now()
+	deliveries, err := uc.storage.ListWebhookDeliveries(ctx, userID, webhookID, status, limit)
+	if err != nil {
+		log.Error("webhook deliveries listing failed",
+			logger.Err(err),
+			slog.Duration("duration", time.Since(start)))
+		return nil, err
+	}
+
+	log.Info("webhook deliveries listed",
+		slog.Int("items", len(deliveries)),
+		slog.Duration("duration", time.Since(start)))
+	return deliveries, nil
+}
+
+func (uc *WebhookUseCase) Redeliver(ctx context.Context, userID string, webhookID string, deliveryID string) (*models.WebhookDelivery, error) {
+	const op = "webhook_usecase.Redeliver"
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("webhook_id", webhookID),
+		slog.String("delivery_id", deliveryID),
+	)
+
+	if webhookID == "" || deliveryID == "" {
+		log.Warn("empty webhook or delivery id")
+		return nil, errors.ErrInvalidInput
+	}
+
+	start := time.Now()
+	delivery, err := uc.storage.RedeliverWebhook(ctx, userID, webhookID, deliveryID)
+	if err != nil {
+		log.Error("webhook redelivery failed",
+			logger.Err(err),
+			slog.Duration("duration", time.Since(start)))
+		return nil, err
+	}
+
+	log.Info("webhook redelivery scheduled",
+		slog.Duration("duration", time.Since(start)))
+	return delivery, nil
+}
+
+func (uc *WebhookUseCase) Close() error {
+	return uc.storage.Close()
+}
@@ -0,0 +1,91 @@
+// Команда управления ключами подписи токенов:
+//
+//	keys -config config.yaml -action rotate   новый активный ключ, прежний уходит в retiring
+//	keys -config config.yaml -action retire   вывести из оборота ключи, чьи токены уже истекли
+//	keys -config config.yaml -action list     показать ключи в обороте
+package main
+
+import (
+	"auth_service/internal/config"
+	"auth_service/internal/repositories/postgres"
+	"auth_service/internal/signing"
+	"auth_service/logger"
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"time"
+)
+
+func main() {
+	var action string
+	flag.StringVar(&action, "action", "list", "key action (rotate, retire, list)")
+
+	cfg, err := config.MustLoad()
+	if err != nil {
+		slog.Error("failed to load configuration", logger.Err(err))
+		os.Exit(1)
+	}
+
+	log := logger.SetUpLogger(cfg.Env)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	repo, err := postgres.NewSigningKeyRepository(ctx, &cfg.Database, log)
+	if err != nil {
+		log.Error("failed to connect to database", logger.Err(err))
+		os.Exit(1)
+	}
+
+	// ключ в retiring ещё проверяет токены, выпущенные до ротации: самый долгоживущий
+	// из них — refresh-токен, плюс время, за которое реплики узнают о новом ключе
+	retireBefore := time.Now().Add(-cfg.Token.RefreshTokenTTL - cfg.Keys.RefreshInterval)
+
+	switch action {
+	case "rotate":
+		key, err := signing.NewKey()
+		if err != nil {
+			log.Error("failed to generate key", logger.Err(err))
+			os.Exit(1)
+		}
+		if err := repo.Rotate(ctx, key); err != nil {
+			log.Error("failed to rotate signing key", logger.Err(err))
+			os.Exit(1)
+		}
+		log.Info("signing key rotated", slog.String("kid", key.ID))
+
+		if _, err := repo.Retire(ctx, retireBefore); err != nil {
+			log.Error("failed to retire signing keys", logger.Err(err))
+			os.Exit(1)
+		}
+	case "retire":
+		retired, err := repo.Retire(ctx, retireBefore)
+		if err != nil {
+			log.Error("failed to retire signing keys", logger.Err(err))
+			os.Exit(1)
+		}
+		log.Info("signing keys retired", slog.Int64("retired", retired))
+	case "list":
+		keys, err := repo.List(ctx)
+		if err != nil {
+			log.Error("failed to list signing keys", logger.Err(err))
+			os.Exit(1)
+		}
+		for _, key := range keys {
+			attrs := []any{
+				slog.String("kid", key.ID),
+				slog.String("algorithm", key.Algorithm),
+				slog.String("state", key.State),
+				slog.Time("created_at", key.CreatedAt),
+			}
+			if key.RotatedAt != nil {
+				attrs = append(attrs, slog.Time("rotated_at", *key.RotatedAt))
+			}
+			log.Info("signing key", attrs...)
+		}
+	default:
+		log.Error("unknown action", slog.String("action", action))
+		os.Exit(1)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	app2 "auth_service/internal/app"
 	"auth_service/internal/config"
 	"auth_service/logger"
+	"context"
+	"github.com/deeelis/platform/tracing"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -25,6 +27,12 @@ func main() {
 		slog.Int("grpc_port", cfg.GRPC.Port),
 	)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), "auth_service", &cfg.Tracing)
+	if err != nil {
+		log.Error("failed to set up tracing", logger.Err(err))
+		os.Exit(1)
+	}
+
 	app, err := app2.NewApp(log, cfg)
 	if err != nil {
 		log.Error("application initialization failed",
@@ -55,6 +63,12 @@ func main() {
 
 	log.Info("initiating graceful shutdown...")
 	app.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error("failed to flush traces", logger.Err(err))
+	}
 	log.Info("application stopped gracefully",
 		slog.Duration("total_uptime", time.Since(startTime)))
 }
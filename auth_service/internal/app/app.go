@@ -3,8 +3,13 @@ package app
 import (
 	grpc2 "auth_service/internal/app/grpc_server"
 	"auth_service/internal/config"
+	"auth_service/internal/metrics"
+	"auth_service/internal/repositories/postgres"
+	"auth_service/internal/revocation/redis_revocation"
 	"auth_service/logger"
+	"context"
 	"fmt"
+	"github.com/deeelis/platform/health"
 	"log/slog"
 	"net"
 	"time"
@@ -14,6 +19,9 @@ type App struct {
 	cfg        *config.Config
 	log        *slog.Logger
 	gRPCServer *grpc2.Server
+	metrics    *metrics.Server
+	health     *health.Checker
+	stopHealth context.CancelFunc
 }
 
 func NewApp(
@@ -36,6 +44,29 @@ func NewApp(
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	// проба ходит в базу через собственный пул соединений
+	db, err := postgres.NewUserRepository(context.Background(), &cfg.Database, log)
+	if err != nil {
+		log.Error("failed to initialize health checks",
+			logger.Err(err),
+			slog.Duration("duration", time.Since(startTime)))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	revocations, err := redis_revocation.NewRedisStore(cfg, log)
+	if err != nil {
+		log.Error("failed to initialize health checks",
+			logger.Err(err),
+			slog.Duration("duration", time.Since(startTime)))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	checker := health.NewChecker(cfg.Health.Timeout, log)
+	checker.Register("postgres", db.Ping)
+	checker.Register("redis", revocations.Ping)
+
+	metricsServer := metrics.NewServer(&cfg.Metrics, log)
+	metricsServer.Handle("/healthz", checker.LivenessHandler())
+	metricsServer.Handle("/readyz", checker.ReadinessHandler())
+
 	log.Info("application initialized successfully",
 		slog.Duration("duration", time.Since(startTime)))
 
@@ -43,6 +74,8 @@ func NewApp(
 		cfg:        cfg,
 		log:        log,
 		gRPCServer: s,
+		metrics:    metricsServer,
+		health:     checker,
 	}, nil
 }
 
@@ -64,6 +97,12 @@ func (a *App) Run() error {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	go a.metrics.Start()
+
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	a.stopHealth = stopHealth
+	go a.health.Watch(healthCtx, a.cfg.Health.Interval, a.gRPCServer.SetReady)
+
 	log.Info("gRPC server starting",
 		slog.String("address", l.Addr().String()),
 		slog.Duration("startup_time", time.Since(startTime)))
@@ -91,8 +130,15 @@ func (a *App) Stop() {
 	log.Info("initiating application shutdown")
 	startTime := time.Now()
 
+	if a.stopHealth != nil {
+		a.stopHealth()
+	}
 	a.gRPCServer.Stop()
 
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	a.metrics.Stop(ctx)
+
 	log.Info("application stopped gracefully",
 		slog.Duration("shutdown_duration", time.Since(startTime)))
 }
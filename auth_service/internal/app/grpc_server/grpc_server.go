@@ -1,12 +1,19 @@
 package grpc_server
 
 import (
+	"auth_service/internal/clientip"
 	"auth_service/internal/config"
 	controller "auth_service/internal/controllers/grpc"
+	"auth_service/internal/metrics"
 	"auth_service/logger"
 	auth "github.com/deeelis/auth-protos/gen/go/auth"
+	"github.com/deeelis/platform/requestid"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/recovery"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"log/slog"
 	"net"
@@ -17,6 +24,7 @@ type Server struct {
 	cfg    *config.Config
 	log    *slog.Logger
 	Server *grpc.Server
+	health *health.Server
 }
 
 func NewServer(cfg *config.Config, log *slog.Logger) (*Server, error) {
@@ -27,8 +35,17 @@ func NewServer(cfg *config.Config, log *slog.Logger) (*Server, error) {
 		slog.Int("port", cfg.GRPC.Port))
 
 	startTime := time.Now()
-	s := grpc.NewServer(grpc.ChainUnaryInterceptor(
-		recovery.UnaryServerInterceptor()))
+	s := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler(
+			otelgrpc.WithFilter(filters.Not(filters.HealthCheck())))),
+		grpc.ChainUnaryInterceptor(
+			requestid.UnaryServerInterceptor(),
+			clientip.UnaryServerInterceptor(),
+			metrics.UnaryServerInterceptor(),
+			recovery.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(
+			requestid.StreamServerInterceptor(),
+			metrics.StreamServerInterceptor()))
 
 	authController, err := controller.NewAuthController(cfg, log)
 	if err != nil {
@@ -41,6 +58,12 @@ func NewServer(cfg *config.Config, log *slog.Logger) (*Server, error) {
 	auth.RegisterAuthServiceServer(s, authController)
 	log.Debug("auth service registered")
 
+	// до первой успешной проверки зависимостей сервис не готов
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthServer.SetServingStatus(auth.AuthService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(s, healthServer)
+
 	if cfg.Env == "local" || cfg.Env == "dev" {
 		reflection.Register(s)
 		log.Debug("gRPC reflection enabled")
@@ -49,7 +72,7 @@ func NewServer(cfg *config.Config, log *slog.Logger) (*Server, error) {
 	log.Info("gRPC server initialized successfully",
 		slog.Duration("duration", time.Since(startTime)))
 
-	return &Server{Server: s, log: log}, nil
+	return &Server{Server: s, health: healthServer, log: log}, nil
 }
 
 func (s *Server) Start(lis net.Listener) error {
@@ -71,6 +94,16 @@ func (s *Server) Start(lis net.Listener) error {
 	return nil
 }
 
+// SetReady переключает статус grpc.health.v1 для всего сервера и AuthService.
+func (s *Server) SetReady(ready bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if ready {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	s.health.SetServingStatus("", status)
+	s.health.SetServingStatus(auth.AuthService_ServiceDesc.ServiceName, status)
+}
+
 func (s *Server) Stop() {
 	const op = "grpc_server.Stop"
 	log := s.log.With(slog.String("op", op))
@@ -78,6 +111,7 @@ func (s *Server) Stop() {
 	log.Info("initiating graceful shutdown")
 
 	startTime := time.Now()
+	s.health.Shutdown()
 	s.Server.GracefulStop()
 
 	log.Info("gRPC server stopped gracefully",
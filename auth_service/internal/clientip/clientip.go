@@ -0,0 +1,44 @@
+package clientip
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Key — ключ gRPC-метаданных, в котором шлюз передаёт адрес клиента.
+// Адрес соединения здесь бесполезен: это адрес самого шлюза.
+const Key = "x-client-ip"
+
+type ctxKey struct{}
+
+func WithContext(ctx context.Context, ip string) context.Context {
+	if ip == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKey{}, ip)
+}
+
+func FromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(ctxKey{}).(string)
+	return ip
+}
+
+// UnaryServerInterceptor переносит адрес из входящих метаданных в контекст обработчика.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(incoming(ctx), req)
+	}
+}
+
+func incoming(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	if values := md.Get(Key); len(values) > 0 {
+		return WithContext(ctx, values[0])
+	}
+	return ctx
+}
@@ -3,16 +3,26 @@ package config
 import (
 	"errors"
 	"flag"
+	"github.com/deeelis/platform/tracing"
 	"github.com/ilyakaznacheev/cleanenv"
 	"os"
 	"time"
 )
 
 type Config struct {
-	Env      string         `yaml:"env" env-default:"local"`
-	GRPC     GRPCConfig     `yaml:"grpc"`
-	Database DatabaseConfig `yaml:"database"`
-	Token    TokenConfig    `yaml:"token"`
+	Env        string           `yaml:"env" env-default:"local"`
+	GRPC       GRPCConfig       `yaml:"grpc"`
+	Database   DatabaseConfig   `yaml:"database"`
+	Token      TokenConfig      `yaml:"token"`
+	Redis      RedisConfig      `yaml:"redis"`
+	Revocation RevocationConfig `yaml:"revocation"`
+	Keys       KeysConfig       `yaml:"keys"`
+	Lockout    LockoutConfig    `yaml:"lockout"`
+	Email      EmailConfig      `yaml:"email"`
+	Mailer     MailerConfig     `yaml:"mailer"`
+	Metrics    MetricsConfig    `yaml:"metrics"`
+	Health     HealthConfig     `yaml:"health"`
+	Tracing    TracingConfig    `yaml:"tracing"`
 }
 
 type TokenConfig struct {
@@ -20,6 +30,81 @@ type TokenConfig struct {
 	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env-default:"168h"`
 }
 
+type RedisConfig struct {
+	URL string `yaml:"url" env-default:"redis://redis:6379/1"`
+}
+
+// RevocationConfig: stream — Redis Stream, через который отзывы access-токенов
+// расходятся по репликам сервиса и шлюзу. База Redis должна совпадать со шлюзом.
+type RevocationConfig struct {
+	Stream string `yaml:"stream" env-default:"auth:revocations"`
+}
+
+// KeysConfig: refresh_interval — как часто реплика перечитывает ключи подписи,
+// то есть сколько она может подписывать старым ключом после ротации.
+type KeysConfig struct {
+	RefreshInterval time.Duration `yaml:"refresh_interval" env-default:"1m"`
+}
+
+// LockoutConfig — защита Login от подбора пароля. Неудачные попытки считаются
+// отдельно по email и по ip клиента в окне window. После free_attempts неудач
+// по email ответ задерживается на base_delay, удваиваясь с каждой следующей
+// до max_delay. Когда неудач по email набирается account_threshold, а по ip —
+// ip_threshold, вход блокируется на lockout_duration.
+type LockoutConfig struct {
+	Window           time.Duration `yaml:"window" env-default:"15m"`
+	FreeAttempts     int64         `yaml:"free_attempts" env-default:"3"`
+	BaseDelay        time.Duration `yaml:"base_delay" env-default:"500ms"`
+	MaxDelay         time.Duration `yaml:"max_delay" env-default:"8s"`
+	AccountThreshold int64         `yaml:"account_threshold" env-default:"10"`
+	IPThreshold      int64         `yaml:"ip_threshold" env-default:"100"`
+	LockoutDuration  time.Duration `yaml:"lockout_duration" env-default:"15m"`
+}
+
+// EmailConfig: verify_url и reset_url — страницы, на которые ведут ссылки
+// из писем, токен добавляется к ним параметром token; шлюз отдаёт такие
+// страницы по /verify-email и /reset-password. unverified_policy — что
+// доступно аккаунту с неподтверждённым email: allow — всё, grace — вход
+// в течение grace_period после регистрации, deny — вход только после подтверждения.
+// Вход с неподтверждённым email отправляет письмо заново, но не чаще resend_interval.
+type EmailConfig struct {
+	VerifyTokenTTL   time.Duration `yaml:"verify_token_ttl" env-default:"24h"`
+	ResetTokenTTL    time.Duration `yaml:"reset_token_ttl" env-default:"1h"`
+	VerifyURL        string        `yaml:"verify_url" env-default:"http://localhost:8080/verify-email"`
+	ResetURL         string        `yaml:"reset_url" env-default:"http://localhost:8080/reset-password"`
+	UnverifiedPolicy string        `yaml:"unverified_policy" env-default:"grace"`
+	GracePeriod      time.Duration `yaml:"grace_period" env-default:"72h"`
+	ResendInterval   time.Duration `yaml:"resend_interval" env-default:"10m"`
+}
+
+// MailerConfig: driver — smtp, file или log. file дописывает письма в file,
+// log пишет их в лог сервиса: так сервис работает без почтового сервера.
+type MailerConfig struct {
+	Driver string     `yaml:"driver" env-default:"log"`
+	From   string     `yaml:"from" env-default:"no-reply@localhost"`
+	File   string     `yaml:"file"`
+	SMTP   SMTPConfig `yaml:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port" env-default:"587"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD"`
+}
+
+type MetricsConfig struct {
+	Port int `yaml:"port" env-default:"9090"`
+}
+
+type HealthConfig struct {
+	Timeout  time.Duration `yaml:"timeout" env-default:"2s"`
+	Interval time.Duration `yaml:"interval" env-default:"10s"`
+}
+
+// TracingConfig — настройки экспорта трейсов, общие для всех сервисов
+type TracingConfig = tracing.Config
+
 type GRPCConfig struct {
 	Port    int           `yaml:"port"`
 	Timeout time.Duration `yaml:"timeout"`
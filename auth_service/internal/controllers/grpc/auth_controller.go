@@ -1,28 +1,37 @@
 package grpc
 
 import (
+	"auth_service/internal/clientip"
 	"auth_service/internal/config"
 	e "auth_service/internal/domain/errors"
 	"auth_service/internal/domain/models"
+	"auth_service/internal/signing"
 	"auth_service/internal/usecases"
 	"auth_service/internal/usecases/auth_usecase"
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
+	"github.com/deeelis/platform/requestid"
 	"log/slog"
 	"os"
 	"time"
 
 	auth "github.com/deeelis/auth-protos/gen/go/auth"
+	errorsv1 "github.com/deeelis/errors-protos/gen/go/errors"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/metadata"
 )
 
+// adminTokenKey — ключ метаданных с токеном администратора для UnlockAccount.
+const adminTokenKey = "x-admin-token"
+
 type AuthController struct {
 	cfg *config.Config
 	log *slog.Logger
 	auth.UnimplementedAuthServiceServer
 	authUsecase usecases.AuthUsecase
+	adminToken  string
 }
 
 func NewAuthController(cfg *config.Config, log *slog.Logger) (*AuthController, error) {
@@ -33,10 +42,17 @@ func NewAuthController(cfg *config.Config, log *slog.Logger) (*AuthController, e
 		slog.String("refresh_token_ttl", cfg.Token.RefreshTokenTTL.String()))
 
 	startTime := time.Now()
+	// токены подписываются ключами из signing_keys, SECRET_KEY нужен только
+	// для проверки HS256-токенов, выпущенных до перехода
 	secretKey := os.Getenv("SECRET_KEY")
 	if secretKey == "" {
-		log.Error("empty secret key")
-		return nil, errors.New("empty secret key")
+		log.Info("SECRET_KEY is not set, HS256 tokens are not accepted")
+	}
+
+	// без ADMIN_TOKEN административные вызовы отклоняются
+	adminToken := os.Getenv("ADMIN_TOKEN")
+	if adminToken == "" {
+		log.Info("ADMIN_TOKEN is not set, admin RPCs are disabled")
 	}
 
 	authUsecase, err := auth_usecase.NewAuthUsecase(cfg, log, secretKey, cfg.Token.TokenTTL, cfg.Token.RefreshTokenTTL)
@@ -50,13 +66,14 @@ func NewAuthController(cfg *config.Config, log *slog.Logger) (*AuthController, e
 	log.Info("auth controller initialized successfully",
 		slog.Duration("duration", time.Since(startTime)))
 
-	return &AuthController{authUsecase: authUsecase, cfg: cfg, log: log}, nil
+	return &AuthController{authUsecase: authUsecase, adminToken: adminToken, cfg: cfg, log: log}, nil
 }
 
 func (c *AuthController) Register(ctx context.Context, req *auth.RegisterRequest) (*auth.RegisterResponse, error) {
 	const op = "grpc.AuthController.Register"
 	log := c.log.With(
 		slog.String("op", op),
+		slog.String("request_id", requestid.FromContext(ctx)),
 		slog.String("email", req.Email),
 		slog.Int("name_length", len(req.Name)),
 	)
@@ -75,12 +92,12 @@ func (c *AuthController) Register(ctx context.Context, req *auth.RegisterRequest
 		if errors.Is(err, e.ErrUserAlreadyExists) {
 			log.Warn("user already exists",
 				slog.Duration("duration", time.Since(startTime)))
-			return nil, status.Error(codes.AlreadyExists, "user already exists")
+			return nil, statusError(codes.AlreadyExists, errorsv1.ErrorCode_USER_ALREADY_EXISTS, "user already exists")
 		}
 		log.Error("registration failed",
 			slog.String("error", err.Error()),
 			slog.Duration("duration", time.Since(startTime)))
-		return nil, status.Error(codes.Internal, "failed to register user")
+		return nil, statusError(codes.Internal, errorsv1.ErrorCode_INTERNAL, "failed to register user")
 	}
 
 	log.Info("user registered successfully",
@@ -94,24 +111,35 @@ func (c *AuthController) Login(ctx context.Context, req *auth.LoginRequest) (*au
 	const op = "grpc.AuthController.Login"
 	log := c.log.With(
 		slog.String("op", op),
+		slog.String("request_id", requestid.FromContext(ctx)),
 		slog.String("email", req.Email),
+		slog.String("client_ip", clientip.FromContext(ctx)),
 	)
 
 	log.Info("authenticating user")
 	startTime := time.Now()
 
-	tokens, err := c.authUsecase.Login(ctx, req.Email, req.Password)
+	tokens, err := c.authUsecase.Login(ctx, req.Email, req.Password, clientip.FromContext(ctx))
 	if err != nil {
-		log.Error(err.Error())
+		if errors.Is(err, e.ErrTooManyAttempts) {
+			log.Warn("too many login attempts",
+				slog.Duration("duration", time.Since(startTime)))
+			return nil, statusError(codes.ResourceExhausted, errorsv1.ErrorCode_RATE_LIMITED, "too many login attempts, try again later")
+		}
+		if errors.Is(err, e.ErrEmailNotVerified) {
+			log.Warn("email not verified",
+				slog.Duration("duration", time.Since(startTime)))
+			return nil, statusError(codes.FailedPrecondition, errorsv1.ErrorCode_PERMISSION_DENIED, "email is not verified")
+		}
 		if errors.Is(err, e.ErrInvalidCredentials) {
 			log.Warn("invalid credentials",
 				slog.Duration("duration", time.Since(startTime)))
-			return nil, status.Error(codes.Unauthenticated, "invalid credentials")
+			return nil, statusError(codes.Unauthenticated, errorsv1.ErrorCode_INVALID_CREDENTIALS, "invalid credentials")
 		}
 		log.Error("login failed",
 			slog.String("error", err.Error()),
 			slog.Duration("duration", time.Since(startTime)))
-		return nil, status.Error(codes.Internal, "failed to login")
+		return nil, statusError(codes.Internal, errorsv1.ErrorCode_INTERNAL, "failed to login")
 	}
 
 	log.Info("user authenticated successfully",
@@ -127,18 +155,25 @@ func (c *AuthController) ValidateToken(ctx context.Context, req *auth.ValidateTo
 	const op = "grpc.AuthController.ValidateToken"
 	log := c.log.With(
 		slog.String("op", op),
+		slog.String("request_id", requestid.FromContext(ctx)),
 		slog.Int("token_length", len(req.Token)),
 	)
 
 	log.Debug("validating token")
 	startTime := time.Now()
 
-	userID, err := c.authUsecase.ValidateToken(req.Token)
+	userID, err := c.authUsecase.ValidateToken(ctx, req.Token)
 	if err != nil {
+		if errors.Is(err, e.ErrInternalServer) {
+			log.Error("token validation failed",
+				slog.String("error", err.Error()),
+				slog.Duration("duration", time.Since(startTime)))
+			return nil, statusError(codes.Internal, errorsv1.ErrorCode_INTERNAL, "failed to validate token")
+		}
 		log.Warn("invalid token",
 			slog.String("error", err.Error()),
 			slog.Duration("duration", time.Since(startTime)))
-		return nil, status.Error(codes.Unauthenticated, "invalid token")
+		return nil, statusError(codes.Unauthenticated, errorsv1.ErrorCode_TOKEN_INVALID, "invalid token")
 	}
 
 	log.Debug("token validated successfully",
@@ -155,18 +190,25 @@ func (c *AuthController) RefreshToken(ctx context.Context, req *auth.RefreshToke
 	const op = "grpc.AuthController.RefreshToken"
 	log := c.log.With(
 		slog.String("op", op),
+		slog.String("request_id", requestid.FromContext(ctx)),
 		slog.Int("token_length", len(req.RefreshToken)),
 	)
 
 	log.Info("refreshing token")
 	startTime := time.Now()
 
-	tokens, err := c.authUsecase.RefreshToken(req.RefreshToken)
+	tokens, err := c.authUsecase.RefreshToken(ctx, req.RefreshToken)
 	if err != nil {
+		if errors.Is(err, e.ErrInternalServer) {
+			log.Error("refresh failed",
+				slog.String("error", err.Error()),
+				slog.Duration("duration", time.Since(startTime)))
+			return nil, statusError(codes.Internal, errorsv1.ErrorCode_INTERNAL, "failed to refresh token")
+		}
 		log.Warn("invalid refresh token",
 			slog.String("error", err.Error()),
 			slog.Duration("duration", time.Since(startTime)))
-		return nil, status.Error(codes.Unauthenticated, "invalid refresh token")
+		return nil, statusError(codes.Unauthenticated, errorsv1.ErrorCode_TOKEN_INVALID, "invalid refresh token")
 	}
 
 	log.Info("token refreshed successfully",
@@ -177,3 +219,206 @@ func (c *AuthController) RefreshToken(ctx context.Context, req *auth.RefreshToke
 		RefreshToken: tokens.RefreshToken,
 	}, nil
 }
+
+func (c *AuthController) Logout(ctx context.Context, req *auth.LogoutRequest) (*auth.LogoutResponse, error) {
+	const op = "grpc.AuthController.Logout"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.FromContext(ctx)),
+		slog.Int("token_length", len(req.Token)),
+	)
+
+	log.Info("logging out session")
+	startTime := time.Now()
+
+	if err := c.authUsecase.Logout(ctx, req.Token); err != nil {
+		return nil, logoutError(log, err, startTime)
+	}
+
+	log.Info("session logged out successfully",
+		slog.Duration("duration", time.Since(startTime)))
+
+	return &auth.LogoutResponse{}, nil
+}
+
+func (c *AuthController) LogoutAll(ctx context.Context, req *auth.LogoutAllRequest) (*auth.LogoutAllResponse, error) {
+	const op = "grpc.AuthController.LogoutAll"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.FromContext(ctx)),
+		slog.Int("token_length", len(req.Token)),
+	)
+
+	log.Info("logging out all sessions")
+	startTime := time.Now()
+
+	if err := c.authUsecase.LogoutAll(ctx, req.Token); err != nil {
+		return nil, logoutError(log, err, startTime)
+	}
+
+	log.Info("all sessions logged out successfully",
+		slog.Duration("duration", time.Since(startTime)))
+
+	return &auth.LogoutAllResponse{}, nil
+}
+
+func logoutError(log *slog.Logger, err error, startTime time.Time) error {
+	if errors.Is(err, e.ErrInvalidToken) {
+		log.Warn("invalid token",
+			slog.Duration("duration", time.Since(startTime)))
+		return statusError(codes.Unauthenticated, errorsv1.ErrorCode_TOKEN_INVALID, "invalid token")
+	}
+	log.Error("logout failed",
+		slog.String("error", err.Error()),
+		slog.Duration("duration", time.Since(startTime)))
+	return statusError(codes.Internal, errorsv1.ErrorCode_INTERNAL, "failed to logout")
+}
+
+func (c *AuthController) GetJWKS(ctx context.Context, _ *auth.GetJWKSRequest) (*auth.GetJWKSResponse, error) {
+	const op = "grpc.AuthController.GetJWKS"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.FromContext(ctx)),
+	)
+
+	keys := c.authUsecase.PublicKeys()
+	resp := &auth.GetJWKSResponse{Keys: make([]*auth.JWK, 0, len(keys))}
+	for _, key := range keys {
+		resp.Keys = append(resp.Keys, &auth.JWK{
+			Kid: key.ID,
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   signing.EncodeKey(key.PublicKey),
+			Alg: key.Algorithm,
+			Use: "sig",
+		})
+	}
+
+	log.Debug("jwks served", slog.Int("keys", len(resp.Keys)))
+	return resp, nil
+}
+
+func (c *AuthController) UnlockAccount(ctx context.Context, req *auth.UnlockAccountRequest) (*auth.UnlockAccountResponse, error) {
+	const op = "grpc.AuthController.UnlockAccount"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.FromContext(ctx)),
+		slog.String("email", req.Email),
+		slog.String("ip", req.Ip),
+	)
+
+	if !c.isAdmin(ctx) {
+		log.Warn("unlock denied: missing or invalid admin token")
+		return nil, statusError(codes.PermissionDenied, errorsv1.ErrorCode_PERMISSION_DENIED, "admin token required")
+	}
+	if req.Email == "" {
+		return nil, statusError(codes.InvalidArgument, errorsv1.ErrorCode_INVALID_ARGUMENT, "email is required")
+	}
+
+	if err := c.authUsecase.UnlockAccount(ctx, req.Email, req.Ip); err != nil {
+		log.Error("unlock failed",
+			slog.String("error", err.Error()))
+		return nil, statusError(codes.Internal, errorsv1.ErrorCode_INTERNAL, "failed to unlock account")
+	}
+
+	log.Info("account unlocked by admin")
+	return &auth.UnlockAccountResponse{}, nil
+}
+
+func (c *AuthController) isAdmin(ctx context.Context) bool {
+	if c.adminToken == "" {
+		return false
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	values := md.Get(adminTokenKey)
+	return len(values) > 0 && subtle.ConstantTimeCompare([]byte(values[0]), []byte(c.adminToken)) == 1
+}
+
+func (c *AuthController) VerifyEmail(ctx context.Context, req *auth.VerifyEmailRequest) (*auth.VerifyEmailResponse, error) {
+	const op = "grpc.AuthController.VerifyEmail"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.FromContext(ctx)),
+		slog.Int("token_length", len(req.Token)),
+	)
+
+	log.Info("verifying email")
+	startTime := time.Now()
+
+	if err := c.authUsecase.VerifyEmail(ctx, req.Token); err != nil {
+		return nil, emailTokenError(log, err, startTime)
+	}
+
+	log.Info("email verified successfully",
+		slog.Duration("duration", time.Since(startTime)))
+
+	return &auth.VerifyEmailResponse{}, nil
+}
+
+func (c *AuthController) RequestPasswordReset(ctx context.Context, req *auth.RequestPasswordResetRequest) (*auth.RequestPasswordResetResponse, error) {
+	const op = "grpc.AuthController.RequestPasswordReset"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.FromContext(ctx)),
+		slog.String("email", req.Email),
+	)
+
+	log.Info("requesting password reset")
+	startTime := time.Now()
+
+	if req.Email == "" {
+		return nil, statusError(codes.InvalidArgument, errorsv1.ErrorCode_INVALID_ARGUMENT, "email is required")
+	}
+
+	if err := c.authUsecase.RequestPasswordReset(ctx, req.Email); err != nil {
+		log.Error("password reset request failed",
+			slog.String("error", err.Error()),
+			slog.Duration("duration", time.Since(startTime)))
+		return nil, statusError(codes.Internal, errorsv1.ErrorCode_INTERNAL, "failed to request password reset")
+	}
+
+	log.Info("password reset requested",
+		slog.Duration("duration", time.Since(startTime)))
+
+	return &auth.RequestPasswordResetResponse{}, nil
+}
+
+func (c *AuthController) ConfirmPasswordReset(ctx context.Context, req *auth.ConfirmPasswordResetRequest) (*auth.ConfirmPasswordResetResponse, error) {
+	const op = "grpc.AuthController.ConfirmPasswordReset"
+	log := c.log.With(
+		slog.String("op", op),
+		slog.String("request_id", requestid.FromContext(ctx)),
+		slog.Int("token_length", len(req.Token)),
+	)
+
+	log.Info("confirming password reset")
+	startTime := time.Now()
+
+	if req.Password == "" {
+		return nil, statusError(codes.InvalidArgument, errorsv1.ErrorCode_INVALID_ARGUMENT, "password is required")
+	}
+
+	if err := c.authUsecase.ConfirmPasswordReset(ctx, req.Token, req.Password); err != nil {
+		return nil, emailTokenError(log, err, startTime)
+	}
+
+	log.Info("password reset successfully",
+		slog.Duration("duration", time.Since(startTime)))
+
+	return &auth.ConfirmPasswordResetResponse{}, nil
+}
+
+func emailTokenError(log *slog.Logger, err error, startTime time.Time) error {
+	if errors.Is(err, e.ErrInvalidToken) {
+		log.Warn("invalid email token",
+			slog.Duration("duration", time.Since(startTime)))
+		return statusError(codes.InvalidArgument, errorsv1.ErrorCode_INVALID_ARGUMENT, "invalid or expired token")
+	}
+	log.Error("email token request failed",
+		slog.String("error", err.Error()),
+		slog.Duration("duration", time.Since(startTime)))
+	return statusError(codes.Internal, errorsv1.ErrorCode_INTERNAL, "failed to process token")
+}
@@ -0,0 +1,23 @@
+package grpc
+
+import (
+	errorsv1 "github.com/deeelis/errors-protos/gen/go/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const errorDomain = "auth_service"
+
+// statusError собирает статус gRPC с кодом из общего каталога в google.rpc.ErrorInfo.
+func statusError(c codes.Code, code errorsv1.ErrorCode, msg string) error {
+	st := status.New(c, msg)
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: code.String(),
+		Domain: errorDomain,
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -7,5 +7,10 @@ var (
 	ErrUserAlreadyExists  = errors.New("user already exists")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrInvalidToken       = errors.New("invalid token")
+	ErrTokenReused        = errors.New("refresh token reused")
 	ErrInternalServer     = errors.New("internal server error")
+	ErrKeyExists          = errors.New("active signing key already exists")
+	ErrNoSigningKey       = errors.New("no active signing key")
+	ErrTooManyAttempts    = errors.New("too many login attempts")
+	ErrEmailNotVerified   = errors.New("email not verified")
 )
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"crypto/ed25519"
+	"time"
+)
+
+// Состояния ключа подписи.
+const (
+	KeyStateActive   = "active"
+	KeyStateRetiring = "retiring"
+	KeyStateRetired  = "retired"
+)
+
+const KeyAlgorithmEdDSA = "EdDSA"
+
+// SigningKey — ключ подписи токенов; ID попадает в заголовок kid.
+// У выведенного из оборота ключа PrivateKey пуст.
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	PrivateKey ed25519.PrivateKey
+	PublicKey  ed25519.PublicKey
+	State      string
+	CreatedAt  time.Time
+	RotatedAt  *time.Time
+	RetiredAt  *time.Time
+}
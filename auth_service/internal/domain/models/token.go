@@ -1,5 +1,13 @@
 package models
 
+import "time"
+
+// Значения claim typ: access-токен нельзя предъявить для обновления и наоборот.
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
 type TokenDetails struct {
 	AccessToken  string
 	RefreshToken string
@@ -8,3 +16,36 @@ type TokenDetails struct {
 	AtExpires    int64
 	RtExpires    int64
 }
+
+// RefreshToken — выданный refresh-токен. ID совпадает с его jti, сам токен
+// хранится только хешем. FamilyID общий у всех токенов, полученных ротацией
+// от одного входа.
+type RefreshToken struct {
+	ID        string
+	UserID    string
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// Назначения токенов из писем: токен подтверждения email нельзя предъявить
+// для сброса пароля и наоборот.
+const (
+	EmailTokenVerifyEmail   = "verify_email"
+	EmailTokenResetPassword = "reset_password"
+)
+
+// EmailToken — одноразовый токен, отправленный пользователю письмом. Сам токен
+// хранится только хешем.
+type EmailToken struct {
+	ID        string
+	UserID    string
+	Purpose   string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,160 @@
+// Package lockout защищает Login от подбора пароля: считает неудачные попытки
+// по email и по ip клиента, задерживает ответ после нескольких неудач и
+// временно блокирует вход. Счётчики ведутся и для несуществующих email,
+// поэтому по поведению входа нельзя понять, есть ли такой пользователь.
+package lockout
+
+import (
+	"auth_service/internal/config"
+	e "auth_service/internal/domain/errors"
+	"auth_service/internal/metrics"
+	"auth_service/logger"
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const (
+	kindAccount = "account"
+	kindIP      = "ip"
+)
+
+type Store interface {
+	// State возвращает число неудач в текущем окне и оставшийся срок блокировки.
+	State(ctx context.Context, subject string) (int64, time.Duration, error)
+	// AddFailure увеличивает счётчик неудач; окно отсчитывается от первой из них.
+	AddFailure(ctx context.Context, subject string, window time.Duration) (int64, error)
+	Lock(ctx context.Context, subject string, ttl time.Duration) error
+	// Reset сбрасывает и счётчик, и блокировку.
+	Reset(ctx context.Context, subject string) error
+}
+
+// Guard недоступность Store считает поводом пропустить проверку, а не
+// отказать во входе: иначе сбой Redis закрыл бы вход всем.
+type Guard struct {
+	store Store
+	cfg   config.LockoutConfig
+	log   *slog.Logger
+}
+
+func NewGuard(store Store, cfg config.LockoutConfig, log *slog.Logger) *Guard {
+	return &Guard{
+		store: store,
+		cfg:   cfg,
+		log:   log.With(slog.String("op", "lockout.Guard")),
+	}
+}
+
+// Admit возвращает ErrTooManyAttempts, если вход по email или с ip заблокирован,
+// иначе выдерживает задержку, набранную неудачами по email.
+func (g *Guard) Admit(ctx context.Context, email, ip string) error {
+	failures, locked, err := g.store.State(ctx, subject(kindAccount, email))
+	if err != nil {
+		g.log.Warn("failed to get account login state", logger.Err(err))
+		return nil
+	}
+	if locked > 0 {
+		return e.ErrTooManyAttempts
+	}
+
+	if ip != "" {
+		_, ipLocked, err := g.store.State(ctx, subject(kindIP, ip))
+		if err != nil {
+			g.log.Warn("failed to get ip login state", logger.Err(err))
+		} else if ipLocked > 0 {
+			return e.ErrTooManyAttempts
+		}
+	}
+
+	delay := g.delay(failures)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Fail учитывает неудачный вход и блокирует email или ip, набравшие порог.
+func (g *Guard) Fail(ctx context.Context, email, ip string) {
+	g.fail(ctx, kindAccount, email, g.cfg.AccountThreshold)
+	if ip != "" {
+		g.fail(ctx, kindIP, ip, g.cfg.IPThreshold)
+	}
+}
+
+// Succeed сбрасывает счётчик email. Счётчик ip остаётся: удачный вход в свой
+// аккаунт не должен обнулять перебор чужих с того же адреса.
+func (g *Guard) Succeed(ctx context.Context, email string) {
+	if err := g.store.Reset(ctx, subject(kindAccount, email)); err != nil {
+		g.log.Warn("failed to reset account login failures", logger.Err(err))
+	}
+}
+
+// Unlock снимает блокировку email и, если он указан, ip.
+func (g *Guard) Unlock(ctx context.Context, email, ip string) error {
+	if err := g.store.Reset(ctx, subject(kindAccount, email)); err != nil {
+		return err
+	}
+	if ip != "" {
+		return g.store.Reset(ctx, subject(kindIP, ip))
+	}
+	return nil
+}
+
+func (g *Guard) fail(ctx context.Context, kind, value string, threshold int64) {
+	key := subject(kind, value)
+	failures, err := g.store.AddFailure(ctx, key, g.cfg.Window)
+	if err != nil {
+		g.log.Warn("failed to record login failure",
+			slog.String("kind", kind),
+			logger.Err(err))
+		return
+	}
+	if threshold <= 0 || failures < threshold {
+		return
+	}
+
+	if err := g.store.Lock(ctx, key, g.cfg.LockoutDuration); err != nil {
+		g.log.Warn("failed to lock login",
+			slog.String("kind", kind),
+			logger.Err(err))
+		return
+	}
+	metrics.LoginLockout(kind)
+	g.log.Warn("login locked after repeated failures",
+		slog.String("kind", kind),
+		slog.Int64("failures", failures),
+		slog.Duration("lockout", g.cfg.LockoutDuration))
+}
+
+func (g *Guard) delay(failures int64) time.Duration {
+	over := failures - g.cfg.FreeAttempts
+	if over <= 0 || g.cfg.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := g.cfg.BaseDelay
+	for i := int64(1); i < over && delay < g.cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+	if g.cfg.MaxDelay > 0 && delay > g.cfg.MaxDelay {
+		delay = g.cfg.MaxDelay
+	}
+	return delay
+}
+
+// subject — ключ счётчика. Email приводится к нижнему регистру, чтобы перебор
+// не обходил счётчик сменой регистра.
+func subject(kind, value string) string {
+	if kind == kindAccount {
+		value = strings.ToLower(strings.TrimSpace(value))
+	}
+	return kind + ":" + value
+}
@@ -0,0 +1,98 @@
+package redis_lockout
+
+import (
+	"auth_service/internal/config"
+	"auth_service/logger"
+	"context"
+	"errors"
+	"github.com/deeelis/platform/tracing/redistrace"
+	"log/slog"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	failuresKeyPrefix = "auth:login:failures:"
+	lockKeyPrefix     = "auth:login:lock:"
+)
+
+// RedisStore хранит счётчик неудач и блокировку отдельными ключами с TTL:
+// счётчик живёт окно, блокировка — свой срок, и оба истекают сами.
+type RedisStore struct {
+	client *redis.Client
+	log    *slog.Logger
+}
+
+func NewRedisStore(cfg *config.RedisConfig, log *slog.Logger) (*RedisStore, error) {
+	const op = "redis_lockout.NewRedisStore"
+	log = log.With(slog.String("op", op))
+
+	opts, err := redis.ParseURL(cfg.URL)
+	if err != nil {
+		log.Error("failed to parse redis url", logger.Err(err))
+		return nil, err
+	}
+
+	client := redis.NewClient(opts)
+	client.AddHook(redistrace.Hook{})
+	return &RedisStore{
+		client: client,
+		log:    log,
+	}, nil
+}
+
+func (s *RedisStore) State(ctx context.Context, subject string) (int64, time.Duration, error) {
+	var (
+		failures *redis.StringCmd
+		locked   *redis.DurationCmd
+	)
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		failures = pipe.Get(ctx, failuresKeyPrefix+subject)
+		locked = pipe.PTTL(ctx, lockKeyPrefix+subject)
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, 0, err
+	}
+
+	count, err := failures.Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, 0, err
+	}
+	// для ключа без срока или несуществующего PTTL отдаёт отрицательное значение
+	ttl := locked.Val()
+	if ttl < 0 {
+		ttl = 0
+	}
+	return count, ttl, nil
+}
+
+func (s *RedisStore) AddFailure(ctx context.Context, subject string, window time.Duration) (int64, error) {
+	key := failuresKeyPrefix + subject
+
+	var incr *redis.IntCmd
+	// SET NX задаёт срок только первой неудаче, поэтому окно не продлевается
+	// с каждой следующей
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SetNX(ctx, key, 0, window)
+		incr = pipe.Incr(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (s *RedisStore) Lock(ctx context.Context, subject string, ttl time.Duration) error {
+	return s.client.Set(ctx, lockKeyPrefix+subject, "1", ttl).Err()
+}
+
+func (s *RedisStore) Reset(ctx context.Context, subject string) error {
+	return s.client.Del(ctx, failuresKeyPrefix+subject, lockKeyPrefix+subject).Err()
+}
+
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,76 @@
+package mailer
+
+import (
+	"auth_service/internal/config"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// FileMailer дописывает письма в файл вместо отправки.
+type FileMailer struct {
+	path string
+	from string
+	log  *slog.Logger
+
+	mu sync.Mutex
+}
+
+func NewFileMailer(cfg *config.MailerConfig, log *slog.Logger) (*FileMailer, error) {
+	const op = "mailer.NewFileMailer"
+	log = log.With(slog.String("op", op))
+
+	if cfg.File == "" {
+		return nil, errors.New("file mailer requires a file path")
+	}
+
+	log.Info("mail is written to file instead of being sent",
+		slog.String("file", cfg.File))
+	return &FileMailer{
+		path: cfg.File,
+		from: cfg.From,
+		log:  log,
+	}, nil
+}
+
+func (m *FileMailer) Send(_ context.Context, msg *Message) error {
+	data, err := format(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LogMailer пишет письма в лог сервиса. Письма содержат одноразовые токены,
+// поэтому он годится только для локального запуска.
+type LogMailer struct {
+	log *slog.Logger
+}
+
+func NewLogMailer(log *slog.Logger) *LogMailer {
+	log = log.With(slog.String("op", "mailer.LogMailer"))
+	log.Warn("mail is written to the log instead of being sent")
+	return &LogMailer{log: log}
+}
+
+func (m *LogMailer) Send(_ context.Context, msg *Message) error {
+	m.log.Info("mail",
+		slog.String("to", msg.To),
+		slog.String("subject", msg.Subject),
+		slog.String("body", msg.Body))
+	return nil
+}
@@ -0,0 +1,99 @@
+package mailer
+
+import (
+	"auth_service/internal/config"
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"strconv"
+)
+
+type SMTPMailer struct {
+	addr string
+	host string
+	from string
+	auth smtp.Auth
+	log  *slog.Logger
+}
+
+func NewSMTPMailer(cfg *config.MailerConfig, log *slog.Logger) (*SMTPMailer, error) {
+	const op = "mailer.NewSMTPMailer"
+	log = log.With(slog.String("op", op))
+
+	if cfg.SMTP.Host == "" {
+		return nil, errors.New("smtp mailer requires a host")
+	}
+
+	var auth smtp.Auth
+	if cfg.SMTP.Username != "" {
+		auth = smtp.PlainAuth("", cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.Host)
+	}
+
+	log.Info("smtp mailer configured",
+		slog.String("host", cfg.SMTP.Host),
+		slog.Int("port", cfg.SMTP.Port))
+	return &SMTPMailer{
+		addr: net.JoinHostPort(cfg.SMTP.Host, strconv.Itoa(cfg.SMTP.Port)),
+		host: cfg.SMTP.Host,
+		from: cfg.From,
+		auth: auth,
+		log:  log,
+	}, nil
+}
+
+// Send шифрует соединение через STARTTLS, если сервер его поддерживает;
+// PlainAuth без TLS net/smtp сам не отправит, кроме как на localhost.
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	data, err := format(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.auth != nil {
+		if err := client.Auth(m.auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(m.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,35 @@
+// Package metrics описывает метрики Prometheus сервиса авторизации
+// и отдаёт их на отдельном HTTP-порту.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "db_query_duration_seconds",
+	Help:    "Postgres query latency, by repository operation.",
+	Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+}, []string{"query"})
+
+// ObserveQuery засекает время запроса к Postgres:
+//
+//	defer metrics.ObserveQuery("user.GetByEmail")()
+func ObserveQuery(query string) func() {
+	startTime := time.Now()
+	return func() {
+		dbQueryDuration.WithLabelValues(query).Observe(time.Since(startTime).Seconds())
+	}
+}
+
+var loginLockouts = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "login_lockouts_total",
+	Help: "Login lockouts after repeated failures, by subject kind (account or ip).",
+}, []string{"kind"})
+
+func LoginLockout(kind string) {
+	loginLockouts.WithLabelValues(kind).Inc()
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"auth_service/internal/config"
+	"auth_service/logger"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Server — служебный HTTP-сервер: /metrics и пробы /healthz, /readyz.
+type Server struct {
+	server *http.Server
+	mux    *http.ServeMux
+	log    *slog.Logger
+}
+
+func NewServer(cfg *config.MetricsConfig, log *slog.Logger) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	return &Server{
+		server: &http.Server{
+			Addr:              fmt.Sprintf(":%d", cfg.Port),
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		mux: mux,
+		log: log,
+	}
+}
+
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+func (s *Server) Start() {
+	s.log.Info("metrics server started", slog.String("addr", s.server.Addr))
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.log.Error("metrics server failed", logger.Err(err))
+	}
+}
+
+func (s *Server) Stop(ctx context.Context) {
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.log.Error("metrics server shutdown failed", logger.Err(err))
+	}
+}
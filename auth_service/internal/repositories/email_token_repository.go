@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"auth_service/internal/domain/models"
+	"context"
+	"time"
+)
+
+type EmailTokenRepository interface {
+	// Create сохраняет токен; прежние неиспользованные токены того же
+	// назначения у пользователя перестают действовать.
+	Create(ctx context.Context, token *models.EmailToken) error
+	// LastIssued возвращает время выпуска последнего неиспользованного токена
+	// назначения purpose или нулевое время, если такого нет.
+	LastIssued(ctx context.Context, userID, purpose string) (time.Time, error)
+	// VerifyEmail гасит токен подтверждения и отмечает email пользователя
+	// подтверждённым. Возвращает id пользователя.
+	VerifyEmail(ctx context.Context, tokenHash string) (string, error)
+	// ResetPassword гасит токен сброса и заменяет хеш пароля пользователя.
+	// Возвращает id пользователя.
+	ResetPassword(ctx context.Context, tokenHash, passwordHash string) (string, error)
+}
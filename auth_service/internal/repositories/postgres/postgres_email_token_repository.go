@@ -0,0 +1,263 @@
+package postgres
+
+import (
+	"auth_service/internal/config"
+	e "auth_service/internal/domain/errors"
+	"auth_service/internal/domain/models"
+	"auth_service/internal/metrics"
+	"context"
+	"database/sql"
+	"errors"
+	sq "github.com/Masterminds/squirrel"
+	"log/slog"
+	"time"
+)
+
+type EmailTokenRepository struct {
+	cfg *config.DatabaseConfig
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewEmailTokenRepository(ctx context.Context, cfg *config.DatabaseConfig, log *slog.Logger) (*EmailTokenRepository, error) {
+	const op = "postgres.NewEmailTokenRepository"
+	log = log.With(slog.String("op", op))
+
+	db, err := openDB(ctx, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmailTokenRepository{
+		cfg: cfg,
+		db:  db,
+		log: log,
+	}, nil
+}
+
+func (r *EmailTokenRepository) Create(ctx context.Context, token *models.EmailToken) error {
+	defer metrics.ObserveQuery("email_token.Create")()
+	const op = "postgres.EmailTokenRepository.Create"
+	log := r.log.With(
+		slog.String("op", op),
+		slog.String("user_id", token.UserID),
+		slog.String("purpose", token.Purpose),
+	)
+
+	startTime := time.Now()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction",
+			slog.Any("error", err))
+		return e.ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	query, args, err := sq.Delete("email_tokens").
+		Where(sq.Eq{"user_id": token.UserID, "purpose": token.Purpose, "used_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("failed to build SQL query",
+			slog.Any("error", err))
+		return e.ErrInternalServer
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Error("failed to delete previous email tokens",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInternalServer
+	}
+
+	token.CreatedAt = time.Now()
+	query, args, err = sq.Insert("email_tokens").
+		Columns("id", "user_id", "purpose", "token_hash", "expires_at", "created_at").
+		Values(token.ID, token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt, token.CreatedAt).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("failed to build SQL query",
+			slog.Any("error", err))
+		return e.ErrInternalServer
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Error("failed to create email token",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInternalServer
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit email token",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInternalServer
+	}
+
+	log.Debug("email token created",
+		slog.Duration("duration", time.Since(startTime)))
+	return nil
+}
+
+func (r *EmailTokenRepository) LastIssued(ctx context.Context, userID, purpose string) (time.Time, error) {
+	defer metrics.ObserveQuery("email_token.LastIssued")()
+	const op = "postgres.EmailTokenRepository.LastIssued"
+	log := r.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("purpose", purpose),
+	)
+
+	query, args, err := sq.Select("MAX(created_at)").
+		From("email_tokens").
+		Where(sq.Eq{"user_id": userID, "purpose": purpose, "used_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("failed to build SQL query",
+			slog.Any("error", err))
+		return time.Time{}, e.ErrInternalServer
+	}
+
+	var last sql.NullTime
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&last); err != nil {
+		log.Error("failed to get last email token",
+			slog.Any("error", err))
+		return time.Time{}, e.ErrInternalServer
+	}
+	return last.Time, nil
+}
+
+func (r *EmailTokenRepository) VerifyEmail(ctx context.Context, tokenHash string) (string, error) {
+	defer metrics.ObserveQuery("email_token.VerifyEmail")()
+	const op = "postgres.EmailTokenRepository.VerifyEmail"
+	log := r.log.With(slog.String("op", op))
+
+	// подтверждённый раньше email остаётся с прежней датой
+	return r.consume(ctx, log, tokenHash, models.EmailTokenVerifyEmail,
+		sq.Update("users").
+			Set("email_verified_at", sq.Expr("COALESCE(email_verified_at, ?)", time.Now())))
+}
+
+func (r *EmailTokenRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (string, error) {
+	defer metrics.ObserveQuery("email_token.ResetPassword")()
+	const op = "postgres.EmailTokenRepository.ResetPassword"
+	log := r.log.With(slog.String("op", op))
+
+	return r.consume(ctx, log, tokenHash, models.EmailTokenResetPassword,
+		sq.Update("users").
+			Set("password", passwordHash).
+			Set("updated_at", time.Now()))
+}
+
+// consume в одной транзакции гасит токен и применяет к его владельцу update,
+// поэтому токен нельзя использовать дважды, а изменение не теряется
+// при сбое между шагами.
+func (r *EmailTokenRepository) consume(
+	ctx context.Context,
+	log *slog.Logger,
+	tokenHash string,
+	purpose string,
+	update sq.UpdateBuilder,
+) (string, error) {
+	startTime := time.Now()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction",
+			slog.Any("error", err))
+		return "", e.ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	query, args, err := sq.
+		Select("id", "user_id", "expires_at", "used_at").
+		From("email_tokens").
+		Where(sq.Eq{"token_hash": tokenHash, "purpose": purpose}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("failed to build SQL query",
+			slog.Any("error", err))
+		return "", e.ErrInternalServer
+	}
+
+	var token models.EmailToken
+	err = tx.QueryRowContext(ctx, query, args...).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.ExpiresAt,
+		&token.UsedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Warn("email token not found",
+				slog.Duration("duration", time.Since(startTime)))
+			return "", e.ErrInvalidToken
+		}
+		log.Error("failed to get email token",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return "", e.ErrInternalServer
+	}
+
+	log = log.With(slog.String("user_id", token.UserID))
+
+	switch {
+	case token.UsedAt != nil:
+		log.Warn("email token already used",
+			slog.Duration("duration", time.Since(startTime)))
+		return "", e.ErrInvalidToken
+	case !token.ExpiresAt.After(time.Now()):
+		log.Warn("email token expired",
+			slog.Duration("duration", time.Since(startTime)))
+		return "", e.ErrInvalidToken
+	}
+
+	query, args, err = sq.Update("email_tokens").
+		Set("used_at", time.Now()).
+		Where(sq.Eq{"id": token.ID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("failed to build SQL query",
+			slog.Any("error", err))
+		return "", e.ErrInternalServer
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Error("failed to mark email token used",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return "", e.ErrInternalServer
+	}
+
+	query, args, err = update.
+		Where(sq.Eq{"id": token.UserID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("failed to build SQL query",
+			slog.Any("error", err))
+		return "", e.ErrInternalServer
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Error("failed to update user",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return "", e.ErrInternalServer
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit email token",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return "", e.ErrInternalServer
+	}
+
+	log.Info("email token consumed",
+		slog.String("purpose", purpose),
+		slog.Duration("duration", time.Since(startTime)))
+	return token.UserID, nil
+}
@@ -0,0 +1,273 @@
+package postgres
+
+import (
+	"auth_service/internal/config"
+	e "auth_service/internal/domain/errors"
+	"auth_service/internal/domain/models"
+	"auth_service/internal/metrics"
+	"context"
+	"database/sql"
+	"errors"
+	sq "github.com/Masterminds/squirrel"
+	"log/slog"
+	"time"
+)
+
+type RefreshTokenRepository struct {
+	cfg *config.DatabaseConfig
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewRefreshTokenRepository(ctx context.Context, cfg *config.DatabaseConfig, log *slog.Logger) (*RefreshTokenRepository, error) {
+	const op = "postgres.NewRefreshTokenRepository"
+	log = log.With(slog.String("op", op))
+
+	db, err := openDB(ctx, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RefreshTokenRepository{
+		cfg: cfg,
+		db:  db,
+		log: log,
+	}, nil
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	defer metrics.ObserveQuery("refresh_token.Create")()
+	const op = "postgres.RefreshTokenRepository.Create"
+	log := r.log.With(
+		slog.String("op", op),
+		slog.String("user_id", token.UserID),
+		slog.String("family_id", token.FamilyID),
+	)
+
+	startTime := time.Now()
+	if err := insertRefreshToken(ctx, r.db, token); err != nil {
+		log.Error("failed to create refresh token",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInternalServer
+	}
+
+	log.Debug("refresh token created",
+		slog.Duration("duration", time.Since(startTime)))
+	return nil
+}
+
+// Rotate выполняется в транзакции с блокировкой строки старого токена, поэтому
+// из двух параллельных обновлений одним токеном успешным будет только одно,
+// а второе сочтётся повторным использованием.
+func (r *RefreshTokenRepository) Rotate(ctx context.Context, tokenHash string, next *models.RefreshToken) error {
+	defer metrics.ObserveQuery("refresh_token.Rotate")()
+	const op = "postgres.RefreshTokenRepository.Rotate"
+	log := r.log.With(
+		slog.String("op", op),
+		slog.String("user_id", next.UserID),
+	)
+
+	startTime := time.Now()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction",
+			slog.Any("error", err))
+		return e.ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	query, args, err := sq.
+		Select("id", "user_id", "family_id", "expires_at", "used_at", "revoked_at").
+		From("refresh_tokens").
+		Where(sq.Eq{"token_hash": tokenHash}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("failed to build SQL query",
+			slog.Any("error", err))
+		return e.ErrInternalServer
+	}
+
+	var current models.RefreshToken
+	err = tx.QueryRowContext(ctx, query, args...).Scan(
+		&current.ID,
+		&current.UserID,
+		&current.FamilyID,
+		&current.ExpiresAt,
+		&current.UsedAt,
+		&current.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Warn("refresh token not found",
+				slog.Duration("duration", time.Since(startTime)))
+			return e.ErrInvalidToken
+		}
+		log.Error("failed to get refresh token",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInternalServer
+	}
+
+	log = log.With(slog.String("family_id", current.FamilyID))
+
+	switch {
+	case current.UserID != next.UserID, current.FamilyID != next.FamilyID:
+		log.Warn("refresh token does not match its claims",
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInvalidToken
+	case current.RevokedAt != nil:
+		log.Warn("refresh token revoked",
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInvalidToken
+	case current.UsedAt != nil:
+		// токен уже обменян: им пользуется кто-то ещё, поэтому отзывается вся семья,
+		// включая токен, выданный законному владельцу
+		if err := revokeFamily(ctx, tx, current.FamilyID); err != nil {
+			log.Error("failed to revoke token family",
+				slog.Any("error", err),
+				slog.Duration("duration", time.Since(startTime)))
+			return e.ErrInternalServer
+		}
+		if err := tx.Commit(); err != nil {
+			log.Error("failed to commit family revocation",
+				slog.Any("error", err),
+				slog.Duration("duration", time.Since(startTime)))
+			return e.ErrInternalServer
+		}
+		log.Warn("refresh token reuse detected, family revoked",
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrTokenReused
+	case !current.ExpiresAt.After(time.Now()):
+		log.Warn("refresh token expired",
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInvalidToken
+	}
+
+	query, args, err = sq.Update("refresh_tokens").
+		Set("used_at", time.Now()).
+		Where(sq.Eq{"id": current.ID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("failed to build SQL query",
+			slog.Any("error", err))
+		return e.ErrInternalServer
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Error("failed to mark refresh token used",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInternalServer
+	}
+
+	if err := insertRefreshToken(ctx, tx, next); err != nil {
+		log.Error("failed to create rotated refresh token",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInternalServer
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit rotation",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInternalServer
+	}
+
+	log.Debug("refresh token rotated",
+		slog.Duration("duration", time.Since(startTime)))
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	defer metrics.ObserveQuery("refresh_token.RevokeFamily")()
+	const op = "postgres.RefreshTokenRepository.RevokeFamily"
+	log := r.log.With(
+		slog.String("op", op),
+		slog.String("family_id", familyID),
+	)
+
+	startTime := time.Now()
+	if err := revokeFamily(ctx, r.db, familyID); err != nil {
+		log.Error("failed to revoke token family",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInternalServer
+	}
+
+	log.Debug("token family revoked",
+		slog.Duration("duration", time.Since(startTime)))
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeUser(ctx context.Context, userID string) error {
+	defer metrics.ObserveQuery("refresh_token.RevokeUser")()
+	const op = "postgres.RefreshTokenRepository.RevokeUser"
+	log := r.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+	)
+
+	startTime := time.Now()
+
+	query, args, err := sq.Update("refresh_tokens").
+		Set("revoked_at", time.Now()).
+		Where(sq.Eq{"user_id": userID, "revoked_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("failed to build SQL query",
+			slog.Any("error", err))
+		return e.ErrInternalServer
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Error("failed to revoke user tokens",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInternalServer
+	}
+
+	revoked, _ := result.RowsAffected()
+	log.Debug("user tokens revoked",
+		slog.Int64("revoked", revoked),
+		slog.Duration("duration", time.Since(startTime)))
+	return nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func insertRefreshToken(ctx context.Context, db execer, token *models.RefreshToken) error {
+	token.CreatedAt = time.Now()
+
+	query, args, err := sq.Insert("refresh_tokens").
+		Columns("id", "user_id", "family_id", "token_hash", "expires_at", "created_at").
+		Values(token.ID, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt, token.CreatedAt).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func revokeFamily(ctx context.Context, db execer, familyID string) error {
+	query, args, err := sq.Update("refresh_tokens").
+		Set("revoked_at", time.Now()).
+		Where(sq.Eq{"family_id": familyID, "revoked_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, query, args...)
+	return err
+}
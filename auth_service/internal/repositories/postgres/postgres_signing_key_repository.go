@@ -0,0 +1,239 @@
+package postgres
+
+import (
+	"auth_service/internal/config"
+	e "auth_service/internal/domain/errors"
+	"auth_service/internal/domain/models"
+	"auth_service/internal/metrics"
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	sq "github.com/Masterminds/squirrel"
+	"log/slog"
+	"time"
+)
+
+type SigningKeyRepository struct {
+	cfg *config.DatabaseConfig
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewSigningKeyRepository(ctx context.Context, cfg *config.DatabaseConfig, log *slog.Logger) (*SigningKeyRepository, error) {
+	const op = "postgres.NewSigningKeyRepository"
+	log = log.With(slog.String("op", op))
+
+	db, err := openDB(ctx, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SigningKeyRepository{
+		cfg: cfg,
+		db:  db,
+		log: log,
+	}, nil
+}
+
+func (r *SigningKeyRepository) List(ctx context.Context) ([]*models.SigningKey, error) {
+	defer metrics.ObserveQuery("signing_key.List")()
+	const op = "postgres.SigningKeyRepository.List"
+	log := r.log.With(slog.String("op", op))
+
+	startTime := time.Now()
+
+	query, args, err := sq.
+		Select("kid", "algorithm", "private_key", "public_key", "state", "created_at", "rotated_at").
+		From("signing_keys").
+		Where(sq.NotEq{"state": models.KeyStateRetired}).
+		OrderBy("created_at DESC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("failed to build SQL query",
+			slog.Any("error", err))
+		return nil, e.ErrInternalServer
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error("failed to list signing keys",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return nil, e.ErrInternalServer
+	}
+	defer rows.Close()
+
+	var keys []*models.SigningKey
+	for rows.Next() {
+		var (
+			key                   models.SigningKey
+			privateKey, publicKey []byte
+		)
+		if err := rows.Scan(
+			&key.ID,
+			&key.Algorithm,
+			&privateKey,
+			&publicKey,
+			&key.State,
+			&key.CreatedAt,
+			&key.RotatedAt,
+		); err != nil {
+			log.Error("failed to scan signing key",
+				slog.Any("error", err))
+			return nil, e.ErrInternalServer
+		}
+		if len(privateKey) == ed25519.PrivateKeySize {
+			key.PrivateKey = privateKey
+		}
+		key.PublicKey = publicKey
+		keys = append(keys, &key)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("failed to iterate signing keys",
+			slog.Any("error", err))
+		return nil, e.ErrInternalServer
+	}
+
+	log.Debug("signing keys listed",
+		slog.Int("count", len(keys)),
+		slog.Duration("duration", time.Since(startTime)))
+	return keys, nil
+}
+
+func (r *SigningKeyRepository) Create(ctx context.Context, key *models.SigningKey) error {
+	defer metrics.ObserveQuery("signing_key.Create")()
+	const op = "postgres.SigningKeyRepository.Create"
+	log := r.log.With(
+		slog.String("op", op),
+		slog.String("kid", key.ID),
+	)
+
+	startTime := time.Now()
+	if err := insertSigningKey(ctx, r.db, key); err != nil {
+		if isDuplicateKeyError(err) {
+			log.Info("active signing key already exists")
+			return e.ErrKeyExists
+		}
+		log.Error("failed to create signing key",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInternalServer
+	}
+
+	log.Info("signing key created",
+		slog.Duration("duration", time.Since(startTime)))
+	return nil
+}
+
+func (r *SigningKeyRepository) Rotate(ctx context.Context, next *models.SigningKey) error {
+	defer metrics.ObserveQuery("signing_key.Rotate")()
+	const op = "postgres.SigningKeyRepository.Rotate"
+	log := r.log.With(
+		slog.String("op", op),
+		slog.String("kid", next.ID),
+	)
+
+	startTime := time.Now()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction",
+			slog.Any("error", err))
+		return e.ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	query, args, err := sq.Update("signing_keys").
+		Set("state", models.KeyStateRetiring).
+		Set("rotated_at", time.Now()).
+		Where(sq.Eq{"state": models.KeyStateActive}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("failed to build SQL query",
+			slog.Any("error", err))
+		return e.ErrInternalServer
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Error("failed to retire active signing key",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInternalServer
+	}
+
+	if err := insertSigningKey(ctx, tx, next); err != nil {
+		if isDuplicateKeyError(err) {
+			log.Warn("concurrent rotation detected")
+			return e.ErrKeyExists
+		}
+		log.Error("failed to create signing key",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInternalServer
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit rotation",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInternalServer
+	}
+
+	log.Info("signing key rotated",
+		slog.Duration("duration", time.Since(startTime)))
+	return nil
+}
+
+// Retire стирает закрытую часть ключа: подписывать ею больше нечего,
+// а открытую оставляем для разбора старых инцидентов.
+func (r *SigningKeyRepository) Retire(ctx context.Context, before time.Time) (int64, error) {
+	defer metrics.ObserveQuery("signing_key.Retire")()
+	const op = "postgres.SigningKeyRepository.Retire"
+	log := r.log.With(slog.String("op", op))
+
+	startTime := time.Now()
+
+	query, args, err := sq.Update("signing_keys").
+		Set("state", models.KeyStateRetired).
+		Set("retired_at", time.Now()).
+		Set("private_key", nil).
+		Where(sq.Eq{"state": models.KeyStateRetiring}).
+		Where(sq.Lt{"rotated_at": before}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("failed to build SQL query",
+			slog.Any("error", err))
+		return 0, e.ErrInternalServer
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Error("failed to retire signing keys",
+			slog.Any("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return 0, e.ErrInternalServer
+	}
+
+	retired, _ := result.RowsAffected()
+	log.Info("signing keys retired",
+		slog.Int64("retired", retired),
+		slog.Duration("duration", time.Since(startTime)))
+	return retired, nil
+}
+
+func insertSigningKey(ctx context.Context, db execer, key *models.SigningKey) error {
+	key.CreatedAt = time.Now()
+
+	query, args, err := sq.Insert("signing_keys").
+		Columns("kid", "algorithm", "private_key", "public_key", "state", "created_at").
+		Values(key.ID, key.Algorithm, []byte(key.PrivateKey), []byte(key.PublicKey), key.State, key.CreatedAt).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, query, args...)
+	return err
+}
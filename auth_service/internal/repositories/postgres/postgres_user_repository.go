@@ -4,13 +4,16 @@ import (
 	"auth_service/internal/config"
 	e "auth_service/internal/domain/errors"
 	"auth_service/internal/domain/models"
+	"auth_service/internal/metrics"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	sq "github.com/Masterminds/squirrel"
+	"github.com/XSAM/otelsql"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"log/slog"
 	"strings"
 	"time"
@@ -28,6 +31,19 @@ func NewUserRepository(ctx context.Context, cfg *config.DatabaseConfig, log *slo
 	const op = "postgres.NewUserRepository"
 	log = log.With(slog.String("op", op))
 
+	db, err := openDB(ctx, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserRepository{
+		cfg: cfg,
+		db:  db,
+		log: log,
+	}, nil
+}
+
+func openDB(ctx context.Context, cfg *config.DatabaseConfig, log *slog.Logger) (*sql.DB, error) {
 	log.Info("connecting to database",
 		slog.String("driver", driverName),
 		slog.String("dsn_mask", maskDSN(cfg.DSN)),
@@ -39,7 +55,9 @@ func NewUserRepository(ctx context.Context, cfg *config.DatabaseConfig, log *slo
 			slog.Duration("duration", time.Since(startTime)))
 	}()
 
-	db, err := sql.Open(driverName, cfg.DSN)
+	db, err := otelsql.Open(driverName, cfg.DSN,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true}))
 	if err != nil {
 		log.Error("failed to open database connection",
 			slog.String("error", err.Error()))
@@ -56,14 +74,15 @@ func NewUserRepository(ctx context.Context, cfg *config.DatabaseConfig, log *slo
 	}
 
 	log.Info("database connection established successfully")
-	return &UserRepository{
-		cfg: cfg,
-		db:  db,
-		log: log,
-	}, nil
+	return db, nil
+}
+
+func (r *UserRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
 }
 
 func (r *UserRepository) Create(ctx context.Context, user *models.User) (string, error) {
+	defer metrics.ObserveQuery("user.Create")()
 	const op = "postgres.UserRepository.Create"
 	log := r.log.With(
 		slog.String("op", op),
@@ -124,6 +143,7 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) (string,
 }
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	defer metrics.ObserveQuery("user.GetByEmail")()
 	const op = "postgres.UserRepository.GetByEmail"
 	log := r.log.With(
 		slog.String("op", op),
@@ -133,7 +153,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 	startTime := time.Now()
 
 	query, args, err := sq.
-		Select("id", "email", "password", "name", "created_at", "updated_at").
+		Select("id", "email", "password", "name", "email_verified_at", "created_at", "updated_at").
 		From("users").
 		Where(sq.Eq{"email": email}).
 		PlaceholderFormat(sq.Dollar).
@@ -156,6 +176,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 		&user.Email,
 		&user.Password,
 		&user.Name,
+		&user.EmailVerifiedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -180,6 +201,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 }
 
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	defer metrics.ObserveQuery("user.GetByID")()
 	const op = "postgres.UserRepository.GetByID"
 	log := r.log.With(
 		slog.String("op", op),
@@ -190,7 +212,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User,
 	startTime := time.Now()
 
 	query, args, err := sq.
-		Select("id", "email", "password", "name", "created_at", "updated_at").
+		Select("id", "email", "password", "name", "email_verified_at", "created_at", "updated_at").
 		From("users").
 		Where(sq.Eq{"id": id}).
 		PlaceholderFormat(sq.Dollar).
@@ -214,6 +236,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User,
 		&user.Email,
 		&user.Password,
 		&user.Name,
+		&user.EmailVerifiedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"auth_service/internal/domain/models"
+	"context"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	// Rotate помечает токен с хешем tokenHash использованным и сохраняет next,
+	// который должен принадлежать той же семье. Повторное предъявление
+	// использованного токена отзывает всю семью.
+	Rotate(ctx context.Context, tokenHash string, next *models.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeUser(ctx context.Context, userID string) error
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"auth_service/internal/domain/models"
+	"context"
+	"time"
+)
+
+type SigningKeyRepository interface {
+	// List возвращает ключи, ещё не выведенные из оборота.
+	List(ctx context.Context) ([]*models.SigningKey, error)
+	// Create сохраняет первый активный ключ; ErrKeyExists, если активный уже есть.
+	Create(ctx context.Context, key *models.SigningKey) error
+	// Rotate переводит активный ключ в retiring и делает активным next.
+	Rotate(ctx context.Context, next *models.SigningKey) error
+	// Retire выводит из оборота ключи, ушедшие в retiring раньше before.
+	Retire(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,108 @@
+package redis_revocation
+
+import (
+	"auth_service/internal/config"
+	"auth_service/internal/revocation"
+	"auth_service/logger"
+	"context"
+	"errors"
+	"github.com/deeelis/platform/denylist"
+	"github.com/deeelis/platform/tracing/redistrace"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const readBlock = 5 * time.Second
+
+// RedisStore хранит отзывы в формате пакета denylist, который читает и шлюз.
+type RedisStore struct {
+	client    *redis.Client
+	stream    string
+	retention time.Duration
+	log       *slog.Logger
+}
+
+// NewRedisStore: поток обрезается по сроку жизни access-токена — более старые
+// записи отзывают токены, которые уже истекли, поэтому читатели могут начинать с начала потока.
+func NewRedisStore(cfg *config.Config, log *slog.Logger) (*RedisStore, error) {
+	const op = "redis_revocation.NewRedisStore"
+	log = log.With(slog.String("op", op), slog.String("stream", cfg.Revocation.Stream))
+
+	opts, err := redis.ParseURL(cfg.Redis.URL)
+	if err != nil {
+		log.Error("failed to parse redis url", logger.Err(err))
+		return nil, err
+	}
+
+	client := redis.NewClient(opts)
+	client.AddHook(redistrace.Hook{})
+	return &RedisStore{
+		client:    client,
+		stream:    cfg.Revocation.Stream,
+		retention: cfg.Token.TokenTTL,
+		log:       log,
+	}, nil
+}
+
+func (s *RedisStore) Revoke(ctx context.Context, rev *denylist.Revocation) error {
+	now := time.Now()
+	ttl := rev.Until.Sub(now)
+	if ttl <= 0 {
+		return nil
+	}
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if rev.TokenID != "" {
+			pipe.Set(ctx, denylist.TokenKeyPrefix+rev.TokenID, "1", ttl)
+		}
+		if rev.UserID != "" && !rev.Before.IsZero() {
+			pipe.Set(ctx, denylist.UserKeyPrefix+rev.UserID, rev.Before.Unix(), ttl)
+		}
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: s.stream,
+			MinID:  strconv.FormatInt(now.Add(-s.retention).UnixMilli(), 10),
+			Approx: true,
+			Values: rev.Values(),
+		})
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) Read(ctx context.Context, after string) ([]*denylist.Revocation, string, error) {
+	streams, err := s.client.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{s.stream, after},
+		Count:   revocation.ReadBatch,
+		Block:   readBlock,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, after, nil
+		}
+		return nil, after, err
+	}
+
+	var revs []*denylist.Revocation
+	for _, stream := range streams {
+		for _, msg := range stream.Messages {
+			after = msg.ID
+			revs = append(revs, denylist.Parse(msg.Values))
+		}
+	}
+	return revs, after, nil
+}
+
+func (s *RedisStore) IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error) {
+	return denylist.IsRevoked(ctx, s.client, tokenID, userID, issuedAt)
+}
+
+func (s *RedisStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,166 @@
+// Package revocation ведёт список отозванных access-токенов. Источник истины —
+// Redis, а проверка токена идёт по копии списка в памяти, которую обновляет
+// лента отзывов, поэтому ValidateToken не ходит в Redis на каждый запрос.
+package revocation
+
+import (
+	"auth_service/logger"
+	"context"
+	"github.com/deeelis/platform/denylist"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// ReadBatch — сколько записей ленты Store.Read отдаёт за раз
+	ReadBatch = 500
+
+	retryInterval = time.Second
+	purgeInterval = time.Minute
+)
+
+type Store interface {
+	// Revoke сохраняет отзыв до Until и публикует его в ленту.
+	Revoke(ctx context.Context, rev *denylist.Revocation) error
+	// Read ждёт записи ленты после after и возвращает не больше ReadBatch
+	// из них вместе с id последней.
+	Read(ctx context.Context, after string) ([]*denylist.Revocation, string, error)
+	// IsRevoked проверяет отзыв напрямую в хранилище.
+	IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error)
+}
+
+type userRevocation struct {
+	before time.Time
+	until  time.Time
+}
+
+// Denylist — кеш отзывов перед Store. Пока лента не дочитана до конца
+// хотя бы раз, кеш неполон, и проверки уходят в Store. Конец ленты —
+// чтение, вернувшее меньше ReadBatch записей.
+type Denylist struct {
+	store  Store
+	log    *slog.Logger
+	synced atomic.Bool
+
+	mu     sync.RWMutex
+	tokens map[string]time.Time
+	users  map[string]userRevocation
+}
+
+func NewDenylist(store Store, log *slog.Logger) *Denylist {
+	return &Denylist{
+		store:  store,
+		log:    log.With(slog.String("op", "revocation.Denylist")),
+		tokens: make(map[string]time.Time),
+		users:  make(map[string]userRevocation),
+	}
+}
+
+// Run читает ленту с начала, пока не отменён ctx. Запись, опубликованная
+// этой же репликой, придёт повторно; добавление идемпотентно.
+func (d *Denylist) Run(ctx context.Context) {
+	lastID := "0"
+	lastPurge := time.Now()
+	loaded := 0
+
+	for ctx.Err() == nil {
+		revs, last, err := d.store.Read(ctx, lastID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			d.log.Warn("failed to read revocation feed", logger.Err(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+			continue
+		}
+
+		lastID = last
+		for _, rev := range revs {
+			d.add(rev)
+		}
+		if !d.synced.Load() {
+			loaded += len(revs)
+			if len(revs) < ReadBatch {
+				d.synced.Store(true)
+				d.log.Info("revocation feed synced", slog.Int("entries", loaded))
+			}
+		}
+
+		if now := time.Now(); now.Sub(lastPurge) >= purgeInterval {
+			d.purge(now)
+			lastPurge = now
+		}
+	}
+}
+
+// Revoke сохраняет отзыв и сразу применяет его локально, не дожидаясь ленты.
+func (d *Denylist) Revoke(ctx context.Context, rev *denylist.Revocation) error {
+	if err := d.store.Revoke(ctx, rev); err != nil {
+		return err
+	}
+	d.add(rev)
+	return nil
+}
+
+func (d *Denylist) Revoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error) {
+	if !d.synced.Load() {
+		return d.store.IsRevoked(ctx, tokenID, userID, issuedAt)
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if tokenID != "" {
+		if _, ok := d.tokens[tokenID]; ok {
+			return true, nil
+		}
+	}
+	if user, ok := d.users[userID]; ok && issuedAt.Before(user.before) {
+		return true, nil
+	}
+	return false, nil
+}
+
+func (d *Denylist) add(rev *denylist.Revocation) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if rev.TokenID != "" {
+		if rev.Until.After(d.tokens[rev.TokenID]) {
+			d.tokens[rev.TokenID] = rev.Until
+		}
+	}
+	if rev.UserID != "" && !rev.Before.IsZero() {
+		current := d.users[rev.UserID]
+		if rev.Before.After(current.before) {
+			current.before = rev.Before
+		}
+		if rev.Until.After(current.until) {
+			current.until = rev.Until
+		}
+		d.users[rev.UserID] = current
+	}
+}
+
+// purge забывает отзывы, чьи токены уже истекли.
+func (d *Denylist) purge(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for id, until := range d.tokens {
+		if until.Before(now) {
+			delete(d.tokens, id)
+		}
+	}
+	for id, user := range d.users {
+		if user.until.Before(now) {
+			delete(d.users, id)
+		}
+	}
+}
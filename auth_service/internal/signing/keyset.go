@@ -0,0 +1,214 @@
+// Package signing хранит ключи подписи токенов в памяти сервиса. Источник
+// ключей — Postgres: ротация выполняется командой cmd/keys, а реплики
+// подхватывают новый набор при периодической перезагрузке или раньше,
+// встретив токен с незнакомым kid.
+package signing
+
+import (
+	e "auth_service/internal/domain/errors"
+	"auth_service/internal/domain/models"
+	"auth_service/internal/repositories"
+	"auth_service/logger"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// reloadInterval — не чаще этого токен с незнакомым kid вызывает
+// внеочередную загрузку ключей: иначе поток мусорных токенов превратился бы
+// в поток запросов к Postgres.
+const reloadInterval = 10 * time.Second
+
+type KeySet struct {
+	repo repositories.SigningKeyRepository
+	log  *slog.Logger
+
+	mu         sync.RWMutex
+	active     *models.SigningKey
+	keys       map[string]*models.SigningKey
+	lastReload time.Time
+}
+
+func NewKeySet(repo repositories.SigningKeyRepository, log *slog.Logger) *KeySet {
+	return &KeySet{
+		repo: repo,
+		log:  log.With(slog.String("op", "signing.KeySet")),
+		keys: make(map[string]*models.SigningKey),
+	}
+}
+
+// Load перечитывает ключи. Если активного ключа ещё нет, создаёт его:
+// при одновременном старте реплик ключ создаст одна, остальные его прочитают.
+func (k *KeySet) Load(ctx context.Context) error {
+	keys, err := k.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	if activeKey(keys) == nil {
+		key, err := NewKey()
+		if err != nil {
+			return err
+		}
+		if err := k.repo.Create(ctx, key); err != nil && !errors.Is(err, e.ErrKeyExists) {
+			return err
+		}
+		if keys, err = k.repo.List(ctx); err != nil {
+			return err
+		}
+	}
+
+	active := activeKey(keys)
+	if active == nil {
+		return e.ErrNoSigningKey
+	}
+
+	byID := make(map[string]*models.SigningKey, len(keys))
+	for _, key := range keys {
+		byID[key.ID] = key
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.active == nil || k.active.ID != active.ID {
+		k.log.Info("active signing key changed",
+			slog.String("kid", active.ID),
+			slog.Int("keys", len(keys)))
+	}
+	k.active = active
+	k.keys = byID
+	return nil
+}
+
+// Run перезагружает ключи раз в interval, пока не отменён ctx. При ошибке
+// остаётся прежний набор.
+func (k *KeySet) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.Load(ctx); err != nil {
+				k.log.Warn("failed to reload signing keys", logger.Err(err))
+			}
+		}
+	}
+}
+
+func (k *KeySet) Active() (*models.SigningKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if k.active == nil || len(k.active.PrivateKey) == 0 {
+		return nil, e.ErrNoSigningKey
+	}
+	return k.active, nil
+}
+
+// PublicKey возвращает ключ проверки активного или выводимого ключа.
+// Незнакомый kid означает, что другая реплика могла повернуть ключ после
+// последней загрузки, поэтому набор загружается заново.
+func (k *KeySet) PublicKey(kid string) (ed25519.PublicKey, bool) {
+	if key, ok := k.publicKey(kid); ok {
+		return key, true
+	}
+	if k.reload() {
+		return k.publicKey(kid)
+	}
+	return nil, false
+}
+
+func (k *KeySet) publicKey(kid string) (ed25519.PublicKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, false
+	}
+	return key.PublicKey, true
+}
+
+// reload — внеочередная загрузка ключей. Время попытки отмечается до неё,
+// чтобы одновременные запросы с незнакомым kid не грузили набор каждый сам.
+func (k *KeySet) reload() bool {
+	k.mu.Lock()
+	if time.Since(k.lastReload) < reloadInterval {
+		k.mu.Unlock()
+		return false
+	}
+	k.lastReload = time.Now()
+	k.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := k.Load(ctx); err != nil {
+		k.log.Warn("failed to reload signing keys", logger.Err(err))
+		return false
+	}
+	return true
+}
+
+// PublicKeys — ключи для JWKS: активный и выводимые из оборота.
+func (k *KeySet) PublicKeys() []*models.SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	keys := make([]*models.SigningKey, 0, len(k.keys))
+	for _, key := range k.keys {
+		keys = append(keys, &models.SigningKey{
+			ID:        key.ID,
+			Algorithm: key.Algorithm,
+			PublicKey: key.PublicKey,
+			State:     key.State,
+			CreatedAt: key.CreatedAt,
+		})
+	}
+	return keys
+}
+
+// NewKey создаёт активный ключ Ed25519. kid — отпечаток JWK по RFC 7638,
+// поэтому он однозначно определяется открытым ключом.
+func NewKey() (*models.SigningKey, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SigningKey{
+		ID:         Thumbprint(publicKey),
+		Algorithm:  models.KeyAlgorithmEdDSA,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+		State:      models.KeyStateActive,
+	}, nil
+}
+
+// EncodeKey — открытый ключ в виде параметра x JWK.
+func EncodeKey(publicKey ed25519.PublicKey) string {
+	return base64.RawURLEncoding.EncodeToString(publicKey)
+}
+
+func Thumbprint(publicKey ed25519.PublicKey) string {
+	// члены JWK в лексикографическом порядке и без пробелов, как требует RFC 7638
+	sum := sha256.Sum256([]byte(`{"crv":"Ed25519","kty":"OKP","x":"` + EncodeKey(publicKey) + `"}`))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func activeKey(keys []*models.SigningKey) *models.SigningKey {
+	for _, key := range keys {
+		if key.State == models.KeyStateActive {
+			return key
+		}
+	}
+	return nil
+}
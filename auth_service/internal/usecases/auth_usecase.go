@@ -7,7 +7,14 @@ import (
 
 type AuthUsecase interface {
 	Register(ctx context.Context, user *models.User) (string, error)
-	Login(ctx context.Context, email, password string) (*models.TokenDetails, error)
-	ValidateToken(token string) (string, error)
-	RefreshToken(refreshToken string) (*models.TokenDetails, error)
+	Login(ctx context.Context, email, password, ip string) (*models.TokenDetails, error)
+	ValidateToken(ctx context.Context, token string) (string, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*models.TokenDetails, error)
+	Logout(ctx context.Context, accessToken string) error
+	LogoutAll(ctx context.Context, accessToken string) error
+	PublicKeys() []*models.SigningKey
+	UnlockAccount(ctx context.Context, email, ip string) error
+	VerifyEmail(ctx context.Context, token string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ConfirmPasswordReset(ctx context.Context, token, password string) error
 }
@@ -4,21 +4,42 @@ import (
 	"auth_service/internal/config"
 	e "auth_service/internal/domain/errors"
 	"auth_service/internal/domain/models"
+	"auth_service/internal/lockout"
+	"auth_service/internal/lockout/redis_lockout"
+	"auth_service/internal/mailer"
 	"auth_service/internal/repositories"
 	"auth_service/internal/repositories/postgres"
+	"auth_service/internal/revocation"
+	"auth_service/internal/revocation/redis_revocation"
+	"auth_service/internal/signing"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"github.com/deeelis/platform/denylist"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"log/slog"
 	"time"
 )
 
+// AuthUsecase подписывает токены ключами из keys. secretKey — ключ HS256
+// прежних токенов на время перехода на EdDSA; если он пуст, такие токены не принимаются.
+// dummyHash сравнивается с паролем, когда пользователя нет, чтобы такой отказ
+// занимал столько же времени, сколько неверный пароль.
 type AuthUsecase struct {
 	cfg           *config.Config
 	log           *slog.Logger
 	userRepo      repositories.UserRepository
+	refreshRepo   repositories.RefreshTokenRepository
+	emailRepo     repositories.EmailTokenRepository
+	mailer        mailer.Mailer
+	denylist      *revocation.Denylist
+	keys          *signing.KeySet
+	lockout       *lockout.Guard
+	dummyHash     []byte
 	secretKey     string
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
@@ -54,11 +75,82 @@ func NewAuthUsecase(
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	refreshRepo, err := postgres.NewRefreshTokenRepository(ctx, &cfg.Database, log)
+	if err != nil {
+		log.Error("failed to create refresh token repository",
+			slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	revocationStore, err := redis_revocation.NewRedisStore(cfg, log)
+	if err != nil {
+		log.Error("failed to create revocation store",
+			slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	denylist := revocation.NewDenylist(revocationStore, log)
+	go denylist.Run(context.Background())
+
+	keyRepo, err := postgres.NewSigningKeyRepository(ctx, &cfg.Database, log)
+	if err != nil {
+		log.Error("failed to create signing key repository",
+			slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	keys := signing.NewKeySet(keyRepo, log)
+	if err := keys.Load(ctx); err != nil {
+		log.Error("failed to load signing keys",
+			slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	go keys.Run(context.Background(), cfg.Keys.RefreshInterval)
+
+	emailRepo, err := postgres.NewEmailTokenRepository(ctx, &cfg.Database, log)
+	if err != nil {
+		log.Error("failed to create email token repository",
+			slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch cfg.Email.UnverifiedPolicy {
+	case UnverifiedAllow, UnverifiedGrace, UnverifiedDeny:
+	default:
+		return nil, fmt.Errorf("%s: unknown unverified account policy %q", op, cfg.Email.UnverifiedPolicy)
+	}
+
+	mail, err := mailer.New(&cfg.Mailer, log)
+	if err != nil {
+		log.Error("failed to create mailer",
+			slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	lockoutStore, err := redis_lockout.NewRedisStore(&cfg.Redis, log)
+	if err != nil {
+		log.Error("failed to create lockout store",
+			slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	dummyHash, err := bcrypt.GenerateFromPassword([]byte(uuid.New().String()), bcrypt.DefaultCost)
+	if err != nil {
+		log.Error("failed to generate dummy password hash",
+			slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	log.Info("auth usecase initialized successfully")
 	return &AuthUsecase{
 		cfg:           cfg,
 		log:           log,
 		userRepo:      userRepo,
+		refreshRepo:   refreshRepo,
+		emailRepo:     emailRepo,
+		mailer:        mail,
+		denylist:      denylist,
+		keys:          keys,
+		lockout:       lockout.NewGuard(lockoutStore, cfg.Lockout, log),
+		dummyHash:     dummyHash,
 		secretKey:     secretKey,
 		accessExpiry:  accessExpiry,
 		refreshExpiry: refreshExpiry,
@@ -105,13 +197,15 @@ func (uc *AuthUsecase) Register(ctx context.Context, user *models.User) (string,
 		return "", err
 	}
 
+	uc.sendEmailToken(userID, user.Email, models.EmailTokenVerifyEmail)
+
 	log.Info("user registered successfully",
 		slog.String("user_id", userID),
 		slog.Duration("duration", time.Since(startTime)))
 	return userID, nil
 }
 
-func (uc *AuthUsecase) Login(ctx context.Context, email, password string) (*models.TokenDetails, error) {
+func (uc *AuthUsecase) Login(ctx context.Context, email, password, ip string) (*models.TokenDetails, error) {
 	const op = "auth_usecase.Login"
 	log := uc.log.With(
 		slog.String("op", op),
@@ -120,26 +214,48 @@ func (uc *AuthUsecase) Login(ctx context.Context, email, password string) (*mode
 	log.Info("authenticating user")
 	startTime := time.Now()
 
-	user, err := uc.userRepo.GetByEmail(ctx, email)
-	if err != nil {
-		if errors.Is(err, e.ErrUserNotFound) {
-			log.Warn("user not found",
-				slog.Duration("duration", time.Since(startTime)))
-		} else {
-			log.Error("failed to retrieve user",
-				slog.String("error", err.Error()),
+	if err := uc.lockout.Admit(ctx, email, ip); err != nil {
+		if errors.Is(err, e.ErrTooManyAttempts) {
+			log.Warn("login locked",
 				slog.Duration("duration", time.Since(startTime)))
+			return nil, err
 		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := uc.userRepo.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, e.ErrUserNotFound) {
+		log.Error("failed to retrieve user",
+			slog.String("error", err.Error()),
+			slog.Duration("duration", time.Since(startTime)))
 		return nil, err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		log.Warn("invalid password provided",
+	// неизвестный email и неверный пароль неотличимы ни по ответу, ни по времени,
+	// ни по логу
+	passwordHash := uc.dummyHash
+	if user != nil {
+		passwordHash = []byte(user.Password)
+	}
+	if err := bcrypt.CompareHashAndPassword(passwordHash, []byte(password)); err != nil || user == nil {
+		uc.lockout.Fail(ctx, email, ip)
+		log.Warn("invalid credentials",
 			slog.Duration("duration", time.Since(startTime)))
 		return nil, e.ErrInvalidCredentials
 	}
+	uc.lockout.Succeed(ctx, email)
+
+	if !uc.mayLogin(user) {
+		uc.resendVerification(ctx, user)
+		log.Warn("email not verified",
+			slog.String("user_id", user.ID),
+			slog.Duration("duration", time.Since(startTime)))
+		return nil, e.ErrEmailNotVerified
+	}
 
-	tokens, err := uc.generateTokens(user.ID)
+	// каждый вход начинает новую семью refresh-токенов, она же id сессии
+	sessionID := uuid.New().String()
+	tokens, err := uc.generateTokens(user.ID, sessionID)
 	if err != nil {
 		log.Error("failed to generate tokens",
 			slog.String("error", err.Error()),
@@ -147,6 +263,19 @@ func (uc *AuthUsecase) Login(ctx context.Context, email, password string) (*mode
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	if err := uc.refreshRepo.Create(ctx, &models.RefreshToken{
+		ID:        tokens.RefreshUUID,
+		UserID:    user.ID,
+		FamilyID:  sessionID,
+		TokenHash: hashToken(tokens.RefreshToken),
+		ExpiresAt: time.Unix(tokens.RtExpires, 0),
+	}); err != nil {
+		log.Error("failed to store refresh token",
+			slog.String("error", err.Error()),
+			slog.Duration("duration", time.Since(startTime)))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	log.Info("user authenticated successfully",
 		slog.String("user_id", user.ID),
 		slog.Duration("duration", time.Since(startTime)))
@@ -154,7 +283,7 @@ func (uc *AuthUsecase) Login(ctx context.Context, email, password string) (*mode
 	return tokens, nil
 }
 
-func (uc *AuthUsecase) ValidateToken(tokenString string) (string, error) {
+func (uc *AuthUsecase) ValidateToken(ctx context.Context, tokenString string) (string, error) {
 	const op = "auth_usecase.ValidateToken"
 	log := uc.log.With(
 		slog.String("op", op),
@@ -164,50 +293,36 @@ func (uc *AuthUsecase) ValidateToken(tokenString string) (string, error) {
 	log.Debug("validating token")
 	startTime := time.Now()
 
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			log.Warn("unexpected token signing method",
-				slog.String("alg", token.Header["alg"].(string)))
-			return nil, e.ErrInvalidToken
-		}
-		return []byte(uc.secretKey), nil
-	})
-
+	claims, err := uc.parseToken(tokenString, models.TokenTypeAccess)
 	if err != nil {
-		var jwtErr *jwt.ValidationError
-		if errors.As(err, &jwtErr) {
-			log.Warn("token validation failed",
-				slog.String("error", jwtErr.Error()),
-				slog.Duration("duration", time.Since(startTime)))
-		} else {
-			log.Error("failed to parse token",
-				slog.String("error", err.Error()),
-				slog.Duration("duration", time.Since(startTime)))
-		}
+		log.Warn("token validation failed",
+			slog.String("error", err.Error()),
+			slog.Duration("duration", time.Since(startTime)))
 		return "", e.ErrInvalidToken
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userID, ok := claims["user_id"].(string)
-		if !ok {
-			log.Warn("missing user_id in token claims",
-				slog.Duration("duration", time.Since(startTime)))
-			return "", e.ErrInvalidToken
-		}
-
-		log.Debug("token validated successfully",
-			slog.String("user_id", userID),
+	revoked, err := uc.denylist.Revoked(ctx, claims.ID, claims.UserID, claims.IssuedAt.Time)
+	if err != nil {
+		log.Error("failed to check token revocation",
+			slog.String("error", err.Error()),
 			slog.Duration("duration", time.Since(startTime)))
-
-		return userID, nil
+		return "", e.ErrInternalServer
+	}
+	if revoked {
+		log.Warn("revoked token provided",
+			slog.String("user_id", claims.UserID),
+			slog.Duration("duration", time.Since(startTime)))
+		return "", e.ErrInvalidToken
 	}
 
-	log.Warn("invalid token claims",
+	log.Debug("token validated successfully",
+		slog.String("user_id", claims.UserID),
 		slog.Duration("duration", time.Since(startTime)))
-	return "", e.ErrInvalidToken
+
+	return claims.UserID, nil
 }
 
-func (uc *AuthUsecase) RefreshToken(refreshToken string) (*models.TokenDetails, error) {
+func (uc *AuthUsecase) RefreshToken(ctx context.Context, refreshToken string) (*models.TokenDetails, error) {
 	const op = "auth_usecase.RefreshToken"
 	log := uc.log.With(
 		slog.String("op", op),
@@ -217,15 +332,16 @@ func (uc *AuthUsecase) RefreshToken(refreshToken string) (*models.TokenDetails,
 	log.Info("refreshing token")
 	startTime := time.Now()
 
-	userID, err := uc.ValidateToken(refreshToken)
+	claims, err := uc.parseToken(refreshToken, models.TokenTypeRefresh)
 	if err != nil {
 		log.Warn("invalid refresh token provided",
 			slog.String("error", err.Error()),
 			slog.Duration("duration", time.Since(startTime)))
-		return nil, err
+		return nil, e.ErrInvalidToken
 	}
+	userID := claims.UserID
 
-	tokens, err := uc.generateTokens(userID)
+	tokens, err := uc.generateTokens(userID, claims.SessionID)
 	if err != nil {
 		log.Error("failed to generate new tokens",
 			slog.String("error", err.Error()),
@@ -233,6 +349,22 @@ func (uc *AuthUsecase) RefreshToken(refreshToken string) (*models.TokenDetails,
 		return nil, err
 	}
 
+	// старый токен обменивается на новый ровно один раз
+	err = uc.refreshRepo.Rotate(ctx, hashToken(refreshToken), &models.RefreshToken{
+		ID:        tokens.RefreshUUID,
+		UserID:    userID,
+		FamilyID:  claims.SessionID,
+		TokenHash: hashToken(tokens.RefreshToken),
+		ExpiresAt: time.Unix(tokens.RtExpires, 0),
+	})
+	if err != nil {
+		log.Warn("refresh token rotation failed",
+			slog.String("user_id", userID),
+			slog.String("error", err.Error()),
+			slog.Duration("duration", time.Since(startTime)))
+		return nil, err
+	}
+
 	log.Info("tokens refreshed successfully",
 		slog.String("user_id", userID),
 		slog.Duration("duration", time.Since(startTime)))
@@ -240,7 +372,104 @@ func (uc *AuthUsecase) RefreshToken(refreshToken string) (*models.TokenDetails,
 	return tokens, nil
 }
 
-func (uc *AuthUsecase) generateTokens(userID string) (*models.TokenDetails, error) {
+// Logout отзывает семью refresh-токенов сессии и сам access-токен до его истечения.
+func (uc *AuthUsecase) Logout(ctx context.Context, accessToken string) error {
+	const op = "auth_usecase.Logout"
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.Int("token_length", len(accessToken)),
+	)
+
+	log.Info("logging out session")
+	startTime := time.Now()
+
+	claims, err := uc.parseToken(accessToken, models.TokenTypeAccess)
+	if err != nil {
+		log.Warn("invalid access token provided",
+			slog.String("error", err.Error()),
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInvalidToken
+	}
+	log = log.With(slog.String("user_id", claims.UserID))
+
+	if claims.SessionID != "" {
+		if err := uc.refreshRepo.RevokeFamily(ctx, claims.SessionID); err != nil {
+			log.Error("failed to revoke session refresh tokens",
+				slog.String("error", err.Error()),
+				slog.Duration("duration", time.Since(startTime)))
+			return err
+		}
+	}
+
+	if err := uc.denylist.Revoke(ctx, &denylist.Revocation{
+		TokenID: claims.ID,
+		UserID:  claims.UserID,
+		Until:   claims.ExpiresAt.Time,
+	}); err != nil {
+		log.Error("failed to revoke access token",
+			slog.String("error", err.Error()),
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInternalServer
+	}
+
+	log.Info("session logged out",
+		slog.Duration("duration", time.Since(startTime)))
+	return nil
+}
+
+// LogoutAll отзывает все refresh-токены пользователя и все его access-токены,
+// выпущенные до этого момента: jti остальных сессий неизвестны, поэтому
+// отзыв идёт по времени выпуска.
+func (uc *AuthUsecase) LogoutAll(ctx context.Context, accessToken string) error {
+	const op = "auth_usecase.LogoutAll"
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.Int("token_length", len(accessToken)),
+	)
+
+	log.Info("logging out all sessions")
+	startTime := time.Now()
+
+	claims, err := uc.parseToken(accessToken, models.TokenTypeAccess)
+	if err != nil {
+		log.Warn("invalid access token provided",
+			slog.String("error", err.Error()),
+			slog.Duration("duration", time.Since(startTime)))
+		return e.ErrInvalidToken
+	}
+	log = log.With(slog.String("user_id", claims.UserID))
+
+	if err := uc.revokeSessions(ctx, claims.UserID); err != nil {
+		log.Error("failed to revoke sessions",
+			slog.String("error", err.Error()),
+			slog.Duration("duration", time.Since(startTime)))
+		return err
+	}
+
+	log.Info("all sessions logged out",
+		slog.Duration("duration", time.Since(startTime)))
+	return nil
+}
+
+// revokeSessions завершает все сессии пользователя: refresh-токены отзываются
+// в базе, а выданные access-токены — через denylist.
+func (uc *AuthUsecase) revokeSessions(ctx context.Context, userID string) error {
+	if err := uc.refreshRepo.RevokeUser(ctx, userID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := uc.denylist.Revoke(ctx, &denylist.Revocation{
+		UserID: userID,
+		Before: now.Truncate(time.Second),
+		Until:  now.Add(uc.accessExpiry),
+	}); err != nil {
+		return fmt.Errorf("%w: %v", e.ErrInternalServer, err)
+	}
+	return nil
+}
+
+func (uc *AuthUsecase) generateTokens(userID, sessionID string) (*models.TokenDetails, error) {
 	const op = "auth_usecase.generateTokens"
 	log := uc.log.With(
 		slog.String("op", op),
@@ -250,17 +479,20 @@ func (uc *AuthUsecase) generateTokens(userID string) (*models.TokenDetails, erro
 	log.Debug("generating new tokens")
 	startTime := time.Now()
 
+	key, err := uc.keys.Active()
+	if err != nil {
+		log.Error("no signing key available",
+			slog.Duration("duration", time.Since(startTime)))
+		return nil, err
+	}
+
 	now := time.Now()
 	accessExpire := now.Add(uc.accessExpiry).Unix()
 	refreshExpire := now.Add(uc.refreshExpiry).Unix()
 
-	accessClaims := jwt.MapClaims{
-		"user_id": userID,
-		"exp":     accessExpire,
-		"iat":     now.Unix(),
-	}
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString([]byte(uc.secretKey))
+	accessID := uuid.New().String()
+	accessClaims := uc.newClaims(userID, sessionID, models.TokenTypeAccess, accessID, now, accessExpire)
+	accessTokenString, err := signToken(key, accessClaims)
 	if err != nil {
 		log.Error("failed to sign access token",
 			slog.String("error", err.Error()),
@@ -268,13 +500,9 @@ func (uc *AuthUsecase) generateTokens(userID string) (*models.TokenDetails, erro
 		return nil, err
 	}
 
-	refreshClaims := jwt.MapClaims{
-		"user_id": userID,
-		"exp":     refreshExpire,
-		"iat":     now.Unix(),
-	}
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString([]byte(uc.secretKey))
+	refreshID := uuid.New().String()
+	refreshClaims := uc.newClaims(userID, sessionID, models.TokenTypeRefresh, refreshID, now, refreshExpire)
+	refreshTokenString, err := signToken(key, refreshClaims)
 	if err != nil {
 		log.Error("failed to sign refresh token",
 			slog.String("error", err.Error()),
@@ -290,7 +518,106 @@ func (uc *AuthUsecase) generateTokens(userID string) (*models.TokenDetails, erro
 	return &models.TokenDetails{
 		AccessToken:  accessTokenString,
 		RefreshToken: refreshTokenString,
+		AccessUUID:   accessID,
+		RefreshUUID:  refreshID,
 		AtExpires:    accessExpire,
 		RtExpires:    refreshExpire,
 	}, nil
 }
+
+// tokenClaims: SessionID (sid) — семья refresh-токенов, к которой относится
+// токен; по ней Logout находит сессию.
+type tokenClaims struct {
+	UserID    string `json:"user_id"`
+	Type      string `json:"typ"`
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+func (uc *AuthUsecase) newClaims(userID, sessionID, typ, id string, issuedAt time.Time, expiresAt int64) *tokenClaims {
+	return &tokenClaims{
+		UserID:    userID,
+		Type:      typ,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(time.Unix(expiresAt, 0)),
+		},
+	}
+}
+
+// parseToken проверяет подпись, срок и тип токена. Токены без typ,
+// выпущенные до его появления, не принимаются ни как access, ни как refresh.
+func (uc *AuthUsecase) parseToken(tokenString, typ string) (*tokenClaims, error) {
+	claims := &tokenClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg(), jwt.SigningMethodHS256.Alg()}))
+	if _, err := parser.ParseWithClaims(tokenString, claims, uc.verificationKey); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case claims.UserID == "":
+		return nil, errors.New("missing user_id in token claims")
+	case claims.Type != typ:
+		return nil, fmt.Errorf("unexpected token type %q", claims.Type)
+	case claims.IssuedAt == nil || claims.ExpiresAt == nil:
+		return nil, errors.New("missing iat or exp in token claims")
+	}
+	return claims, nil
+}
+
+// verificationKey выбирает ключ проверки по kid; HS256 принимается только
+// на время перехода и только при заданном SECRET_KEY.
+func (uc *AuthUsecase) verificationKey(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodEd25519:
+		kid, _ := token.Header["kid"].(string)
+		key, ok := uc.keys.PublicKey(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		return key, nil
+	case *jwt.SigningMethodHMAC:
+		if uc.secretKey == "" {
+			return nil, errors.New("HS256 tokens are no longer accepted")
+		}
+		return []byte(uc.secretKey), nil
+	default:
+		return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+	}
+}
+
+// PublicKeys — ключи для JWKS.
+func (uc *AuthUsecase) PublicKeys() []*models.SigningKey {
+	return uc.keys.PublicKeys()
+}
+
+func signToken(key *models.SigningKey, claims *tokenClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.PrivateKey)
+}
+
+// hashToken — в базе хранится только SHA-256 refresh-токена.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (uc *AuthUsecase) UnlockAccount(ctx context.Context, email, ip string) error {
+	const op = "auth_usecase.UnlockAccount"
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.String("ip", ip),
+	)
+
+	if err := uc.lockout.Unlock(ctx, email, ip); err != nil {
+		log.Error("failed to unlock account",
+			slog.String("error", err.Error()))
+		return e.ErrInternalServer
+	}
+
+	log.Info("account unlocked")
+	return nil
+}
@@ -0,0 +1,245 @@
+package auth_usecase
+
+import (
+	e "auth_service/internal/domain/errors"
+	"auth_service/internal/domain/models"
+	"auth_service/internal/mailer"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"log/slog"
+	"net/url"
+	"time"
+)
+
+// Политики для аккаунтов с неподтверждённым email, см. config.EmailConfig.
+const (
+	UnverifiedAllow = "allow"
+	UnverifiedGrace = "grace"
+	UnverifiedDeny  = "deny"
+)
+
+const sendTimeout = 30 * time.Second
+
+func (uc *AuthUsecase) mayLogin(user *models.User) bool {
+	if user.EmailVerifiedAt != nil {
+		return true
+	}
+	switch uc.cfg.Email.UnverifiedPolicy {
+	case UnverifiedAllow:
+		return true
+	case UnverifiedGrace:
+		return time.Since(user.CreatedAt) < uc.cfg.Email.GracePeriod
+	default:
+		return false
+	}
+}
+
+func (uc *AuthUsecase) VerifyEmail(ctx context.Context, token string) error {
+	const op = "auth_usecase.VerifyEmail"
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.Int("token_length", len(token)),
+	)
+
+	log.Info("verifying email")
+	startTime := time.Now()
+
+	userID, err := uc.emailRepo.VerifyEmail(ctx, hashToken(token))
+	if err != nil {
+		log.Warn("email verification failed",
+			slog.String("error", err.Error()),
+			slog.Duration("duration", time.Since(startTime)))
+		return err
+	}
+
+	log.Info("email verified",
+		slog.String("user_id", userID),
+		slog.Duration("duration", time.Since(startTime)))
+	return nil
+}
+
+// RequestPasswordReset отвечает одинаково, есть ли такой пользователь или нет:
+// письмо уходит в фоне, поэтому и время ответа этого не выдаёт.
+func (uc *AuthUsecase) RequestPasswordReset(ctx context.Context, email string) error {
+	const op = "auth_usecase.RequestPasswordReset"
+	log := uc.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("password reset requested")
+	startTime := time.Now()
+
+	user, err := uc.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, e.ErrUserNotFound) {
+			log.Debug("password reset for unknown email ignored",
+				slog.Duration("duration", time.Since(startTime)))
+			return nil
+		}
+		log.Error("failed to retrieve user",
+			slog.String("error", err.Error()),
+			slog.Duration("duration", time.Since(startTime)))
+		return err
+	}
+
+	uc.sendEmailToken(user.ID, user.Email, models.EmailTokenResetPassword)
+
+	log.Debug("password reset accepted",
+		slog.Duration("duration", time.Since(startTime)))
+	return nil
+}
+
+// ConfirmPasswordReset меняет пароль по токену из письма и завершает все
+// сессии пользователя: сброс обычно означает, что пароль мог утечь.
+func (uc *AuthUsecase) ConfirmPasswordReset(ctx context.Context, token, password string) error {
+	const op = "auth_usecase.ConfirmPasswordReset"
+	log := uc.log.With(
+		slog.String("op", op),
+		slog.Int("token_length", len(token)),
+	)
+
+	log.Info("confirming password reset")
+	startTime := time.Now()
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		log.Error("failed to hash password",
+			slog.String("error", err.Error()),
+			slog.Duration("duration", time.Since(startTime)))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	userID, err := uc.emailRepo.ResetPassword(ctx, hashToken(token), string(passwordHash))
+	if err != nil {
+		log.Warn("password reset failed",
+			slog.String("error", err.Error()),
+			slog.Duration("duration", time.Since(startTime)))
+		return err
+	}
+	log = log.With(slog.String("user_id", userID))
+
+	if err := uc.revokeSessions(ctx, userID); err != nil {
+		// пароль уже сменён; старые сессии доживут до истечения токенов
+		log.Error("failed to revoke sessions after password reset",
+			slog.String("error", err.Error()),
+			slog.Duration("duration", time.Since(startTime)))
+		return nil
+	}
+
+	log.Info("password reset",
+		slog.Duration("duration", time.Since(startTime)))
+	return nil
+}
+
+// resendVerification отправляет письмо подтверждения при входе: прежнее могло
+// потеряться или истечь. Новый токен гасит ссылку из прежнего письма, поэтому
+// повторные попытки входа не шлют письмо чаще resend_interval.
+func (uc *AuthUsecase) resendVerification(ctx context.Context, user *models.User) {
+	last, err := uc.emailRepo.LastIssued(ctx, user.ID, models.EmailTokenVerifyEmail)
+	if err != nil {
+		uc.log.Error("failed to check last verification email",
+			slog.String("user_id", user.ID),
+			slog.String("error", err.Error()))
+		return
+	}
+	if time.Since(last) < uc.cfg.Email.ResendInterval {
+		return
+	}
+	uc.sendEmailToken(user.ID, user.Email, models.EmailTokenVerifyEmail)
+}
+
+// sendEmailToken выпускает токен и отправляет письмо со ссылкой в фоне:
+// почтовый сервер может отвечать долго, а исход клиенту всё равно не сообщается.
+func (uc *AuthUsecase) sendEmailToken(userID, email, purpose string) {
+	log := uc.log.With(
+		slog.String("op", "auth_usecase.sendEmailToken"),
+		slog.String("user_id", userID),
+		slog.String("purpose", purpose),
+	)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+
+		token, err := newEmailToken()
+		if err != nil {
+			log.Error("failed to generate email token",
+				slog.String("error", err.Error()))
+			return
+		}
+
+		ttl, link, msg := uc.cfg.Email.VerifyTokenTTL, uc.cfg.Email.VerifyURL, verifyEmailMessage
+		if purpose == models.EmailTokenResetPassword {
+			ttl, link, msg = uc.cfg.Email.ResetTokenTTL, uc.cfg.Email.ResetURL, resetPasswordMessage
+		}
+
+		if err := uc.emailRepo.Create(ctx, &models.EmailToken{
+			ID:        uuid.New().String(),
+			UserID:    userID,
+			Purpose:   purpose,
+			TokenHash: hashToken(token),
+			ExpiresAt: time.Now().Add(ttl),
+		}); err != nil {
+			log.Error("failed to store email token",
+				slog.String("error", err.Error()))
+			return
+		}
+
+		link, err = withToken(link, token)
+		if err != nil {
+			log.Error("failed to build email link",
+				slog.String("error", err.Error()))
+			return
+		}
+
+		if err := uc.mailer.Send(ctx, msg(email, link, ttl)); err != nil {
+			log.Error("failed to send email",
+				slog.String("error", err.Error()))
+			return
+		}
+		log.Info("email sent")
+	}()
+}
+
+func verifyEmailMessage(to, link string, ttl time.Duration) *mailer.Message {
+	return &mailer.Message{
+		To:      to,
+		Subject: "Confirm your email",
+		Body: "Open the link below to confirm your email address:\n\n" + link +
+			"\n\nThe link is valid for " + ttl.String() + ". If you did not sign up, ignore this email.",
+	}
+}
+
+func resetPasswordMessage(to, link string, ttl time.Duration) *mailer.Message {
+	return &mailer.Message{
+		To:      to,
+		Subject: "Reset your password",
+		Body: "Open the link below to choose a new password:\n\n" + link +
+			"\n\nThe link is valid for " + ttl.String() + " and works once. If you did not request a reset, ignore this email.",
+	}
+}
+
+// newEmailToken — 32 случайных байта в base64url: токен попадает в ссылку.
+func newEmailToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func withToken(link, token string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,17 @@
+-- ключи подписи токенов: active — подписывает, retiring — только проверяет
+-- ещё не истёкшие токены, retired — больше не публикуется, закрытая часть стёрта
+CREATE TABLE signing_keys
+(
+    kid         VARCHAR(64) PRIMARY KEY,
+    algorithm   VARCHAR(16) NOT NULL,
+    private_key BYTEA,
+    public_key  BYTEA       NOT NULL,
+    state       VARCHAR(16) NOT NULL CHECK (state IN ('active', 'retiring', 'retired')),
+    created_at  TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    rotated_at  TIMESTAMP,
+    retired_at  TIMESTAMP
+);
+
+-- активный ключ всегда один: параллельные ротации или первые запуски реплик
+-- не создадут второй
+CREATE UNIQUE INDEX idx_signing_keys_active ON signing_keys (state) WHERE state = 'active';
@@ -0,0 +1,16 @@
+ALTER TABLE users
+    ADD COLUMN email_verified_at TIMESTAMP;
+
+-- одноразовые токены из писем: подтверждение email и сброс пароля; хранятся хешами
+CREATE TABLE email_tokens
+(
+    id         UUID PRIMARY KEY,
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    purpose    VARCHAR(16) NOT NULL CHECK (purpose IN ('verify_email', 'reset_password')),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP   NOT NULL,
+    used_at    TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_email_tokens_user_id ON email_tokens (user_id, purpose);
@@ -10,7 +10,8 @@ import logging
 import os
 from omegaconf import OmegaConf
 from aiokafka import AIOKafkaConsumer, AIOKafkaProducer
-from api.functions import save_base64_image
+from minio import Minio
+from api.functions import fetch_image, save_image_bytes
 from nsfw_detector import predict
 
 
@@ -22,6 +23,12 @@ logger = logging.getLogger(__name__)
 
 config = OmegaConf.load("config.yaml")
 model = predict.load_model(config.MODEL_PATH)
+s3 = Minio(
+    config.S3.ENDPOINT,
+    access_key=config.S3.ACCESS_KEY,
+    secret_key=config.S3.SECRET_KEY,
+    secure=config.S3.SECURE,
+)
 
 
 async def connect_to_kafka():
@@ -54,23 +61,32 @@ async def connect_to_kafka():
 
 
 async def process_image(payload):
-    try:
-        image_id = payload.get("id")
-        user_id = payload.get("user_id")
-        base64_data = payload.get("data")
+    image_id = payload.get("id")
+    reference = {
+        "id": image_id,
+        "user_id": payload.get("user_id"),
+        "bucket": payload.get("bucket"),
+        "key": payload.get("key"),
+        "checksum": payload.get("checksum"),
+        "size": payload.get("size"),
+    }
 
-        if not base64_data:
-            logger.error(f"not base64, try again")
-            return {"id": image_id, "user_id": user_id, 'data': base64_data, "error": "IMAGE DATA EMPTY"}
-
-        image_path = await save_base64_image(base64_data)
+    try:
+        if not reference["bucket"] or not reference["key"]:
+            logger.error(f"image reference missing for {image_id}")
+            return {**reference, "error": "IMAGE REFERENCE EMPTY"}
+
+        image_bytes = await fetch_image(
+            s3,
+            reference["bucket"],
+            reference["key"],
+            checksum=reference["checksum"],
+            size=reference["size"],
+        )
+
+        image_path = await save_image_bytes(image_bytes)
         if not image_path:
-            return {
-                "id": image_id,
-                "user_id": user_id,
-                "data": base64_data,
-                "error": "IMAGE SIZE TOO LARGE OR INVALID BASE64 DATA"
-            }
+            return {**reference, "error": "IMAGE SIZE TOO LARGE OR INVALID IMAGE DATA"}
 
         results = predict.classify(model, image_path)
         os.remove(image_path)
@@ -91,16 +107,22 @@ async def process_image(payload):
             is_nsfw = False
 
         return {
-            "id": image_id,
-            "user_id": user_id,
-            "data": base64_data,
+            **reference,
             "nsfw_scores": results['data'],
             "is_nsfw": is_nsfw
         }
 
     except Exception as e:
         logging.error(f"Exception during processing: {str(e)}")
-        return {"error": f"Exception during processing: {str(e)}"}
+        return {**reference, "error": f"Exception during processing: {str(e)}"}
+
+
+TRACE_HEADERS = ("traceparent", "tracestate", "baggage", "x-request-id")
+
+
+def trace_headers(headers):
+    """Пробрасывает W3C-контекст трейса и id запроса шлюза в результат, чтобы storage продолжил тот же трейс."""
+    return [(key, value) for key, value in (headers or ()) if key in TRACE_HEADERS] or None
 
 
 async def consume_and_produce():
@@ -112,13 +134,15 @@ async def consume_and_produce():
             async for msg in consumer:
                 try:
                     payload = json.loads(msg.value.decode("utf-8"))
-                    logging.info(f"Processing image ID: {payload.get('id')}")
+                    request_id = dict(msg.headers or ()).get("x-request-id", b"").decode("utf-8", "replace")
+                    logging.info(f"Processing image ID: {payload.get('id')} request_id={request_id}")
                     result = await process_image(payload)
                     logging.info(f"Processed image ID: {json.dumps(result)}")
                     try:
                         fut = await producer.send(
                             config.KAFKA.TOPIC_OUTPUT,
                             json.dumps(result).encode("utf-8"),
+                            headers=trace_headers(msg.headers),
                             #partition=1
                         )
                         res = await fut
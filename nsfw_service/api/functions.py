@@ -1,16 +1,49 @@
 from random import randint
+import asyncio
 import aiofiles
 import base64
+import hashlib
 import re
 
 MAX_IMAGE_SIZE = 10
-MAX_IMAGE_SIZE = MAX_IMAGE_SIZE * 1000000
+MAX_IMAGE_SIZE = MAX_IMAGE_SIZE * 1024 * 1024
 
 
-async def save_base64_image(base64_str):
+def _read_object(client, bucket, key):
+    stat = client.stat_object(bucket, key)
+    if stat.size > MAX_IMAGE_SIZE:
+        raise ValueError(f"image too large: {stat.size} bytes")
+
+    # объект могли подменить после stat_object: читаем не больше лимита и байта сверху
+    response = client.get_object(bucket, key, length=MAX_IMAGE_SIZE + 1)
     try:
-        image_bytes = base64.b64decode(base64_str)
+        return response.read()
+    finally:
+        response.close()
+        response.release_conn()
+
+
+async def fetch_image(client, bucket, key, checksum=None, size=None):
+    """Забирает байты изображения из объектного хранилища и сверяет размер и sha256; больше MAX_IMAGE_SIZE не загружает."""
+    if size and size > MAX_IMAGE_SIZE:
+        raise ValueError(f"image too large: {size} bytes")
+
+    image_bytes = await asyncio.get_running_loop().run_in_executor(
+        None, _read_object, client, bucket, key
+    )
 
+    if len(image_bytes) > MAX_IMAGE_SIZE:
+        raise ValueError(f"image too large: more than {MAX_IMAGE_SIZE} bytes")
+    if size and len(image_bytes) != size:
+        raise ValueError(f"size mismatch: expected {size}, got {len(image_bytes)}")
+    if checksum and hashlib.sha256(image_bytes).hexdigest() != checksum:
+        raise ValueError("checksum mismatch")
+
+    return image_bytes
+
+
+async def save_image_bytes(image_bytes):
+    try:
         if len(image_bytes) > MAX_IMAGE_SIZE:
             print("Image too large.")
             return False
@@ -22,7 +55,7 @@ async def save_base64_image(base64_str):
         return file_name
 
     except Exception as e:
-        print(f"Failed to save image from base64: {e}")
+        print(f"Failed to save image: {e}")
         return False
 
 
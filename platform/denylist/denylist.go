@@ -0,0 +1,86 @@
+// Package denylist — формат списка отозванных access-токенов в Redis, общий для
+// auth_service, который его пишет, и шлюза, который его читает. Отзыв хранится
+// ключом с TTL до истечения токена для прямой проверки и записью Redis Stream
+// для ленты с полями jti, user_id, before и until (unix-секунды).
+package denylist
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	TokenKeyPrefix = "auth:denylist:token:"
+	UserKeyPrefix  = "auth:denylist:user:"
+)
+
+// Revocation — запись ленты отзывов. TokenID отзывает один токен по jti,
+// UserID с Before — все токены пользователя, выпущенные раньше Before.
+// Before, как и iat, берётся с точностью до секунды, чтобы токен, выданный
+// сразу после отзыва (например, при сбросе пароля), остался действительным.
+// После Until отозванные токены истекли сами, и запись можно забыть.
+type Revocation struct {
+	TokenID string
+	UserID  string
+	Before  time.Time
+	Until   time.Time
+}
+
+// Values — поля записи потока для XAdd.
+func (r *Revocation) Values() []interface{} {
+	return []interface{}{
+		"jti", r.TokenID,
+		"user_id", r.UserID,
+		"before", unixString(r.Before),
+		"until", unixString(r.Until),
+	}
+}
+
+// Parse читает запись потока, сделанную по Values.
+func Parse(values map[string]interface{}) *Revocation {
+	return &Revocation{
+		TokenID: stringValue(values["jti"]),
+		UserID:  stringValue(values["user_id"]),
+		Before:  unixValue(values["before"]),
+		Until:   unixValue(values["until"]),
+	}
+}
+
+// IsRevoked проверяет отзыв по ключам, мимо ленты.
+func IsRevoked(ctx context.Context, client redis.Cmdable, tokenID, userID string, issuedAt time.Time) (bool, error) {
+	values, err := client.MGet(ctx, TokenKeyPrefix+tokenID, UserKeyPrefix+userID).Result()
+	if err != nil {
+		return false, err
+	}
+
+	if tokenID != "" && values[0] != nil {
+		return true, nil
+	}
+	if before := unixValue(values[1]); !before.IsZero() && issuedAt.Before(before) {
+		return true, nil
+	}
+	return false, nil
+}
+
+func unixString(t time.Time) string {
+	if t.IsZero() {
+		return "0"
+	}
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+func unixValue(v interface{}) time.Time {
+	sec, err := strconv.ParseInt(stringValue(v), 10, 64)
+	if err != nil || sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
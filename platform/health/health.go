@@ -0,0 +1,156 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Check проверяет одну внешнюю зависимость; nil означает, что она доступна.
+type Check func(ctx context.Context) error
+
+type CheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Checker опрашивает зависимости сервиса параллельно, каждую со своим таймаутом.
+type Checker struct {
+	timeout time.Duration
+	names   []string
+	checks  map[string]Check
+	log     *slog.Logger
+}
+
+func NewChecker(timeout time.Duration, log *slog.Logger) *Checker {
+	return &Checker{
+		timeout: timeout,
+		checks:  make(map[string]Check),
+		log:     log,
+	}
+}
+
+func (c *Checker) Register(name string, check Check) {
+	c.names = append(c.names, name)
+	c.checks[name] = check
+}
+
+func (c *Checker) Run(ctx context.Context) *Report {
+	report := &Report{
+		Status: StatusUp,
+		Checks: make(map[string]CheckResult, len(c.names)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range c.names {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			result := c.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}(name, c.checks[name])
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	startTime := time.Now()
+	errCh := make(chan error, 1)
+	// не все клиенты уважают контекст, поэтому ждём результат не дольше таймаута
+	go func() { errCh <- check(ctx) }()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := CheckResult{
+		Status:   StatusUp,
+		Duration: time.Since(startTime).String(),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Watch периодически прогоняет проверки и сообщает итог в onChange,
+// пока не будет отменён ctx. Используется для gRPC health-протокола.
+func (c *Checker) Watch(ctx context.Context, interval time.Duration, onChange func(ready bool)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	ready := false
+	onChange(ready)
+	for {
+		report := c.Run(ctx)
+		if current := report.Status == StatusUp; current != ready {
+			ready = current
+			c.log.Info("readiness changed",
+				slog.Bool("ready", ready),
+				slog.Any("checks", report.Checks))
+			onChange(ready)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// LivenessHandler отвечает 200, пока процесс жив; зависимости не опрашиваются.
+func (c *Checker) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, http.StatusOK, &Report{Status: StatusUp})
+	})
+}
+
+// ReadinessHandler отвечает 503 с разбивкой по зависимостям, если хотя бы одна недоступна.
+func (c *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Run(r.Context())
+		code := http.StatusOK
+		if report.Status != StatusUp {
+			code = http.StatusServiceUnavailable
+		}
+		writeReport(w, code, report)
+	})
+}
+
+func writeReport(w http.ResponseWriter, code int, report *Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,141 @@
+package requestid
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// Header — HTTP-заголовок, в котором id приходит от клиента и возвращается ему.
+	Header = "X-Request-ID"
+	// Key — ключ gRPC-метаданных и заголовка Kafka-сообщения.
+	Key = "x-request-id"
+
+	maxLength = 128
+)
+
+type ctxKey struct{}
+
+func WithContext(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// New генерирует id для запроса, пришедшего без заголовка.
+func New() string {
+	return time.Now().Format("20060102150405") + "-" + randString(8)
+}
+
+// Valid отсекает id, которые нельзя без опаски писать в логи, заголовки и БД.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// FromMessage достаёт id из заголовков сообщения; пустая строка, если его нет.
+func FromMessage(msg *kafka.Message) string {
+	for _, h := range msg.Headers {
+		if h.Key == Key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Inject кладёт id из ctx в заголовки сообщения.
+func Inject(ctx context.Context, msg *kafka.Message) {
+	id := FromContext(ctx)
+	if id == "" {
+		return
+	}
+	for i, h := range msg.Headers {
+		if h.Key == Key {
+			msg.Headers[i].Value = []byte(id)
+			return
+		}
+	}
+	msg.Headers = append(msg.Headers, kafka.Header{Key: Key, Value: []byte(id)})
+}
+
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoing(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoing(ctx), desc, cc, method, opts...)
+	}
+}
+
+// UnaryServerInterceptor переносит id из входящих метаданных в контекст обработчика.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(incoming(ctx), req)
+	}
+}
+
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: ss, ctx: incoming(ss.Context())})
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func outgoing(ctx context.Context) context.Context {
+	if id := FromContext(ctx); id != "" {
+		return metadata.AppendToOutgoingContext(ctx, Key, id)
+	}
+	return ctx
+}
+
+func incoming(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	if values := md.Get(Key); len(values) > 0 {
+		return WithContext(ctx, values[0])
+	}
+	return ctx
+}
+
+func randString(n int) string {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[rand.Intn(len(letters))]
+	}
+	return string(b)
+}
@@ -0,0 +1,29 @@
+package awstrace
+
+import (
+	"context"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/deeelis/platform/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware открывает клиентский спан на каждый вызов AWS SDK (S3, MinIO).
+// Подключается через config.WithAPIOptions.
+func Middleware(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("TracingSpan",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			service := awsmiddleware.GetServiceID(ctx)
+			operation := awsmiddleware.GetOperationName(ctx)
+			ctx, span := tracing.Start(ctx, service+"."+operation, trace.SpanKindClient,
+				semconv.RPCSystemKey.String("aws-api"),
+				semconv.RPCService(service),
+				semconv.RPCMethod(operation))
+
+			out, metadata, err := next.HandleInitialize(ctx, in)
+			tracing.End(span, err)
+			return out, metadata, err
+		}), middleware.Before)
+}
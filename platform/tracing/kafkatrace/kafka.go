@@ -0,0 +1,70 @@
+package kafkatrace
+
+import (
+	"context"
+
+	"github.com/deeelis/platform/tracing"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// headerCarrier позволяет пропагатору читать и писать заголовки Kafka-сообщения.
+type headerCarrier struct {
+	msg *kafka.Message
+}
+
+var _ propagation.TextMapCarrier = headerCarrier{}
+
+func (c headerCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c headerCarrier) Set(key string, value string) {
+	for i, h := range c.msg.Headers {
+		if h.Key == key {
+			c.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.msg.Headers = append(c.msg.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, h := range c.msg.Headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
+
+// Inject кладёт контекст трейса из ctx в заголовки сообщения.
+func Inject(ctx context.Context, msg *kafka.Message) {
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier{msg: msg})
+}
+
+// StartConsumer продолжает трейс, пришедший в заголовках сообщения, спаном обработки.
+func StartConsumer(ctx context.Context, topic string, msg *kafka.Message) (context.Context, trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, headerCarrier{msg: msg})
+	return tracing.Start(ctx, topic+" process", trace.SpanKindConsumer,
+		semconv.MessagingSystemKafka,
+		semconv.MessagingDestinationName(topic),
+		semconv.MessagingOperationTypeDeliver,
+		semconv.MessagingMessageBodySize(len(msg.Value)))
+}
+
+// StartProducer открывает спан отправки; его контекст затем передаётся в Inject.
+func StartProducer(ctx context.Context, topic string, count int) (context.Context, trace.Span) {
+	return tracing.Start(ctx, topic+" publish", trace.SpanKindProducer,
+		semconv.MessagingSystemKafka,
+		semconv.MessagingDestinationName(topic),
+		semconv.MessagingOperationTypePublish,
+		semconv.MessagingBatchMessageCount(count))
+}
@@ -0,0 +1,54 @@
+package redistrace
+
+import (
+	"context"
+	"errors"
+
+	"github.com/deeelis/platform/tracing"
+	"github.com/go-redis/redis/v8"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Hook открывает клиентский спан на каждую команду или пайплайн go-redis.
+type Hook struct{}
+
+var _ redis.Hook = Hook{}
+
+func (Hook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	ctx, _ = tracing.Start(ctx, "redis "+cmd.Name(), trace.SpanKindClient,
+		semconv.DBSystemRedis,
+		semconv.DBOperationName(cmd.Name()))
+	return ctx, nil
+}
+
+func (Hook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	tracing.End(trace.SpanFromContext(ctx), redisErr(cmd.Err()))
+	return nil
+}
+
+func (Hook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	ctx, _ = tracing.Start(ctx, "redis pipeline", trace.SpanKindClient,
+		semconv.DBSystemRedis,
+		semconv.DBOperationName("pipeline"))
+	return ctx, nil
+}
+
+func (Hook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if err = redisErr(cmd.Err()); err != nil {
+			break
+		}
+	}
+	tracing.End(trace.SpanFromContext(ctx), err)
+	return nil
+}
+
+// redisErr не считает промах по ключу ошибкой
+func redisErr(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
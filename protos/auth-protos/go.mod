@@ -0,0 +1,3 @@
+module github.com/deeelis/auth-protos
+
+go 1.21.5
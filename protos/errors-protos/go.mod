@@ -0,0 +1,3 @@
+module github.com/deeelis/errors-protos
+
+go 1.21
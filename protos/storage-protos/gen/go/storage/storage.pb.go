@@ -0,0 +1,2186 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.30.2
+// source: storage/storage.proto
+
+package storagev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ContentType int32
+
+const (
+	ContentType_TEXT  ContentType = 0
+	ContentType_IMAGE ContentType = 1
+)
+
+// Enum value maps for ContentType.
+var (
+	ContentType_name = map[int32]string{
+		0: "TEXT",
+		1: "IMAGE",
+	}
+	ContentType_value = map[string]int32{
+		"TEXT":  0,
+		"IMAGE": 1,
+	}
+)
+
+func (x ContentType) Enum() *ContentType {
+	p := new(ContentType)
+	*p = x
+	return p
+}
+
+func (x ContentType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ContentType) Descriptor() protoreflect.EnumDescriptor {
+	return file_storage_storage_proto_enumTypes[0].Descriptor()
+}
+
+func (ContentType) Type() protoreflect.EnumType {
+	return &file_storage_storage_proto_enumTypes[0]
+}
+
+func (x ContentType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ContentType.Descriptor instead.
+func (ContentType) EnumDescriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{0}
+}
+
+type ProcessingStatus int32
+
+const (
+	ProcessingStatus_PENDING    ProcessingStatus = 0
+	ProcessingStatus_PROCESSING ProcessingStatus = 1
+	ProcessingStatus_COMPLETED  ProcessingStatus = 2
+	ProcessingStatus_FAILED     ProcessingStatus = 3
+)
+
+// Enum value maps for ProcessingStatus.
+var (
+	ProcessingStatus_name = map[int32]string{
+		0: "PENDING",
+		1: "PROCESSING",
+		2: "COMPLETED",
+		3: "FAILED",
+	}
+	ProcessingStatus_value = map[string]int32{
+		"PENDING":    0,
+		"PROCESSING": 1,
+		"COMPLETED":  2,
+		"FAILED":     3,
+	}
+)
+
+func (x ProcessingStatus) Enum() *ProcessingStatus {
+	p := new(ProcessingStatus)
+	*p = x
+	return p
+}
+
+func (x ProcessingStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProcessingStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_storage_storage_proto_enumTypes[1].Descriptor()
+}
+
+func (ProcessingStatus) Type() protoreflect.EnumType {
+	return &file_storage_storage_proto_enumTypes[1]
+}
+
+func (x ProcessingStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProcessingStatus.Descriptor instead.
+func (ProcessingStatus) EnumDescriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{1}
+}
+
+type ContentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContentId     string                 `protobuf:"bytes,1,opt,name=content_id,json=contentId,proto3" json:"content_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Moderator     bool                   `protobuf:"varint,3,opt,name=moderator,proto3" json:"moderator,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContentRequest) Reset() {
+	*x = ContentRequest{}
+	mi := &file_storage_storage_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContentRequest) ProtoMessage() {}
+
+func (x *ContentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContentRequest.ProtoReflect.Descriptor instead.
+func (*ContentRequest) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ContentRequest) GetContentId() string {
+	if x != nil {
+		return x.ContentId
+	}
+	return ""
+}
+
+func (x *ContentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ContentRequest) GetModerator() bool {
+	if x != nil {
+		return x.Moderator
+	}
+	return false
+}
+
+type ContentResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ContentId string                 `protobuf:"bytes,1,opt,name=content_id,json=contentId,proto3" json:"content_id,omitempty"`
+	Type      ContentType            `protobuf:"varint,2,opt,name=type,proto3,enum=storage.ContentType" json:"type,omitempty"`
+	Status    ProcessingStatus       `protobuf:"varint,3,opt,name=status,proto3,enum=storage.ProcessingStatus" json:"status,omitempty"`
+	// Types that are valid to be assigned to Content:
+	//
+	//	*ContentResponse_Text
+	//	*ContentResponse_Image
+	Content       isContentResponse_Content `protobuf_oneof:"content"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContentResponse) Reset() {
+	*x = ContentResponse{}
+	mi := &file_storage_storage_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContentResponse) ProtoMessage() {}
+
+func (x *ContentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContentResponse.ProtoReflect.Descriptor instead.
+func (*ContentResponse) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ContentResponse) GetContentId() string {
+	if x != nil {
+		return x.ContentId
+	}
+	return ""
+}
+
+func (x *ContentResponse) GetType() ContentType {
+	if x != nil {
+		return x.Type
+	}
+	return ContentType_TEXT
+}
+
+func (x *ContentResponse) GetStatus() ProcessingStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ProcessingStatus_PENDING
+}
+
+func (x *ContentResponse) GetContent() isContentResponse_Content {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *ContentResponse) GetText() *TextContent {
+	if x != nil {
+		if x, ok := x.Content.(*ContentResponse_Text); ok {
+			return x.Text
+		}
+	}
+	return nil
+}
+
+func (x *ContentResponse) GetImage() *ImageContent {
+	if x != nil {
+		if x, ok := x.Content.(*ContentResponse_Image); ok {
+			return x.Image
+		}
+	}
+	return nil
+}
+
+type isContentResponse_Content interface {
+	isContentResponse_Content()
+}
+
+type ContentResponse_Text struct {
+	Text *TextContent `protobuf:"bytes,4,opt,name=text,proto3,oneof"`
+}
+
+type ContentResponse_Image struct {
+	Image *ImageContent `protobuf:"bytes,5,opt,name=image,proto3,oneof"`
+}
+
+func (*ContentResponse_Text) isContentResponse_Content() {}
+
+func (*ContentResponse_Image) isContentResponse_Content() {}
+
+type TextContent struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	OriginalText     string                 `protobuf:"bytes,1,opt,name=original_text,json=originalText,proto3" json:"original_text,omitempty"`
+	AnalysisMetadata map[string]string      `protobuf:"bytes,2,rep,name=analysis_metadata,json=analysisMetadata,proto3" json:"analysis_metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TextContent) Reset() {
+	*x = TextContent{}
+	mi := &file_storage_storage_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TextContent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TextContent) ProtoMessage() {}
+
+func (x *TextContent) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TextContent.ProtoReflect.Descriptor instead.
+func (*TextContent) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TextContent) GetOriginalText() string {
+	if x != nil {
+		return x.OriginalText
+	}
+	return ""
+}
+
+func (x *TextContent) GetAnalysisMetadata() map[string]string {
+	if x != nil {
+		return x.AnalysisMetadata
+	}
+	return nil
+}
+
+type ImageContent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// не заполняется: бакет приватный, изображение отдаётся через шлюз по s3_key
+	ImageUrl         string            `protobuf:"bytes,1,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	AnalysisMetadata map[string]string `protobuf:"bytes,2,rep,name=analysis_metadata,json=analysisMetadata,proto3" json:"analysis_metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	S3Key            string            `protobuf:"bytes,3,opt,name=s3_key,json=s3Key,proto3" json:"s3_key,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ImageContent) Reset() {
+	*x = ImageContent{}
+	mi := &file_storage_storage_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImageContent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageContent) ProtoMessage() {}
+
+func (x *ImageContent) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageContent.ProtoReflect.Descriptor instead.
+func (*ImageContent) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ImageContent) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *ImageContent) GetAnalysisMetadata() map[string]string {
+	if x != nil {
+		return x.AnalysisMetadata
+	}
+	return nil
+}
+
+func (x *ImageContent) GetS3Key() string {
+	if x != nil {
+		return x.S3Key
+	}
+	return ""
+}
+
+type RegisterContentRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ContentId string                 `protobuf:"bytes,1,opt,name=content_id,json=contentId,proto3" json:"content_id,omitempty"`
+	Type      ContentType            `protobuf:"varint,2,opt,name=type,proto3,enum=storage.ContentType" json:"type,omitempty"`
+	UserId    string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Объект изображения, загруженный шлюзом; для текста не заполняется.
+	Bucket        string `protobuf:"bytes,4,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Key           string `protobuf:"bytes,5,opt,name=key,proto3" json:"key,omitempty"`
+	Checksum      string `protobuf:"bytes,6,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterContentRequest) Reset() {
+	*x = RegisterContentRequest{}
+	mi := &file_storage_storage_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterContentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterContentRequest) ProtoMessage() {}
+
+func (x *RegisterContentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterContentRequest.ProtoReflect.Descriptor instead.
+func (*RegisterContentRequest) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RegisterContentRequest) GetContentId() string {
+	if x != nil {
+		return x.ContentId
+	}
+	return ""
+}
+
+func (x *RegisterContentRequest) GetType() ContentType {
+	if x != nil {
+		return x.Type
+	}
+	return ContentType_TEXT
+}
+
+func (x *RegisterContentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RegisterContentRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *RegisterContentRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *RegisterContentRequest) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+type RegisterContentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterContentResponse) Reset() {
+	*x = RegisterContentResponse{}
+	mi := &file_storage_storage_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterContentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterContentResponse) ProtoMessage() {}
+
+func (x *RegisterContentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterContentResponse.ProtoReflect.Descriptor instead.
+func (*RegisterContentResponse) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RegisterContentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type RegisterContentBatchRequest struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Items         []*RegisterContentRequest `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterContentBatchRequest) Reset() {
+	*x = RegisterContentBatchRequest{}
+	mi := &file_storage_storage_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterContentBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterContentBatchRequest) ProtoMessage() {}
+
+func (x *RegisterContentBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterContentBatchRequest.ProtoReflect.Descriptor instead.
+func (*RegisterContentBatchRequest) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RegisterContentBatchRequest) GetItems() []*RegisterContentRequest {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type RegisterContentBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Registered    int32                  `protobuf:"varint,1,opt,name=registered,proto3" json:"registered,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterContentBatchResponse) Reset() {
+	*x = RegisterContentBatchResponse{}
+	mi := &file_storage_storage_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterContentBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterContentBatchResponse) ProtoMessage() {}
+
+func (x *RegisterContentBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterContentBatchResponse.ProtoReflect.Descriptor instead.
+func (*RegisterContentBatchResponse) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RegisterContentBatchResponse) GetRegistered() int32 {
+	if x != nil {
+		return x.Registered
+	}
+	return 0
+}
+
+type ListContentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Status        *ProcessingStatus      `protobuf:"varint,4,opt,name=status,proto3,enum=storage.ProcessingStatus,oneof" json:"status,omitempty"`
+	Type          *ContentType           `protobuf:"varint,5,opt,name=type,proto3,enum=storage.ContentType,oneof" json:"type,omitempty"`
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListContentRequest) Reset() {
+	*x = ListContentRequest{}
+	mi := &file_storage_storage_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListContentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListContentRequest) ProtoMessage() {}
+
+func (x *ListContentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListContentRequest.ProtoReflect.Descriptor instead.
+func (*ListContentRequest) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListContentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListContentRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListContentRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListContentRequest) GetStatus() ProcessingStatus {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return ProcessingStatus_PENDING
+}
+
+func (x *ListContentRequest) GetType() ContentType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return ContentType_TEXT
+}
+
+func (x *ListContentRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *ListContentRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+type ContentSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContentId     string                 `protobuf:"bytes,1,opt,name=content_id,json=contentId,proto3" json:"content_id,omitempty"`
+	Type          ContentType            `protobuf:"varint,2,opt,name=type,proto3,enum=storage.ContentType" json:"type,omitempty"`
+	Status        ProcessingStatus       `protobuf:"varint,3,opt,name=status,proto3,enum=storage.ProcessingStatus" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContentSummary) Reset() {
+	*x = ContentSummary{}
+	mi := &file_storage_storage_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContentSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContentSummary) ProtoMessage() {}
+
+func (x *ContentSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContentSummary.ProtoReflect.Descriptor instead.
+func (*ContentSummary) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ContentSummary) GetContentId() string {
+	if x != nil {
+		return x.ContentId
+	}
+	return ""
+}
+
+func (x *ContentSummary) GetType() ContentType {
+	if x != nil {
+		return x.Type
+	}
+	return ContentType_TEXT
+}
+
+func (x *ContentSummary) GetStatus() ProcessingStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ProcessingStatus_PENDING
+}
+
+func (x *ContentSummary) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ContentSummary) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListContentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*ContentSummary      `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListContentResponse) Reset() {
+	*x = ListContentResponse{}
+	mi := &file_storage_storage_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListContentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListContentResponse) ProtoMessage() {}
+
+func (x *ListContentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListContentResponse.ProtoReflect.Descriptor instead.
+func (*ListContentResponse) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListContentResponse) GetItems() []*ContentSummary {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ListContentResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type DeleteContentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContentId     string                 `protobuf:"bytes,1,opt,name=content_id,json=contentId,proto3" json:"content_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteContentRequest) Reset() {
+	*x = DeleteContentRequest{}
+	mi := &file_storage_storage_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteContentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteContentRequest) ProtoMessage() {}
+
+func (x *DeleteContentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteContentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteContentRequest) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeleteContentRequest) GetContentId() string {
+	if x != nil {
+		return x.ContentId
+	}
+	return ""
+}
+
+func (x *DeleteContentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type DeleteContentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PurgeAfter    *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=purge_after,json=purgeAfter,proto3" json:"purge_after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteContentResponse) Reset() {
+	*x = DeleteContentResponse{}
+	mi := &file_storage_storage_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteContentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteContentResponse) ProtoMessage() {}
+
+func (x *DeleteContentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteContentResponse.ProtoReflect.Descriptor instead.
+func (*DeleteContentResponse) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteContentResponse) GetPurgeAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PurgeAfter
+	}
+	return nil
+}
+
+type RestoreContentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContentId     string                 `protobuf:"bytes,1,opt,name=content_id,json=contentId,proto3" json:"content_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreContentRequest) Reset() {
+	*x = RestoreContentRequest{}
+	mi := &file_storage_storage_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreContentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreContentRequest) ProtoMessage() {}
+
+func (x *RestoreContentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreContentRequest.ProtoReflect.Descriptor instead.
+func (*RestoreContentRequest) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RestoreContentRequest) GetContentId() string {
+	if x != nil {
+		return x.ContentId
+	}
+	return ""
+}
+
+func (x *RestoreContentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type RestoreContentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreContentResponse) Reset() {
+	*x = RestoreContentResponse{}
+	mi := &file_storage_storage_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreContentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreContentResponse) ProtoMessage() {}
+
+func (x *RestoreContentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreContentResponse.ProtoReflect.Descriptor instead.
+func (*RestoreContentResponse) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RestoreContentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type WatchContentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContentId     string                 `protobuf:"bytes,1,opt,name=content_id,json=contentId,proto3" json:"content_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchContentRequest) Reset() {
+	*x = WatchContentRequest{}
+	mi := &file_storage_storage_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchContentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchContentRequest) ProtoMessage() {}
+
+func (x *WatchContentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchContentRequest.ProtoReflect.Descriptor instead.
+func (*WatchContentRequest) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *WatchContentRequest) GetContentId() string {
+	if x != nil {
+		return x.ContentId
+	}
+	return ""
+}
+
+func (x *WatchContentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ContentEvent struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ContentId        string                 `protobuf:"bytes,1,opt,name=content_id,json=contentId,proto3" json:"content_id,omitempty"`
+	Type             ContentType            `protobuf:"varint,2,opt,name=type,proto3,enum=storage.ContentType" json:"type,omitempty"`
+	Status           ProcessingStatus       `protobuf:"varint,3,opt,name=status,proto3,enum=storage.ProcessingStatus" json:"status,omitempty"`
+	AnalysisMetadata map[string]string      `protobuf:"bytes,4,rep,name=analysis_metadata,json=analysisMetadata,proto3" json:"analysis_metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	OriginalContent  string                 `protobuf:"bytes,5,opt,name=original_content,json=originalContent,proto3" json:"original_content,omitempty"`
+	OccurredAt       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ContentEvent) Reset() {
+	*x = ContentEvent{}
+	mi := &file_storage_storage_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContentEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContentEvent) ProtoMessage() {}
+
+func (x *ContentEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContentEvent.ProtoReflect.Descriptor instead.
+func (*ContentEvent) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ContentEvent) GetContentId() string {
+	if x != nil {
+		return x.ContentId
+	}
+	return ""
+}
+
+func (x *ContentEvent) GetType() ContentType {
+	if x != nil {
+		return x.Type
+	}
+	return ContentType_TEXT
+}
+
+func (x *ContentEvent) GetStatus() ProcessingStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ProcessingStatus_PENDING
+}
+
+func (x *ContentEvent) GetAnalysisMetadata() map[string]string {
+	if x != nil {
+		return x.AnalysisMetadata
+	}
+	return nil
+}
+
+func (x *ContentEvent) GetOriginalContent() string {
+	if x != nil {
+		return x.OriginalContent
+	}
+	return ""
+}
+
+func (x *ContentEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+type Webhook struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_storage_storage_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Webhook) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreateWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Secret        string                 `protobuf:"bytes,3,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookRequest) Reset() {
+	*x = CreateWebhookRequest{}
+	mi := &file_storage_storage_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookRequest) ProtoMessage() {}
+
+func (x *CreateWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookRequest.ProtoReflect.Descriptor instead.
+func (*CreateWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CreateWebhookRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type CreateWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhook       *Webhook               `protobuf:"bytes,1,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookResponse) Reset() {
+	*x = CreateWebhookResponse{}
+	mi := &file_storage_storage_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookResponse) ProtoMessage() {}
+
+func (x *CreateWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookResponse.ProtoReflect.Descriptor instead.
+func (*CreateWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CreateWebhookResponse) GetWebhook() *Webhook {
+	if x != nil {
+		return x.Webhook
+	}
+	return nil
+}
+
+func (x *CreateWebhookResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type ListWebhooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksRequest) Reset() {
+	*x = ListWebhooksRequest{}
+	mi := &file_storage_storage_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksRequest) ProtoMessage() {}
+
+func (x *ListWebhooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhooksRequest) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListWebhooksRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListWebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*Webhook             `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksResponse) Reset() {
+	*x = ListWebhooksResponse{}
+	mi := &file_storage_storage_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksResponse) ProtoMessage() {}
+
+func (x *ListWebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListWebhooksResponse) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+type DeleteWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	WebhookId     string                 `protobuf:"bytes,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookRequest) Reset() {
+	*x = DeleteWebhookRequest{}
+	mi := &file_storage_storage_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookRequest) ProtoMessage() {}
+
+func (x *DeleteWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookRequest.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DeleteWebhookRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DeleteWebhookRequest) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+type DeleteWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookResponse) Reset() {
+	*x = DeleteWebhookResponse{}
+	mi := &file_storage_storage_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookResponse) ProtoMessage() {}
+
+func (x *DeleteWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookResponse.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DeleteWebhookResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type WebhookDelivery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	WebhookId     string                 `protobuf:"bytes,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	ContentId     string                 `protobuf:"bytes,3,opt,name=content_id,json=contentId,proto3" json:"content_id,omitempty"`
+	Event         string                 `protobuf:"bytes,4,opt,name=event,proto3" json:"event,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Attempts      int32                  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	ResponseCode  int32                  `protobuf:"varint,7,opt,name=response_code,json=responseCode,proto3" json:"response_code,omitempty"`
+	LastError     string                 `protobuf:"bytes,8,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	NextAttemptAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_storage_storage_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDelivery) ProtoMessage() {}
+
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *WebhookDelivery) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetContentId() string {
+	if x != nil {
+		return x.ContentId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetResponseCode() int32 {
+	if x != nil {
+		return x.ResponseCode
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetNextAttemptAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextAttemptAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListWebhookDeliveriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	WebhookId     string                 `protobuf:"bytes,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesRequest) Reset() {
+	*x = ListWebhookDeliveriesRequest{}
+	mi := &file_storage_storage_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesRequest) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListWebhookDeliveriesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListWebhookDeliveriesRequest) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *ListWebhookDeliveriesRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListWebhookDeliveriesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListWebhookDeliveriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*WebhookDelivery     `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesResponse) Reset() {
+	*x = ListWebhookDeliveriesResponse{}
+	mi := &file_storage_storage_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesResponse) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListWebhookDeliveriesResponse) GetDeliveries() []*WebhookDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+type RedeliverWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	WebhookId     string                 `protobuf:"bytes,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	DeliveryId    string                 `protobuf:"bytes,3,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RedeliverWebhookRequest) Reset() {
+	*x = RedeliverWebhookRequest{}
+	mi := &file_storage_storage_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RedeliverWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedeliverWebhookRequest) ProtoMessage() {}
+
+func (x *RedeliverWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedeliverWebhookRequest.ProtoReflect.Descriptor instead.
+func (*RedeliverWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RedeliverWebhookRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RedeliverWebhookRequest) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *RedeliverWebhookRequest) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+type RedeliverWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Delivery      *WebhookDelivery       `protobuf:"bytes,1,opt,name=delivery,proto3" json:"delivery,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RedeliverWebhookResponse) Reset() {
+	*x = RedeliverWebhookResponse{}
+	mi := &file_storage_storage_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RedeliverWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedeliverWebhookResponse) ProtoMessage() {}
+
+func (x *RedeliverWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_storage_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedeliverWebhookResponse.ProtoReflect.Descriptor instead.
+func (*RedeliverWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_storage_storage_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RedeliverWebhookResponse) GetDelivery() *WebhookDelivery {
+	if x != nil {
+		return x.Delivery
+	}
+	return nil
+}
+
+var File_storage_storage_proto protoreflect.FileDescriptor
+
+const file_storage_storage_proto_rawDesc = "" +
+	"\n" +
+	"\x15storage/storage.proto\x12\astorage\x1a\x1fgoogle/protobuf/timestamp.proto\"f\n" +
+	"\x0eContentRequest\x12\x1d\n" +
+	"\n" +
+	"content_id\x18\x01 \x01(\tR\tcontentId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1c\n" +
+	"\tmoderator\x18\x03 \x01(\bR\tmoderator\"\xf3\x01\n" +
+	"\x0fContentResponse\x12\x1d\n" +
+	"\n" +
+	"content_id\x18\x01 \x01(\tR\tcontentId\x12(\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x14.storage.ContentTypeR\x04type\x121\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x19.storage.ProcessingStatusR\x06status\x12*\n" +
+	"\x04text\x18\x04 \x01(\v2\x14.storage.TextContentH\x00R\x04text\x12-\n" +
+	"\x05image\x18\x05 \x01(\v2\x15.storage.ImageContentH\x00R\x05imageB\t\n" +
+	"\acontent\"\xd0\x01\n" +
+	"\vTextContent\x12#\n" +
+	"\roriginal_text\x18\x01 \x01(\tR\foriginalText\x12W\n" +
+	"\x11analysis_metadata\x18\x02 \x03(\v2*.storage.TextContent.AnalysisMetadataEntryR\x10analysisMetadata\x1aC\n" +
+	"\x15AnalysisMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xe1\x01\n" +
+	"\fImageContent\x12\x1b\n" +
+	"\timage_url\x18\x01 \x01(\tR\bimageUrl\x12X\n" +
+	"\x11analysis_metadata\x18\x02 \x03(\v2+.storage.ImageContent.AnalysisMetadataEntryR\x10analysisMetadata\x12\x15\n" +
+	"\x06s3_key\x18\x03 \x01(\tR\x05s3Key\x1aC\n" +
+	"\x15AnalysisMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc0\x01\n" +
+	"\x16RegisterContentRequest\x12\x1d\n" +
+	"\n" +
+	"content_id\x18\x01 \x01(\tR\tcontentId\x12(\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x14.storage.ContentTypeR\x04type\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06bucket\x18\x04 \x01(\tR\x06bucket\x12\x10\n" +
+	"\x03key\x18\x05 \x01(\tR\x03key\x12\x1a\n" +
+	"\bchecksum\x18\x06 \x01(\tR\bchecksum\"3\n" +
+	"\x17RegisterContentResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"T\n" +
+	"\x1bRegisterContentBatchRequest\x125\n" +
+	"\x05items\x18\x01 \x03(\v2\x1f.storage.RegisterContentRequestR\x05items\">\n" +
+	"\x1cRegisterContentBatchResponse\x12\x1e\n" +
+	"\n" +
+	"registered\x18\x01 \x01(\x05R\n" +
+	"registered\"\xe8\x02\n" +
+	"\x12ListContentRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x126\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x19.storage.ProcessingStatusH\x00R\x06status\x88\x01\x01\x12-\n" +
+	"\x04type\x18\x05 \x01(\x0e2\x14.storage.ContentTypeH\x01R\x04type\x88\x01\x01\x12?\n" +
+	"\rcreated_after\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBeforeB\t\n" +
+	"\a_statusB\a\n" +
+	"\x05_type\"\x82\x02\n" +
+	"\x0eContentSummary\x12\x1d\n" +
+	"\n" +
+	"content_id\x18\x01 \x01(\tR\tcontentId\x12(\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x14.storage.ContentTypeR\x04type\x121\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x19.storage.ProcessingStatusR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"l\n" +
+	"\x13ListContentResponse\x12-\n" +
+	"\x05items\x18\x01 \x03(\v2\x17.storage.ContentSummaryR\x05items\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"N\n" +
+	"\x14DeleteContentRequest\x12\x1d\n" +
+	"\n" +
+	"content_id\x18\x01 \x01(\tR\tcontentId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"T\n" +
+	"\x15DeleteContentResponse\x12;\n" +
+	"\vpurge_after\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"purgeAfter\"O\n" +
+	"\x15RestoreContentRequest\x12\x1d\n" +
+	"\n" +
+	"content_id\x18\x01 \x01(\tR\tcontentId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"2\n" +
+	"\x16RestoreContentResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"M\n" +
+	"\x13WatchContentRequest\x12\x1d\n" +
+	"\n" +
+	"content_id\x18\x01 \x01(\tR\tcontentId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x91\x03\n" +
+	"\fContentEvent\x12\x1d\n" +
+	"\n" +
+	"content_id\x18\x01 \x01(\tR\tcontentId\x12(\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x14.storage.ContentTypeR\x04type\x121\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x19.storage.ProcessingStatusR\x06status\x12X\n" +
+	"\x11analysis_metadata\x18\x04 \x03(\v2+.storage.ContentEvent.AnalysisMetadataEntryR\x10analysisMetadata\x12)\n" +
+	"\x10original_content\x18\x05 \x01(\tR\x0foriginalContent\x12;\n" +
+	"\voccurred_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x1aC\n" +
+	"\x15AnalysisMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"f\n" +
+	"\aWebhook\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"Y\n" +
+	"\x14CreateWebhookRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x16\n" +
+	"\x06secret\x18\x03 \x01(\tR\x06secret\"[\n" +
+	"\x15CreateWebhookResponse\x12*\n" +
+	"\awebhook\x18\x01 \x01(\v2\x10.storage.WebhookR\awebhook\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\".\n" +
+	"\x13ListWebhooksRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"D\n" +
+	"\x14ListWebhooksResponse\x12,\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\x10.storage.WebhookR\bwebhooks\"N\n" +
+	"\x14DeleteWebhookRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\tR\twebhookId\"1\n" +
+	"\x15DeleteWebhookResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xa7\x03\n" +
+	"\x0fWebhookDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\tR\twebhookId\x12\x1d\n" +
+	"\n" +
+	"content_id\x18\x03 \x01(\tR\tcontentId\x12\x14\n" +
+	"\x05event\x18\x04 \x01(\tR\x05event\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1a\n" +
+	"\battempts\x18\x06 \x01(\x05R\battempts\x12#\n" +
+	"\rresponse_code\x18\a \x01(\x05R\fresponseCode\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\b \x01(\tR\tlastError\x12B\n" +
+	"\x0fnext_attempt_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\rnextAttemptAt\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x84\x01\n" +
+	"\x1cListWebhookDeliveriesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\tR\twebhookId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"Y\n" +
+	"\x1dListWebhookDeliveriesResponse\x128\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x18.storage.WebhookDeliveryR\n" +
+	"deliveries\"r\n" +
+	"\x17RedeliverWebhookRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\tR\twebhookId\x12\x1f\n" +
+	"\vdelivery_id\x18\x03 \x01(\tR\n" +
+	"deliveryId\"P\n" +
+	"\x18RedeliverWebhookResponse\x124\n" +
+	"\bdelivery\x18\x01 \x01(\v2\x18.storage.WebhookDeliveryR\bdelivery*\"\n" +
+	"\vContentType\x12\b\n" +
+	"\x04TEXT\x10\x00\x12\t\n" +
+	"\x05IMAGE\x10\x01*J\n" +
+	"\x10ProcessingStatus\x12\v\n" +
+	"\aPENDING\x10\x00\x12\x0e\n" +
+	"\n" +
+	"PROCESSING\x10\x01\x12\r\n" +
+	"\tCOMPLETED\x10\x02\x12\n" +
+	"\n" +
+	"\x06FAILED\x10\x032\xee\a\n" +
+	"\x0eStorageService\x12?\n" +
+	"\n" +
+	"GetContent\x12\x17.storage.ContentRequest\x1a\x18.storage.ContentResponse\x12T\n" +
+	"\x0fRegisterContent\x12\x1f.storage.RegisterContentRequest\x1a .storage.RegisterContentResponse\x12c\n" +
+	"\x14RegisterContentBatch\x12$.storage.RegisterContentBatchRequest\x1a%.storage.RegisterContentBatchResponse\x12H\n" +
+	"\vListContent\x12\x1b.storage.ListContentRequest\x1a\x1c.storage.ListContentResponse\x12N\n" +
+	"\rDeleteContent\x12\x1d.storage.DeleteContentRequest\x1a\x1e.storage.DeleteContentResponse\x12Q\n" +
+	"\x0eRestoreContent\x12\x1e.storage.RestoreContentRequest\x1a\x1f.storage.RestoreContentResponse\x12E\n" +
+	"\fWatchContent\x12\x1c.storage.WatchContentRequest\x1a\x15.storage.ContentEvent0\x01\x12N\n" +
+	"\rCreateWebhook\x12\x1d.storage.CreateWebhookRequest\x1a\x1e.storage.CreateWebhookResponse\x12K\n" +
+	"\fListWebhooks\x12\x1c.storage.ListWebhooksRequest\x1a\x1d.storage.ListWebhooksResponse\x12N\n" +
+	"\rDeleteWebhook\x12\x1d.storage.DeleteWebhookRequest\x1a\x1e.storage.DeleteWebhookResponse\x12f\n" +
+	"\x15ListWebhookDeliveries\x12%.storage.ListWebhookDeliveriesRequest\x1a&.storage.ListWebhookDeliveriesResponse\x12W\n" +
+	"\x10RedeliverWebhook\x12 .storage.RedeliverWebhookRequest\x1a!.storage.RedeliverWebhookResponseB\x17Z\x15storage.v1;storagev1 b\x06proto3"
+
+var (
+	file_storage_storage_proto_rawDescOnce sync.Once
+	file_storage_storage_proto_rawDescData []byte
+)
+
+func file_storage_storage_proto_rawDescGZIP() []byte {
+	file_storage_storage_proto_rawDescOnce.Do(func() {
+		file_storage_storage_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_storage_storage_proto_rawDesc), len(file_storage_storage_proto_rawDesc)))
+	})
+	return file_storage_storage_proto_rawDescData
+}
+
+var file_storage_storage_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_storage_storage_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
+var file_storage_storage_proto_goTypes = []any{
+	(ContentType)(0),                      // 0: storage.ContentType
+	(ProcessingStatus)(0),                 // 1: storage.ProcessingStatus
+	(*ContentRequest)(nil),                // 2: storage.ContentRequest
+	(*ContentResponse)(nil),               // 3: storage.ContentResponse
+	(*TextContent)(nil),                   // 4: storage.TextContent
+	(*ImageContent)(nil),                  // 5: storage.ImageContent
+	(*RegisterContentRequest)(nil),        // 6: storage.RegisterContentRequest
+	(*RegisterContentResponse)(nil),       // 7: storage.RegisterContentResponse
+	(*RegisterContentBatchRequest)(nil),   // 8: storage.RegisterContentBatchRequest
+	(*RegisterContentBatchResponse)(nil),  // 9: storage.RegisterContentBatchResponse
+	(*ListContentRequest)(nil),            // 10: storage.ListContentRequest
+	(*ContentSummary)(nil),                // 11: storage.ContentSummary
+	(*ListContentResponse)(nil),           // 12: storage.ListContentResponse
+	(*DeleteContentRequest)(nil),          // 13: storage.DeleteContentRequest
+	(*DeleteContentResponse)(nil),         // 14: storage.DeleteContentResponse
+	(*RestoreContentRequest)(nil),         // 15: storage.RestoreContentRequest
+	(*RestoreContentResponse)(nil),        // 16: storage.RestoreContentResponse
+	(*WatchContentRequest)(nil),           // 17: storage.WatchContentRequest
+	(*ContentEvent)(nil),                  // 18: storage.ContentEvent
+	(*Webhook)(nil),                       // 19: storage.Webhook
+	(*CreateWebhookRequest)(nil),          // 20: storage.CreateWebhookRequest
+	(*CreateWebhookResponse)(nil),         // 21: storage.CreateWebhookResponse
+	(*ListWebhooksRequest)(nil),           // 22: storage.ListWebhooksRequest
+	(*ListWebhooksResponse)(nil),          // 23: storage.ListWebhooksResponse
+	(*DeleteWebhookRequest)(nil),          // 24: storage.DeleteWebhookRequest
+	(*DeleteWebhookResponse)(nil),         // 25: storage.DeleteWebhookResponse
+	(*WebhookDelivery)(nil),               // 26: storage.WebhookDelivery
+	(*ListWebhookDeliveriesRequest)(nil),  // 27: storage.ListWebhookDeliveriesRequest
+	(*ListWebhookDeliveriesResponse)(nil), // 28: storage.ListWebhookDeliveriesResponse
+	(*RedeliverWebhookRequest)(nil),       // 29: storage.RedeliverWebhookRequest
+	(*RedeliverWebhookResponse)(nil),      // 30: storage.RedeliverWebhookResponse
+	nil,                                   // 31: storage.TextContent.AnalysisMetadataEntry
+	nil,                                   // 32: storage.ImageContent.AnalysisMetadataEntry
+	nil,                                   // 33: storage.ContentEvent.AnalysisMetadataEntry
+	(*timestamppb.Timestamp)(nil),         // 34: google.protobuf.Timestamp
+}
+var file_storage_storage_proto_depIdxs = []int32{
+	0,  // 0: storage.ContentResponse.type:type_name -> storage.ContentType
+	1,  // 1: storage.ContentResponse.status:type_name -> storage.ProcessingStatus
+	4,  // 2: storage.ContentResponse.text:type_name -> storage.TextContent
+	5,  // 3: storage.ContentResponse.image:type_name -> storage.ImageContent
+	31, // 4: storage.TextContent.analysis_metadata:type_name -> storage.TextContent.AnalysisMetadataEntry
+	32, // 5: storage.ImageContent.analysis_metadata:type_name -> storage.ImageContent.AnalysisMetadataEntry
+	0,  // 6: storage.RegisterContentRequest.type:type_name -> storage.ContentType
+	6,  // 7: storage.RegisterContentBatchRequest.items:type_name -> storage.RegisterContentRequest
+	1,  // 8: storage.ListContentRequest.status:type_name -> storage.ProcessingStatus
+	0,  // 9: storage.ListContentRequest.type:type_name -> storage.ContentType
+	34, // 10: storage.ListContentRequest.created_after:type_name -> google.protobuf.Timestamp
+	34, // 11: storage.ListContentRequest.created_before:type_name -> google.protobuf.Timestamp
+	0,  // 12: storage.ContentSummary.type:type_name -> storage.ContentType
+	1,  // 13: storage.ContentSummary.status:type_name -> storage.ProcessingStatus
+	34, // 14: storage.ContentSummary.created_at:type_name -> google.protobuf.Timestamp
+	34, // 15: storage.ContentSummary.updated_at:type_name -> google.protobuf.Timestamp
+	11, // 16: storage.ListContentResponse.items:type_name -> storage.ContentSummary
+	34, // 17: storage.DeleteContentResponse.purge_after:type_name -> google.protobuf.Timestamp
+	0,  // 18: storage.ContentEvent.type:type_name -> storage.ContentType
+	1,  // 19: storage.ContentEvent.status:type_name -> storage.ProcessingStatus
+	33, // 20: storage.ContentEvent.analysis_metadata:type_name -> storage.ContentEvent.AnalysisMetadataEntry
+	34, // 21: storage.ContentEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	34, // 22: storage.Webhook.created_at:type_name -> google.protobuf.Timestamp
+	19, // 23: storage.CreateWebhookResponse.webhook:type_name -> storage.Webhook
+	19, // 24: storage.ListWebhooksResponse.webhooks:type_name -> storage.Webhook
+	34, // 25: storage.WebhookDelivery.next_attempt_at:type_name -> google.protobuf.Timestamp
+	34, // 26: storage.WebhookDelivery.created_at:type_name -> google.protobuf.Timestamp
+	34, // 27: storage.WebhookDelivery.updated_at:type_name -> google.protobuf.Timestamp
+	26, // 28: storage.ListWebhookDeliveriesResponse.deliveries:type_name -> storage.WebhookDelivery
+	26, // 29: storage.RedeliverWebhookResponse.delivery:type_name -> storage.WebhookDelivery
+	2,  // 30: storage.StorageService.GetContent:input_type -> storage.ContentRequest
+	6,  // 31: storage.StorageService.RegisterContent:input_type -> storage.RegisterContentRequest
+	8,  // 32: storage.StorageService.RegisterContentBatch:input_type -> storage.RegisterContentBatchRequest
+	10, // 33: storage.StorageService.ListContent:input_type -> storage.ListContentRequest
+	13, // 34: storage.StorageService.DeleteContent:input_type -> storage.DeleteContentRequest
+	15, // 35: storage.StorageService.RestoreContent:input_type -> storage.RestoreContentRequest
+	17, // 36: storage.StorageService.WatchContent:input_type -> storage.WatchContentRequest
+	20, // 37: storage.StorageService.CreateWebhook:input_type -> storage.CreateWebhookRequest
+	22, // 38: storage.StorageService.ListWebhooks:input_type -> storage.ListWebhooksRequest
+	24, // 39: storage.StorageService.DeleteWebhook:input_type -> storage.DeleteWebhookRequest
+	27, // 40: storage.StorageService.ListWebhookDeliveries:input_type -> storage.ListWebhookDeliveriesRequest
+	29, // 41: storage.StorageService.RedeliverWebhook:input_type -> storage.RedeliverWebhookRequest
+	3,  // 42: storage.StorageService.GetContent:output_type -> storage.ContentResponse
+	7,  // 43: storage.StorageService.RegisterContent:output_type -> storage.RegisterContentResponse
+	9,  // 44: storage.StorageService.RegisterContentBatch:output_type -> storage.RegisterContentBatchResponse
+	12, // 45: storage.StorageService.ListContent:output_type -> storage.ListContentResponse
+	14, // 46: storage.StorageService.DeleteContent:output_type -> storage.DeleteContentResponse
+	16, // 47: storage.StorageService.RestoreContent:output_type -> storage.RestoreContentResponse
+	18, // 48: storage.StorageService.WatchContent:output_type -> storage.ContentEvent
+	21, // 49: storage.StorageService.CreateWebhook:output_type -> storage.CreateWebhookResponse
+	23, // 50: storage.StorageService.ListWebhooks:output_type -> storage.ListWebhooksResponse
+	25, // 51: storage.StorageService.DeleteWebhook:output_type -> storage.DeleteWebhookResponse
+	28, // 52: storage.StorageService.ListWebhookDeliveries:output_type -> storage.ListWebhookDeliveriesResponse
+	30, // 53: storage.StorageService.RedeliverWebhook:output_type -> storage.RedeliverWebhookResponse
+	42, // [42:54] is the sub-list for method output_type
+	30, // [30:42] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
+}
+
+func init() { file_storage_storage_proto_init() }
+func file_storage_storage_proto_init() {
+	if File_storage_storage_proto != nil {
+		return
+	}
+	file_storage_storage_proto_msgTypes[1].OneofWrappers = []any{
+		(*ContentResponse_Text)(nil),
+		(*ContentResponse_Image)(nil),
+	}
+	file_storage_storage_proto_msgTypes[8].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_storage_storage_proto_rawDesc), len(file_storage_storage_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   32,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_storage_storage_proto_goTypes,
+		DependencyIndexes: file_storage_storage_proto_depIdxs,
+		EnumInfos:         file_storage_storage_proto_enumTypes,
+		MessageInfos:      file_storage_storage_proto_msgTypes,
+	}.Build()
+	File_storage_storage_proto = out.File
+	file_storage_storage_proto_goTypes = nil
+	file_storage_storage_proto_depIdxs = nil
+}
@@ -0,0 +1,3 @@
+module github.com/deeelis/storage-protos
+
+go 1.21
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"github.com/deeelis/platform/tracing"
 	_ "github.com/lib/pq"
 	"log/slog"
 	"os"
@@ -9,6 +11,7 @@ import (
 	"storage_service/internal/config"
 	"storage_service/logger"
 	"syscall"
+	"time"
 )
 
 func main() {
@@ -23,6 +26,12 @@ func main() {
 		slog.String("env", cfg.Env),
 	)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), "storage_service", &cfg.Tracing)
+	if err != nil {
+		log.Error("failed to set up tracing", logger.Err(err))
+		os.Exit(1)
+	}
+
 	application, err := app.NewApp(log, cfg)
 	if err != nil {
 		log.Error("failed to create app", logger.Err(err))
@@ -37,6 +46,12 @@ func main() {
 
 	log.Info("shutting down server...")
 	application.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error("failed to flush traces", logger.Err(err))
+	}
 	log.Info("server stopped")
 
 }
@@ -3,11 +3,16 @@ package app
 import (
 	"context"
 	"fmt"
+	"github.com/deeelis/platform/health"
 	"log/slog"
 	"net"
 	grpc2 "storage_service/internal/app/grpc"
 	"storage_service/internal/config"
 	"storage_service/internal/consumer/kafka"
+	"storage_service/internal/dispatcher"
+	"storage_service/internal/metrics"
+	"storage_service/internal/purger"
+	"time"
 )
 
 type App struct {
@@ -15,7 +20,12 @@ type App struct {
 	log           *slog.Logger
 	ImageConsumer *kafka.ImageConsumer
 	TextConsumer  *kafka.TextConsumer
+	Purger        *purger.Purger
+	Dispatcher    *dispatcher.Dispatcher
 	gRPCServer    *grpc2.Server
+	metrics       *metrics.Server
+	health        *health.Checker
+	stopHealth    context.CancelFunc
 }
 
 func NewApp(log *slog.Logger, cfg *config.Config) (*App, error) {
@@ -33,12 +43,31 @@ func NewApp(log *slog.Logger, cfg *config.Config) (*App, error) {
 	if err != nil {
 		return nil, err
 	}
+	p, err := purger.NewPurger(ctx, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+	d, err := dispatcher.NewDispatcher(ctx, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+	checker, err := newHealthChecker(ctx, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+	metricsServer := metrics.NewServer(&cfg.Metrics, log)
+	metricsServer.Handle("/healthz", checker.LivenessHandler())
+	metricsServer.Handle("/readyz", checker.ReadinessHandler())
 	return &App{
 		ImageConsumer: img,
 		TextConsumer:  txt,
+		Purger:        p,
+		Dispatcher:    d,
 		cfg:           cfg,
 		log:           log,
 		gRPCServer:    s,
+		metrics:       metricsServer,
+		health:        checker,
 	}, nil
 }
 
@@ -60,7 +89,11 @@ func (a *App) Run() {
 			a.log.Error(fmt.Errorf("%s: %w", op, err).Error())
 		}
 	}()
+	go a.metrics.Start()
 	ctx := context.Background()
+	healthCtx, stopHealth := context.WithCancel(ctx)
+	a.stopHealth = stopHealth
+	go a.health.Watch(healthCtx, a.cfg.Health.Interval, a.gRPCServer.SetReady)
 	go func() {
 		err := a.ImageConsumer.ConsumeImages(ctx)
 		if err != nil {
@@ -73,10 +106,16 @@ func (a *App) Run() {
 			a.log.Error(err.Error())
 		}
 	}()
+	go a.Purger.Run(ctx)
+	go a.Dispatcher.Run(ctx)
 }
 
 func (a *App) Stop() {
 	a.log.Info("stopping app")
+	if a.stopHealth != nil {
+		a.stopHealth()
+	}
+	a.gRPCServer.Stop()
 	err := a.TextConsumer.Close()
 	if err != nil {
 		a.log.Error(err.Error())
@@ -85,4 +124,15 @@ func (a *App) Stop() {
 	if err != nil {
 		a.log.Error(err.Error())
 	}
+	err = a.Purger.Close()
+	if err != nil {
+		a.log.Error(err.Error())
+	}
+	err = a.Dispatcher.Close()
+	if err != nil {
+		a.log.Error(err.Error())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	a.metrics.Stop(ctx)
 }
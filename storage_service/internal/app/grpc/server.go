@@ -1,35 +1,55 @@
 package grpc
 
 import (
+	"github.com/deeelis/platform/requestid"
 	storage "github.com/deeelis/storage-protos/gen/go/storage"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/recovery"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"log/slog"
 	"net"
 	"storage_service/internal/config"
 	grpc2 "storage_service/internal/controllers/grpc"
+	"storage_service/internal/metrics"
 )
 
 type Server struct {
 	cfg    *config.Config
 	log    *slog.Logger
 	Server *grpc.Server
+	health *health.Server
 }
 
 func NewServer(cfg *config.Config, log *slog.Logger) (*Server, error) {
-	s := grpc.NewServer(grpc.ChainUnaryInterceptor(
-		recovery.UnaryServerInterceptor()))
+	s := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler(
+			otelgrpc.WithFilter(filters.Not(filters.HealthCheck())))),
+		grpc.ChainUnaryInterceptor(
+			requestid.UnaryServerInterceptor(),
+			metrics.UnaryServerInterceptor(),
+			recovery.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(
+			requestid.StreamServerInterceptor(),
+			metrics.StreamServerInterceptor()))
 	storageController, err := grpc2.NewStorageController(cfg, log)
 	if err != nil {
 		log.Error("creating new server error", err.Error())
 		return nil, err
 	}
 	storage.RegisterStorageServiceServer(s, storageController)
+	// до первой успешной проверки зависимостей сервис не готов
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthServer.SetServingStatus(storage.StorageService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(s, healthServer)
 	if cfg.Env == "local" || cfg.Env == "dev" {
 		reflection.Register(s)
 	}
-	return &Server{Server: s, log: log}, nil
+	return &Server{Server: s, health: healthServer, log: log}, nil
 }
 
 func (s *Server) Start(lis net.Listener) error {
@@ -37,7 +57,18 @@ func (s *Server) Start(lis net.Listener) error {
 	return s.Server.Serve(lis)
 }
 
+// SetReady переключает статус grpc.health.v1 для всего сервера и StorageService.
+func (s *Server) SetReady(ready bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if ready {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	s.health.SetServingStatus("", status)
+	s.health.SetServingStatus(storage.StorageService_ServiceDesc.ServiceName, status)
+}
+
 func (s *Server) Stop() {
 	s.log.Info("stop server")
+	s.health.Shutdown()
 	s.Server.GracefulStop()
 }
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"context"
+	"github.com/deeelis/platform/health"
+	"log/slog"
+	"storage_service/internal/config"
+	kafka2 "storage_service/internal/kafka"
+	"storage_service/internal/repositories/cache"
+	repos "storage_service/internal/repositories/repos/postgres"
+	"storage_service/internal/repositories/s3"
+)
+
+// newHealthChecker собирает проверки зависимостей на собственных клиентах,
+// чтобы проба не конкурировала за соединения с консьюмерами и gRPC-хендлерами.
+func newHealthChecker(ctx context.Context, cfg *config.Config, log *slog.Logger) (*health.Checker, error) {
+	db, err := repos.NewPostgresContentRepository(ctx, &cfg.Repo, log)
+	if err != nil {
+		return nil, err
+	}
+	redis, err := cache.NewRedisCache(&cfg.Cache, log)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := s3.NewS3ImageStorage(&cfg.S3, log)
+	if err != nil {
+		return nil, err
+	}
+
+	checker := health.NewChecker(cfg.Health.Timeout, log)
+	checker.Register("postgres", db.Ping)
+	checker.Register("redis", redis.Ping)
+	checker.Register("minio", objects.Ping)
+	checker.Register("kafka", func(ctx context.Context) error {
+		return kafka2.Ping(ctx, cfg.Kafka.Brokers)
+	})
+	return checker, nil
+}
@@ -3,18 +3,24 @@ package config
 import (
 	"errors"
 	"flag"
+	"github.com/deeelis/platform/tracing"
 	"github.com/ilyakaznacheev/cleanenv"
 	"os"
 	"time"
 )
 
 type Config struct {
-	Env   string      `yaml:"env"`
-	GRPC  GRPCConfig  `yaml:"grpc"`
-	Repo  RepoConfig  `yaml:"repo"`
-	Cache CacheConfig `yaml:"cache"`
-	S3    S3Config    `yaml:"s3"`
-	Kafka KafkaConfig `yaml:"kafka"`
+	Env      string         `yaml:"env"`
+	GRPC     GRPCConfig     `yaml:"grpc"`
+	Repo     RepoConfig     `yaml:"repo"`
+	Cache    CacheConfig    `yaml:"cache"`
+	S3       S3Config       `yaml:"s3"`
+	Kafka    KafkaConfig    `yaml:"kafka"`
+	Purge    PurgeConfig    `yaml:"purge"`
+	Webhooks WebhooksConfig `yaml:"webhooks"`
+	Metrics  MetricsConfig  `yaml:"metrics"`
+	Health   HealthConfig   `yaml:"health"`
+	Tracing  TracingConfig  `yaml:"tracing"`
 }
 
 type CacheConfig struct {
@@ -30,7 +36,6 @@ type S3Config struct {
 	Bucket    string `yaml:"bucket"`
 	AccessKey string `yaml:"access_key"`
 	SecretKey string `yaml:"secret_key"`
-	URL       string `yaml:"url"`
 }
 
 type KafkaConfig struct {
@@ -40,6 +45,36 @@ type KafkaConfig struct {
 	ErrorTopic string   `yaml:"error_topic" env-default:"content.dead_letter"`
 }
 
+type PurgeConfig struct {
+	GracePeriod time.Duration `yaml:"grace_period" env-default:"72h"`
+	Interval    time.Duration `yaml:"interval" env-default:"10m"`
+	BatchSize   int           `yaml:"batch_size" env-default:"100"`
+}
+
+// WebhooksConfig: AllowedNetworks — сети в нотации CIDR, куда можно слать
+// вебхуки, хотя адреса в них не публичные (например, приёмник на стенде).
+type WebhooksConfig struct {
+	MaxAttempts     int           `yaml:"max_attempts" env-default:"8"`
+	InitialBackoff  time.Duration `yaml:"initial_backoff" env-default:"10s"`
+	MaxBackoff      time.Duration `yaml:"max_backoff" env-default:"1h"`
+	Timeout         time.Duration `yaml:"timeout" env-default:"10s"`
+	PollInterval    time.Duration `yaml:"poll_interval" env-default:"5s"`
+	BatchSize       int           `yaml:"batch_size" env-default:"50"`
+	AllowedNetworks []string      `yaml:"allowed_networks"`
+}
+
+type MetricsConfig struct {
+	Port int `yaml:"port" env-default:"9090"`
+}
+
+type HealthConfig struct {
+	Timeout  time.Duration `yaml:"timeout" env-default:"2s"`
+	Interval time.Duration `yaml:"interval" env-default:"10s"`
+}
+
+// TracingConfig — настройки экспорта трейсов, общие для всех сервисов
+type TracingConfig = tracing.Config
+
 type GRPCConfig struct {
 	Port    int           `yaml:"port"`
 	Timeout time.Duration `yaml:"timeout"`
@@ -3,13 +3,19 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"github.com/deeelis/platform/requestid"
+	"github.com/deeelis/platform/tracing"
+	"github.com/deeelis/platform/tracing/kafkatrace"
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
 	"log/slog"
 	"storage_service/internal/config"
 	"storage_service/internal/domain/models"
 	"storage_service/internal/domain/repositories"
 	kafka2 "storage_service/internal/kafka"
+	"storage_service/internal/metrics"
 	services "storage_service/internal/usecases"
+	"storage_service/logger"
 	"time"
 )
 
@@ -79,44 +85,64 @@ func (c *ImageConsumer) ConsumeImages(ctx context.Context) error {
 			return err
 		}
 
-		var content models.ImageKafkaMessage
-		if err := json.Unmarshal(msg, &content); err != nil {
-			c.log.Error("failed to unmarshal message",
-				err.Error(),
-			)
-			continue
-		}
+		c.handleImage(ctx, log, &msg)
+	}
+}
 
-		c.log.Debug("received message",
-			"image_id", content.ID,
-		)
+// handleImage обрабатывает одно сообщение в спане, продолжающем трейс отправителя.
+func (c *ImageConsumer) handleImage(ctx context.Context, log *slog.Logger, msg *kafka.Message) {
+	startTime := time.Now()
+	ctx, span := kafkatrace.StartConsumer(ctx, c.cfg.Kafka.ImageTopic, msg)
+	var err error
+	defer func() { tracing.End(span, err) }()
+	requestID := requestid.FromMessage(msg)
+	ctx = requestid.WithContext(ctx, requestID)
+	log = log.With(
+		slog.String("trace_id", tracing.TraceID(ctx)),
+		slog.String("request_id", requestID))
+
+	var content models.ImageKafkaMessage
+	if err = json.Unmarshal(msg.Value, &content); err != nil {
+		log.Error("failed to unmarshal message", logger.Err(err))
+		metrics.ObserveConsumed(c.cfg.Kafka.ImageTopic, metrics.ResultInvalid, startTime)
+		return
+	}
+	span.SetAttributes(attribute.String("content.id", content.ID))
 
-		imageMessage := &models.ImageMessage{
-			ID:     content.ID,
-			UserID: content.UserID,
-			Data:   content.Data,
-			NsfwScores: models.NsfwScoresResult{
-				Drawings: content.NsfwScores.Drawings,
-				Hentai:   content.NsfwScores.Hentai,
-				Neutral:  content.NsfwScores.Neutral,
-				Porn:     content.NsfwScores.Porn,
-				Sexy:     content.NsfwScores.Sexy,
-			},
-		}
+	log.Debug("received message",
+		slog.String("image_id", content.ID),
+	)
 
-		err = c.usecase.ProcessImageMessage(ctx, imageMessage)
-		if err != nil {
-			c.log.Error("failed to process image",
-				"image_id", content.ID,
-				err.Error(),
-			)
-			continue
-		}
+	imageMessage := &models.ImageMessage{
+		ID:       content.ID,
+		UserID:   content.UserID,
+		Bucket:   content.Bucket,
+		Key:      content.Key,
+		Checksum: content.Checksum,
+		Size:     content.Size,
+		IsNsfw:   content.IsNsfw,
+		Error:    content.Error,
+		NsfwScores: models.NsfwScoresResult{
+			Drawings: content.NsfwScores.Drawings,
+			Hentai:   content.NsfwScores.Hentai,
+			Neutral:  content.NsfwScores.Neutral,
+			Porn:     content.NsfwScores.Porn,
+			Sexy:     content.NsfwScores.Sexy,
+		},
+	}
 
-		log.Info("content successfully saved",
-			//slog.String("topic", content.Data),
-			slog.String("content_id", content.ID))
+	if err = c.usecase.ProcessImageMessage(ctx, imageMessage); err != nil {
+		log.Error("failed to process image",
+			slog.String("image_id", content.ID),
+			logger.Err(err),
+		)
+		metrics.ObserveConsumed(c.cfg.Kafka.ImageTopic, metrics.ResultError, startTime)
+		return
 	}
+
+	metrics.ObserveConsumed(c.cfg.Kafka.ImageTopic, metrics.ResultOK, startTime)
+	log.Info("content successfully saved",
+		slog.String("content_id", content.ID))
 }
 
 func (c *ImageConsumer) Close() error {
@@ -3,13 +3,19 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"github.com/deeelis/platform/requestid"
+	"github.com/deeelis/platform/tracing"
+	"github.com/deeelis/platform/tracing/kafkatrace"
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
 	"log/slog"
 	"storage_service/internal/config"
 	"storage_service/internal/domain/models"
 	"storage_service/internal/domain/repositories"
 	kafka2 "storage_service/internal/kafka"
+	"storage_service/internal/metrics"
 	services "storage_service/internal/usecases"
+	"storage_service/logger"
 	"strconv"
 	"time"
 )
@@ -80,42 +86,57 @@ func (c *TextConsumer) ConsumeText(ctx context.Context) error {
 			return err
 		}
 
-		var content models.TextMessage
-		var mes models.TextKafkaMessage
-		if err := json.Unmarshal(msg, &mes); err != nil {
-			c.log.Error("failed to unmarshal message",
-				err.Error(),
-			)
-			continue
-		}
-
-		content.ID = mes.ID
-		content.Content = mes.Data
-		content.UserID = mes.UserID
-		metadata := make(map[string]string)
-		metadata["is_approved"] = strconv.FormatBool(mes.Analysis.IsApproved)
-		metadata["is_spam"] = strconv.FormatBool(mes.Analysis.IsSpam)
-		metadata["has_sensitive"] = strconv.FormatBool(mes.Analysis.HasSensitive)
-		metadata["language"] = mes.Analysis.Language
-
-		content.Analysis = metadata
-		c.log.Debug("received message",
-			"text_id", content.ID,
-		)
+		c.handleText(ctx, log, &msg)
+	}
+}
 
-		err = c.usecase.ProcessTextMessage(ctx, &content)
-		if err != nil {
-			c.log.Error("failed to process text",
-				"text_id", content.ID,
-				err.Error(),
-			)
-			continue
-		}
+// handleText обрабатывает одно сообщение в спане, продолжающем трейс анализатора.
+func (c *TextConsumer) handleText(ctx context.Context, log *slog.Logger, msg *kafka.Message) {
+	startTime := time.Now()
+	ctx, span := kafkatrace.StartConsumer(ctx, c.cfg.Kafka.TextTopic, msg)
+	var err error
+	defer func() { tracing.End(span, err) }()
+	requestID := requestid.FromMessage(msg)
+	ctx = requestid.WithContext(ctx, requestID)
+	log = log.With(
+		slog.String("trace_id", tracing.TraceID(ctx)),
+		slog.String("request_id", requestID))
+
+	var content models.TextMessage
+	var mes models.TextKafkaMessage
+	if err = json.Unmarshal(msg.Value, &mes); err != nil {
+		log.Error("failed to unmarshal message", logger.Err(err))
+		metrics.ObserveConsumed(c.cfg.Kafka.TextTopic, metrics.ResultInvalid, startTime)
+		return
+	}
+	span.SetAttributes(attribute.String("content.id", mes.ID))
+
+	content.ID = mes.ID
+	content.Content = mes.Data
+	content.UserID = mes.UserID
+	metadata := make(map[string]string)
+	metadata["is_approved"] = strconv.FormatBool(mes.Analysis.IsApproved)
+	metadata["is_spam"] = strconv.FormatBool(mes.Analysis.IsSpam)
+	metadata["has_sensitive"] = strconv.FormatBool(mes.Analysis.HasSensitive)
+	metadata["language"] = mes.Analysis.Language
+
+	content.Analysis = metadata
+	log.Debug("received message",
+		slog.String("text_id", content.ID),
+	)
 
-		log.Info("content successfully saved",
-			slog.String("topic", content.Content),
-			slog.String("content_id", content.ID))
+	if err = c.usecase.ProcessTextMessage(ctx, &content); err != nil {
+		log.Error("failed to process text",
+			slog.String("text_id", content.ID),
+			logger.Err(err),
+		)
+		metrics.ObserveConsumed(c.cfg.Kafka.TextTopic, metrics.ResultError, startTime)
+		return
 	}
+
+	metrics.ObserveConsumed(c.cfg.Kafka.TextTopic, metrics.ResultOK, startTime)
+	log.Info("content successfully saved",
+		slog.String("content_id", content.ID))
 }
 
 func (c *TextConsumer) Close() error {
@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	errorsv1 "github.com/deeelis/errors-protos/gen/go/errors"
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"net"
+	domainerrors "storage_service/internal/domain/errors"
+	"storage_service/logger"
+)
+
+const errorDomain = "storage_service"
+
+// statusError собирает статус gRPC с кодом из общего каталога в google.rpc.ErrorInfo.
+func statusError(c codes.Code, code errorsv1.ErrorCode, msg string) error {
+	st := status.New(c, msg)
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: code.String(),
+		Domain: errorDomain,
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+func invalidArgument(msg string) error {
+	return statusError(codes.InvalidArgument, errorsv1.ErrorCode_INVALID_ARGUMENT, msg)
+}
+
+// toStatusError переводит доменную ошибку в статус gRPC. Текст внутренних
+// ошибок клиенту не отдаётся, только пишется в лог.
+func (c *StorageController) toStatusError(err error, msg string) error {
+	switch {
+	case errors.Is(err, domainerrors.ErrContentNotFound):
+		return statusError(codes.NotFound, errorsv1.ErrorCode_CONTENT_NOT_FOUND, "content not found")
+	case errors.Is(err, domainerrors.ErrAccessDenied):
+		return statusError(codes.PermissionDenied, errorsv1.ErrorCode_CONTENT_ACCESS_DENIED, "content belongs to another user")
+	case errors.Is(err, domainerrors.ErrInvalidContent):
+		return statusError(codes.InvalidArgument, errorsv1.ErrorCode_INVALID_ARGUMENT, err.Error())
+	case errors.Is(err, domainerrors.ErrInvalidWebhook):
+		return statusError(codes.InvalidArgument, errorsv1.ErrorCode_WEBHOOK_INVALID, err.Error())
+	case errors.Is(err, domainerrors.ErrWebhookNotFound):
+		return statusError(codes.NotFound, errorsv1.ErrorCode_WEBHOOK_NOT_FOUND, "webhook not found")
+	case errors.Is(err, domainerrors.ErrNotRedeliverable):
+		return statusError(codes.FailedPrecondition, errorsv1.ErrorCode_DELIVERY_NOT_REDELIVERABLE, err.Error())
+	case isUnavailable(err):
+		c.log.Error(msg, slog.String("code", errorsv1.ErrorCode_UNAVAILABLE.String()), logger.Err(err))
+		return statusError(codes.Unavailable, errorsv1.ErrorCode_UNAVAILABLE, msg)
+	default:
+		c.log.Error(msg, slog.String("code", errorsv1.ErrorCode_INTERNAL.String()), logger.Err(err))
+		return statusError(codes.Internal, errorsv1.ErrorCode_INTERNAL, msg)
+	}
+}
+
+// isUnavailable отличает недоступность базы от прочих ошибок: такой запрос можно повторить.
+func isUnavailable(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// 08 — connection exception, 57P — сервер БД останавливается или ещё не готов
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code.Class() == "08" || pqErr.Code.Class() == "57"
+	}
+	return false
+}
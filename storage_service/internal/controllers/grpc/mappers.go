@@ -0,0 +1,117 @@
+package grpc
+
+import (
+	"encoding/base64"
+	"fmt"
+	storage "github.com/deeelis/storage-protos/gen/go/storage"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"storage_service/internal/domain/models"
+	"strings"
+	"time"
+)
+
+func toProtoStatus(s models.ProcessingStatus) (storage.ProcessingStatus, error) {
+	switch s {
+	case models.StatusPending:
+		return storage.ProcessingStatus_PENDING, nil
+	case models.StatusProcessing:
+		return storage.ProcessingStatus_PROCESSING, nil
+	case models.StatusCompleted:
+		return storage.ProcessingStatus_COMPLETED, nil
+	case models.StatusFailed:
+		return storage.ProcessingStatus_FAILED, nil
+	default:
+		return 0, fmt.Errorf("unknown processing status: %v", s)
+	}
+}
+
+func fromProtoStatus(s storage.ProcessingStatus) (models.ProcessingStatus, error) {
+	switch s {
+	case storage.ProcessingStatus_PENDING:
+		return models.StatusPending, nil
+	case storage.ProcessingStatus_PROCESSING:
+		return models.StatusProcessing, nil
+	case storage.ProcessingStatus_COMPLETED:
+		return models.StatusCompleted, nil
+	case storage.ProcessingStatus_FAILED:
+		return models.StatusFailed, nil
+	default:
+		return "", fmt.Errorf("unknown processing status: %v", s)
+	}
+}
+
+func toProtoType(t models.ContentType) storage.ContentType {
+	return storage.ContentType(storage.ContentType_value[strings.ToUpper(string(t))])
+}
+
+func fromProtoType(t storage.ContentType) models.ContentType {
+	if t == storage.ContentType_IMAGE {
+		return models.ContentTypeImage
+	}
+	return models.ContentTypeText
+}
+
+// Токен страницы — непрозрачная для клиента пара (created_at, id) последней записи.
+func encodePageToken(cursor *models.ContentCursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + cursor.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodePageToken(token string) (*models.ContentCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ContentCursor{CreatedAt: t, ID: id}, nil
+}
+
+func toProtoWebhook(w *models.Webhook) *storage.Webhook {
+	return &storage.Webhook{
+		Id:        w.ID,
+		Url:       w.URL,
+		CreatedAt: timestamppb.New(w.CreatedAt),
+	}
+}
+
+func toProtoDelivery(d *models.WebhookDelivery) *storage.WebhookDelivery {
+	return &storage.WebhookDelivery{
+		Id:            d.ID,
+		WebhookId:     d.WebhookID,
+		ContentId:     d.ContentID,
+		Event:         d.Event,
+		Status:        string(d.Status),
+		Attempts:      int32(d.Attempts),
+		ResponseCode:  int32(d.ResponseCode),
+		LastError:     d.LastError,
+		NextAttemptAt: timestamppb.New(d.NextAttemptAt),
+		CreatedAt:     timestamppb.New(d.CreatedAt),
+		UpdatedAt:     timestamppb.New(d.UpdatedAt),
+	}
+}
+
+func fromRegisterRequest(req *storage.RegisterContentRequest) *models.Content {
+	content := &models.Content{
+		ID:     req.ContentId,
+		UserID: req.UserId,
+		Type:   fromProtoType(req.Type),
+	}
+	if content.Type == models.ContentTypeImage && req.Key != "" {
+		content.Image = &models.ImageObject{
+			Bucket:   req.Bucket,
+			Key:      req.Key,
+			Checksum: req.Checksum,
+		}
+	}
+	return content
+}
@@ -2,15 +2,21 @@ package grpc
 
 import (
 	"context"
+	"fmt"
 	storage "github.com/deeelis/storage-protos/gen/go/storage"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"log/slog"
 	"storage_service/internal/config"
+	domainerrors "storage_service/internal/domain/errors"
 	"storage_service/internal/domain/models"
 	"storage_service/internal/domain/repositories"
 	usecases "storage_service/internal/usecases"
-	"strings"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	maxBatchSize    = 1000
 )
 
 type StorageController struct {
@@ -18,6 +24,7 @@ type StorageController struct {
 	log *slog.Logger
 	storage.UnimplementedStorageServiceServer
 	storageUsecase repositories.StorageUsecase
+	webhookUsecase repositories.WebhookUsecase
 }
 
 func NewStorageController(cfg *config.Config, log *slog.Logger) (*StorageController, error) {
@@ -26,37 +33,46 @@ func NewStorageController(cfg *config.Config, log *slog.Logger) (*StorageControl
 	if err != nil {
 		return nil, err
 	}
+	webhookUsecase, err := usecases.NewWebhookUsecase(ctx, cfg, log)
+	if err != nil {
+		return nil, err
+	}
 
 	return &StorageController{
 		cfg:            cfg,
 		log:            log,
 		storageUsecase: storageUsecase,
+		webhookUsecase: webhookUsecase,
 	}, nil
 }
 
 func (c *StorageController) GetContent(ctx context.Context, req *storage.ContentRequest) (*storage.ContentResponse, error) {
+	// у старых записей user_id пустой, и запрос без владельца прошёл бы проверку
+	if req.UserId == "" {
+		return nil, invalidArgument("user id is required")
+	}
+
 	content, err := c.storageUsecase.GetContent(ctx, req.ContentId)
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "content not found: %v", err)
+		return nil, c.toStatusError(err, "failed to get content")
 	}
 
-	var processingStatus storage.ProcessingStatus
-	switch content.Status {
-	case models.StatusPending:
-		processingStatus = storage.ProcessingStatus_PENDING
-	case models.StatusProcessing:
-		processingStatus = storage.ProcessingStatus_PROCESSING
-	case models.StatusCompleted:
-		processingStatus = storage.ProcessingStatus_COMPLETED
-	case models.StatusFailed:
-		processingStatus = storage.ProcessingStatus_FAILED
-	default:
-		return nil, status.Errorf(codes.Internal, "unknown processing status: %v", content.Status)
+	// модераторам доступен чужой контент, включая отклонённые изображения
+	if content.UserID != req.UserId && !req.Moderator {
+		c.log.Warn("content access denied",
+			slog.String("content_id", req.ContentId),
+			slog.String("user_id", req.UserId))
+		return nil, c.toStatusError(domainerrors.ErrAccessDenied, "")
+	}
+
+	processingStatus, err := toProtoStatus(content.Status)
+	if err != nil {
+		return nil, c.toStatusError(err, "failed to convert processing status")
 	}
 
 	resp := &storage.ContentResponse{
 		ContentId: content.ID,
-		Type:      storage.ContentType(storage.ContentType_value[strings.ToUpper(string(content.Type))]),
+		Type:      toProtoType(content.Type),
 		Status:    processingStatus,
 	}
 
@@ -64,7 +80,7 @@ func (c *StorageController) GetContent(ctx context.Context, req *storage.Content
 	case models.ContentTypeText:
 		textContent, err := c.storageUsecase.GetTextContent(ctx, content.ID)
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to get text content: %v", err)
+			return nil, c.toStatusError(err, "failed to get text content")
 		}
 		resp.Content = &storage.ContentResponse_Text{
 			Text: &storage.TextContent{
@@ -77,12 +93,12 @@ func (c *StorageController) GetContent(ctx context.Context, req *storage.Content
 		imageContent, err := c.storageUsecase.GetImageContent(ctx, content.ID)
 		if err != nil {
 			c.log.Debug("hueviy image")
-			return nil, status.Errorf(codes.Internal, "failed to get image content: %v", err)
+			return nil, c.toStatusError(err, "failed to get image content")
 		}
 		resp.Content = &storage.ContentResponse_Image{
 			Image: &storage.ImageContent{
 				AnalysisMetadata: imageContent.Metadata,
-				ImageUrl:         imageContent.S3Key,
+				S3Key:            imageContent.S3Key,
 			},
 		}
 	}
@@ -91,16 +107,171 @@ func (c *StorageController) GetContent(ctx context.Context, req *storage.Content
 }
 
 func (c *StorageController) RegisterContent(ctx context.Context, req *storage.RegisterContentRequest) (*storage.RegisterContentResponse, error) {
-	contentType := models.ContentTypeText
-	if req.Type == storage.ContentType_IMAGE {
-		contentType = models.ContentTypeImage
+	if err := c.storageUsecase.CreateContentRecord(ctx, fromRegisterRequest(req)); err != nil {
+		return nil, c.toStatusError(err, "failed to register content")
 	}
 
-	if err := c.storageUsecase.CreateContentRecord(ctx, contentType, req.ContentId); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to register content: %v", err)
+	return &storage.RegisterContentResponse{
+		Success: true,
+	}, nil
+}
+
+func (c *StorageController) RegisterContentBatch(ctx context.Context, req *storage.RegisterContentBatchRequest) (*storage.RegisterContentBatchResponse, error) {
+	if len(req.Items) == 0 {
+		return nil, invalidArgument("batch is empty")
+	}
+	if len(req.Items) > maxBatchSize {
+		return nil, invalidArgument(fmt.Sprintf("batch exceeds %d items", maxBatchSize))
 	}
 
-	return &storage.RegisterContentResponse{
+	contents := make([]*models.Content, 0, len(req.Items))
+	for _, item := range req.Items {
+		contents = append(contents, fromRegisterRequest(item))
+	}
+
+	registered, err := c.storageUsecase.CreateContentRecords(ctx, contents)
+	if err != nil {
+		return nil, c.toStatusError(err, "failed to register content batch")
+	}
+
+	return &storage.RegisterContentBatchResponse{
+		Registered: int32(registered),
+	}, nil
+}
+
+func (c *StorageController) ListContent(ctx context.Context, req *storage.ListContentRequest) (*storage.ListContentResponse, error) {
+	if req.UserId == "" {
+		return nil, invalidArgument("user id is required")
+	}
+
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	filter := &models.ContentFilter{
+		UserID: req.UserId,
+		// одна лишняя запись показывает, есть ли следующая страница
+		Limit: pageSize + 1,
+	}
+
+	if req.PageToken != "" {
+		cursor, err := decodePageToken(req.PageToken)
+		if err != nil {
+			return nil, invalidArgument("invalid page token: " + err.Error())
+		}
+		filter.Cursor = cursor
+	}
+	if req.Status != nil {
+		st, err := fromProtoStatus(*req.Status)
+		if err != nil {
+			return nil, invalidArgument(err.Error())
+		}
+		filter.Status = &st
+	}
+	if req.Type != nil {
+		contentType := fromProtoType(*req.Type)
+		filter.Type = &contentType
+	}
+	if req.CreatedAfter != nil {
+		t := req.CreatedAfter.AsTime()
+		filter.CreatedAfter = &t
+	}
+	if req.CreatedBefore != nil {
+		t := req.CreatedBefore.AsTime()
+		filter.CreatedBefore = &t
+	}
+
+	items, err := c.storageUsecase.ListContent(ctx, filter)
+	if err != nil {
+		return nil, c.toStatusError(err, "failed to list content")
+	}
+
+	resp := &storage.ListContentResponse{}
+	if len(items) > pageSize {
+		items = items[:pageSize]
+		last := items[len(items)-1]
+		resp.NextPageToken = encodePageToken(&models.ContentCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	resp.Items = make([]*storage.ContentSummary, 0, len(items))
+	for _, item := range items {
+		processingStatus, err := toProtoStatus(item.Status)
+		if err != nil {
+			return nil, c.toStatusError(err, "failed to convert processing status")
+		}
+		resp.Items = append(resp.Items, &storage.ContentSummary{
+			ContentId: item.ID,
+			Type:      toProtoType(item.Type),
+			Status:    processingStatus,
+			CreatedAt: timestamppb.New(item.CreatedAt),
+			UpdatedAt: timestamppb.New(item.UpdatedAt),
+		})
+	}
+
+	return resp, nil
+}
+
+func (c *StorageController) DeleteContent(ctx context.Context, req *storage.DeleteContentRequest) (*storage.DeleteContentResponse, error) {
+	if req.ContentId == "" || req.UserId == "" {
+		return nil, invalidArgument("content id and user id are required")
+	}
+
+	purgeAfter, err := c.storageUsecase.DeleteContent(ctx, req.UserId, req.ContentId)
+	if err != nil {
+		return nil, c.toStatusError(err, "failed to delete content")
+	}
+
+	return &storage.DeleteContentResponse{
+		PurgeAfter: timestamppb.New(purgeAfter),
+	}, nil
+}
+
+func (c *StorageController) RestoreContent(ctx context.Context, req *storage.RestoreContentRequest) (*storage.RestoreContentResponse, error) {
+	if req.ContentId == "" || req.UserId == "" {
+		return nil, invalidArgument("content id and user id are required")
+	}
+
+	if err := c.storageUsecase.RestoreContent(ctx, req.UserId, req.ContentId); err != nil {
+		return nil, c.toStatusError(err, "failed to restore content")
+	}
+
+	return &storage.RestoreContentResponse{
 		Success: true,
 	}, nil
 }
+
+func (c *StorageController) WatchContent(req *storage.WatchContentRequest, stream storage.StorageService_WatchContentServer) error {
+	if req.ContentId == "" || req.UserId == "" {
+		return invalidArgument("content id and user id are required")
+	}
+
+	events, unsubscribe, err := c.storageUsecase.WatchContent(stream.Context(), req.UserId, req.ContentId)
+	if err != nil {
+		return c.toStatusError(err, "failed to watch content")
+	}
+	defer unsubscribe()
+
+	for event := range events {
+		processingStatus, err := toProtoStatus(event.Status)
+		if err != nil {
+			return c.toStatusError(err, "failed to convert processing status")
+		}
+
+		if err := stream.Send(&storage.ContentEvent{
+			ContentId:        event.ContentID,
+			Type:             toProtoType(event.Type),
+			Status:           processingStatus,
+			AnalysisMetadata: event.Metadata,
+			OriginalContent:  event.OriginalContent,
+			OccurredAt:       timestamppb.New(event.OccurredAt),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"context"
+	storage "github.com/deeelis/storage-protos/gen/go/storage"
+)
+
+func (c *StorageController) CreateWebhook(ctx context.Context, req *storage.CreateWebhookRequest) (*storage.CreateWebhookResponse, error) {
+	if req.UserId == "" {
+		return nil, invalidArgument("user id is required")
+	}
+
+	webhook, err := c.webhookUsecase.CreateWebhook(ctx, req.UserId, req.Url, req.Secret)
+	if err != nil {
+		return nil, c.toStatusError(err, "failed to create webhook")
+	}
+
+	return &storage.CreateWebhookResponse{
+		Webhook: toProtoWebhook(webhook),
+		Secret:  webhook.Secret,
+	}, nil
+}
+
+func (c *StorageController) ListWebhooks(ctx context.Context, req *storage.ListWebhooksRequest) (*storage.ListWebhooksResponse, error) {
+	if req.UserId == "" {
+		return nil, invalidArgument("user id is required")
+	}
+
+	webhooks, err := c.webhookUsecase.ListWebhooks(ctx, req.UserId)
+	if err != nil {
+		return nil, c.toStatusError(err, "failed to list webhooks")
+	}
+
+	resp := &storage.ListWebhooksResponse{
+		Webhooks: make([]*storage.Webhook, 0, len(webhooks)),
+	}
+	for _, webhook := range webhooks {
+		resp.Webhooks = append(resp.Webhooks, toProtoWebhook(webhook))
+	}
+
+	return resp, nil
+}
+
+func (c *StorageController) DeleteWebhook(ctx context.Context, req *storage.DeleteWebhookRequest) (*storage.DeleteWebhookResponse, error) {
+	if err := c.webhookUsecase.DeleteWebhook(ctx, req.UserId, req.WebhookId); err != nil {
+		return nil, c.toStatusError(err, "failed to delete webhook")
+	}
+
+	return &storage.DeleteWebhookResponse{
+		Success: true,
+	}, nil
+}
+
+func (c *StorageController) ListWebhookDeliveries(ctx context.Context, req *storage.ListWebhookDeliveriesRequest) (*storage.ListWebhookDeliveriesResponse, error) {
+	deliveries, err := c.webhookUsecase.ListDeliveries(ctx, req.UserId, req.WebhookId, req.Status, int(req.Limit))
+	if err != nil {
+		return nil, c.toStatusError(err, "failed to list webhook deliveries")
+	}
+
+	resp := &storage.ListWebhookDeliveriesResponse{
+		Deliveries: make([]*storage.WebhookDelivery, 0, len(deliveries)),
+	}
+	for _, delivery := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, toProtoDelivery(delivery))
+	}
+
+	return resp, nil
+}
+
+func (c *StorageController) RedeliverWebhook(ctx context.Context, req *storage.RedeliverWebhookRequest) (*storage.RedeliverWebhookResponse, error) {
+	delivery, err := c.webhookUsecase.Redeliver(ctx, req.UserId, req.WebhookId, req.DeliveryId)
+	if err != nil {
+		return nil, c.toStatusError(err, "failed to redeliver webhook")
+	}
+
+	return &storage.RedeliverWebhookResponse{
+		Delivery: toProtoDelivery(delivery),
+	}, nil
+}
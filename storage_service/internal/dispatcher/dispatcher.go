@@ -0,0 +1,80 @@
+package dispatcher
+
+import (
+	"context"
+	"log/slog"
+	"storage_service/internal/config"
+	"storage_service/internal/domain/repositories"
+	services "storage_service/internal/usecases"
+	"storage_service/logger"
+	"time"
+)
+
+// Dispatcher опрашивает очередь доставок вебхуков и отправляет те, чей срок подошёл.
+type Dispatcher struct {
+	cfg     *config.Config
+	log     *slog.Logger
+	usecase repositories.WebhookUsecase
+	done    chan struct{}
+}
+
+func NewDispatcher(ctx context.Context, cfg *config.Config, log *slog.Logger) (*Dispatcher, error) {
+	usecase, err := services.NewWebhookUsecase(ctx, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Dispatcher{
+		cfg:     cfg,
+		log:     log.With(slog.String("component", "webhook_dispatcher")),
+		usecase: usecase,
+		done:    make(chan struct{}),
+	}, nil
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	const op = "dispatcher.Dispatcher.Run"
+	log := d.log.With(slog.String("op", op))
+
+	log.Info("webhook dispatcher started",
+		slog.Duration("poll_interval", d.cfg.Webhooks.PollInterval))
+
+	ticker := time.NewTicker(d.cfg.Webhooks.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.done:
+			log.Info("webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+			d.dispatch(ctx)
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context) {
+	for {
+		sent, err := d.usecase.DispatchDue(ctx)
+		if err != nil {
+			d.log.Error("webhook dispatch failed", logger.Err(err))
+			return
+		}
+		// неполная пачка — очередь разобрана до следующего тика
+		if sent < d.cfg.Webhooks.BatchSize {
+			return
+		}
+		select {
+		case <-d.done:
+			return
+		default:
+		}
+	}
+}
+
+func (d *Dispatcher) Close() error {
+	close(d.done)
+	return nil
+}
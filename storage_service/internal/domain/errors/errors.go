@@ -0,0 +1,12 @@
+package errors
+
+import "errors"
+
+var (
+	ErrContentNotFound  = errors.New("content not found")
+	ErrAccessDenied     = errors.New("content belongs to another user")
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrInvalidWebhook   = errors.New("invalid webhook")
+	ErrNotRedeliverable = errors.New("delivery is not in failed state")
+	ErrInvalidContent   = errors.New("invalid content")
+)
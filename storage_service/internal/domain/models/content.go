@@ -19,13 +19,26 @@ const (
 )
 
 type Content struct {
-	ID        string
-	UserID    string `json:"user_id"`
+	ID     string
+	UserID string `json:"user_id"`
+	// RequestID — id HTTP-запроса, которым контент был загружен; по нему поддержка
+	// находит запись, когда клиент присылает значение из X-Request-ID.
+	RequestID string `json:"request_id,omitempty"`
 	Type      ContentType
 	Status    ProcessingStatus
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 	Metadata  map[string]string
+	// Image заполняется только при регистрации изображения.
+	Image *ImageObject `json:"-"`
+}
+
+// ImageObject — объект изображения, загруженный шлюзом до регистрации.
+type ImageObject struct {
+	Bucket   string
+	Key      string
+	Checksum string
 }
 
 type TextContent struct {
@@ -37,3 +50,32 @@ type ImageContent struct {
 	Content
 	S3Key string
 }
+
+type ContentCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+type ContentFilter struct {
+	UserID        string
+	Status        *ProcessingStatus
+	Type          *ContentType
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Cursor        *ContentCursor
+	Limit         int
+}
+
+type ContentEvent struct {
+	ContentID       string            `json:"content_id"`
+	UserID          string            `json:"user_id"`
+	Type            ContentType       `json:"type"`
+	Status          ProcessingStatus  `json:"status"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+	OriginalContent string            `json:"original_content,omitempty"`
+	OccurredAt      time.Time         `json:"occurred_at"`
+}
+
+func (s ProcessingStatus) IsTerminal() bool {
+	return s == StatusCompleted || s == StatusFailed
+}
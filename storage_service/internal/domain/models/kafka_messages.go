@@ -7,19 +7,30 @@ type TextMessage struct {
 	Analysis map[string]string `json:"result"`
 }
 
+// ImageMessage — вердикт по изображению. Сами байты лежат в объектном хранилище
+// под Key, в Kafka передаётся только ссылка на объект.
 type ImageMessage struct {
 	ID         string           `json:"id"`
 	UserID     string           `json:"user_id"`
-	Data       string           `json:"data"`
+	Bucket     string           `json:"bucket"`
+	Key        string           `json:"key"`
+	Checksum   string           `json:"checksum"`
+	Size       int64            `json:"size"`
 	NsfwScores NsfwScoresResult `json:"nsfw_scores"`
+	IsNsfw     bool             `json:"is_nsfw"`
+	Error      string           `json:"error"`
 }
 
 type ImageKafkaMessage struct {
 	ID         string           `json:"id"`
 	UserID     string           `json:"user_id"`
-	Data       string           `json:"data"`
+	Bucket     string           `json:"bucket"`
+	Key        string           `json:"key"`
+	Checksum   string           `json:"checksum"`
+	Size       int64            `json:"size"`
 	NsfwScores NsfwScoresResult `json:"nsfw_scores"`
 	IsNsfw     bool             `json:"is_nsfw"`
+	Error      string           `json:"error"`
 }
 
 type NsfwScoresResult struct {
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+const (
+	EventContentCompleted = "content.completed"
+	EventContentFailed    = "content.failed"
+)
+
+type Webhook struct {
+	ID        string
+	UserID    string
+	URL       string
+	Secret    string
+	CreatedAt time.Time
+}
+
+type WebhookDelivery struct {
+	ID            string
+	WebhookID     string
+	ContentID     string
+	Event         string
+	Payload       json.RawMessage
+	Status        DeliveryStatus
+	Attempts      int
+	ResponseCode  int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// DueDelivery — доставка вместе с адресом и секретом вебхука, готовая к отправке.
+type DueDelivery struct {
+	WebhookDelivery
+	URL    string
+	Secret string
+}
+
+type WebhookPayload struct {
+	DeliveryID string            `json:"delivery_id"`
+	Event      string            `json:"event"`
+	ContentID  string            `json:"content_id"`
+	Type       ContentType       `json:"type"`
+	Status     ProcessingStatus  `json:"status"`
+	Analysis   map[string]string `json:"analysis,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
@@ -3,25 +3,55 @@ package repositories
 import (
 	"context"
 	"storage_service/internal/domain/models"
+	"time"
 )
 
 type ContentRepository interface {
 	CreateContentRecord(ctx context.Context, content *models.Content) error
+	CreateContentRecords(ctx context.Context, contents []*models.Content) (int, error)
 	CreateContent(ctx context.Context, content *models.Content) error
 	GetContent(ctx context.Context, id string) (*models.Content, error)
 	UpdateContentStatus(ctx context.Context, id string, status models.ProcessingStatus) error
-	UpdateTextContent(ctx context.Context, content *models.TextContent) error
-	UpdateImageContent(ctx context.Context, content *models.ImageContent) error
+	// UpdateTextContent и UpdateImageContent пишут доставки вебхуков в той же
+	// транзакции, что и результат анализа.
+	UpdateTextContent(ctx context.Context, content *models.TextContent, deliveries []*models.WebhookDelivery) error
+	UpdateImageContent(ctx context.Context, content *models.ImageContent, deliveries []*models.WebhookDelivery) error
 	GetTextContent(ctx context.Context, id string) (*models.TextContent, error)
 	GetImageContent(ctx context.Context, id string) (*models.ImageContent, error)
+	ListContent(ctx context.Context, filter *models.ContentFilter) ([]*models.Content, error)
+	SoftDeleteContent(ctx context.Context, id string, userID string, deletedAt time.Time) error
+	// RestoreContent снимает отметку об удалении, только если запись удалена
+	// позже deletedAfter: более старые уже может удалять очистка.
+	RestoreContent(ctx context.Context, id string, userID string, deletedAfter time.Time) error
+	ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]*models.Content, error)
+	// GetImageKey возвращает ключ изображения записи, в том числе удалённой; для текста — пустую строку.
+	GetImageKey(ctx context.Context, id string) (string, error)
+	// DeleteContent удаляет запись со всеми зависимыми строками.
+	DeleteContent(ctx context.Context, id string) error
+}
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) error
+	GetWebhook(ctx context.Context, id string, userID string) (*models.Webhook, error)
+	ListWebhooks(ctx context.Context, userID string) ([]*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id string, userID string) error
+	GetDelivery(ctx context.Context, id string, webhookID string) (*models.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, webhookID string, status *models.DeliveryStatus, limit int) ([]*models.WebhookDelivery, error)
+	ClaimDueDeliveries(ctx context.Context, leaseUntil time.Time, limit int) ([]*models.DueDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
 }
 
 type CacheRepository interface {
-	Get(ctx context.Context, key string) (interface{}, error)
+	Get(ctx context.Context, key string, dest interface{}) (bool, error)
 	Set(ctx context.Context, key string, value interface{}, ttl int) error
 	Delete(ctx context.Context, key string) error
 }
 
+type EventBus interface {
+	Publish(ctx context.Context, event *models.ContentEvent) error
+	Subscribe(ctx context.Context, contentID string) (<-chan *models.ContentEvent, func(), error)
+}
+
 type StorageUsecase interface {
 	GetContent(ctx context.Context, id string) (*models.Content, error)
 	GetTextContent(ctx context.Context, id string) (*models.TextContent, error)
@@ -30,10 +60,26 @@ type StorageUsecase interface {
 	StoreImageAnalysis(ctx context.Context, content *models.ImageContent) error
 	ProcessImageMessage(ctx context.Context, m *models.ImageMessage) error
 	ProcessTextMessage(ctx context.Context, m *models.TextMessage) error
-	CreateContentRecord(ctx context.Context, contentType models.ContentType, contentID string) error
+	CreateContentRecord(ctx context.Context, content *models.Content) error
+	CreateContentRecords(ctx context.Context, contents []*models.Content) (int, error)
+	ListContent(ctx context.Context, filter *models.ContentFilter) ([]*models.Content, error)
+	DeleteContent(ctx context.Context, userID string, contentID string) (time.Time, error)
+	RestoreContent(ctx context.Context, userID string, contentID string) error
+	PurgeDeletedContent(ctx context.Context) (int, error)
+	WatchContent(ctx context.Context, userID string, contentID string) (<-chan *models.ContentEvent, func(), error)
+}
+
+type WebhookUsecase interface {
+	CreateWebhook(ctx context.Context, userID string, url string, secret string) (*models.Webhook, error)
+	ListWebhooks(ctx context.Context, userID string) ([]*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, userID string, webhookID string) error
+	ListDeliveries(ctx context.Context, userID string, webhookID string, status string, limit int) ([]*models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, userID string, webhookID string, deliveryID string) (*models.WebhookDelivery, error)
+	DispatchDue(ctx context.Context) (int, error)
 }
 
 type ImageStorage interface {
-	StoreImage(ctx context.Context, data []byte) (string, error)
-	GetImageURL(ctx context.Context, key string) (string, error)
+	Bucket() string
+	TagImage(ctx context.Context, key string, tags map[string]string) error
+	DeleteImage(ctx context.Context, key string) error
 }
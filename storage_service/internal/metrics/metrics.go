@@ -0,0 +1,78 @@
+// Package metrics описывает метрики Prometheus сервиса хранения
+// и отдаёт их на отдельном HTTP-порту.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	ResultOK      = "ok"
+	ResultError   = "error"
+	ResultInvalid = "invalid"
+
+	CacheHit   = "hit"
+	CacheMiss  = "miss"
+	CacheError = "error"
+)
+
+var (
+	kafkaConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_consumed_messages_total",
+		Help: "Kafka messages consumed, by topic and processing result.",
+	}, []string{"topic", "result"})
+
+	kafkaConsumeDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kafka_consume_duration_seconds",
+		Help:    "Time spent processing a consumed Kafka message.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"topic"})
+
+	cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_requests_total",
+		Help: "Redis cache lookups, by cache and result.",
+	}, []string{"cache", "result"})
+
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Postgres query latency, by repository operation.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"query"})
+
+	contentCompletion = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "content_completion_seconds",
+		Help:    "Time from content registration to COMPLETED status.",
+		Buckets: []float64{.25, .5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1800},
+	}, []string{"type"})
+)
+
+// ObserveConsumed учитывает обработку одного сообщения из topic
+func ObserveConsumed(topic string, result string, startTime time.Time) {
+	kafkaConsumed.WithLabelValues(topic, result).Inc()
+	kafkaConsumeDuration.WithLabelValues(topic).Observe(time.Since(startTime).Seconds())
+}
+
+func ObserveCache(cache string, result string) {
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// ObserveQuery засекает время запроса к Postgres:
+//
+//	defer metrics.ObserveQuery("content.GetContent")()
+func ObserveQuery(query string) func() {
+	startTime := time.Now()
+	return func() {
+		dbQueryDuration.WithLabelValues(query).Observe(time.Since(startTime).Seconds())
+	}
+}
+
+// ObserveCompletion учитывает время модерации контента, зарегистрированного в createdAt
+func ObserveCompletion(contentType string, createdAt time.Time) {
+	if createdAt.IsZero() {
+		return
+	}
+	contentCompletion.WithLabelValues(contentType).Observe(time.Since(createdAt).Seconds())
+}
@@ -0,0 +1,82 @@
+package purger
+
+import (
+	"context"
+	"log/slog"
+	"storage_service/internal/config"
+	"storage_service/internal/domain/repositories"
+	services "storage_service/internal/usecases"
+	"storage_service/logger"
+	"time"
+)
+
+// Purger периодически окончательно удаляет контент, у которого истёк срок восстановления.
+type Purger struct {
+	cfg     *config.Config
+	log     *slog.Logger
+	usecase repositories.StorageUsecase
+	done    chan struct{}
+}
+
+func NewPurger(ctx context.Context, cfg *config.Config, log *slog.Logger) (*Purger, error) {
+	usecase, err := services.NewStorageUsecase(ctx, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Purger{
+		cfg:     cfg,
+		log:     log.With(slog.String("component", "purger")),
+		usecase: usecase,
+		done:    make(chan struct{}),
+	}, nil
+}
+
+func (p *Purger) Run(ctx context.Context) {
+	const op = "purger.Purger.Run"
+	log := p.log.With(slog.String("op", op))
+
+	log.Info("purger started",
+		slog.Duration("grace_period", p.cfg.Purge.GracePeriod),
+		slog.Duration("interval", p.cfg.Purge.Interval))
+
+	ticker := time.NewTicker(p.cfg.Purge.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.done:
+			log.Info("purger stopped")
+			return
+		case <-ticker.C:
+			p.purge(ctx)
+		}
+	}
+}
+
+func (p *Purger) purge(ctx context.Context) {
+	startTime := time.Now()
+	for {
+		purged, err := p.usecase.PurgeDeletedContent(ctx)
+		if err != nil {
+			p.log.Error("purge failed", logger.Err(err))
+			return
+		}
+		if purged > 0 {
+			p.log.Info("deleted content purged",
+				slog.Int("count", purged),
+				slog.Duration("duration", time.Since(startTime)))
+		}
+		// неполная пачка — больше просроченных записей нет
+		if purged < p.cfg.Purge.BatchSize {
+			return
+		}
+	}
+}
+
+func (p *Purger) Close() error {
+	close(p.done)
+	return nil
+}
@@ -5,12 +5,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/deeelis/platform/tracing/redistrace"
 	"github.com/go-redis/redis/v8"
 	"log/slog"
 	"storage_service/internal/config"
+	"storage_service/internal/metrics"
 	"time"
 )
 
+// cacheName — метка кэша в метриках
+const cacheName = "content"
+
 type RedisCache struct {
 	cfg    *config.CacheConfig
 	client *redis.Client
@@ -24,24 +29,30 @@ func NewRedisCache(cfg *config.CacheConfig, log *slog.Logger) (*RedisCache, erro
 		return nil, err
 	}
 	client := redis.NewClient(redisOpts)
+	client.AddHook(redistrace.Hook{})
 	return &RedisCache{client: client}, nil
 }
 
-func (r *RedisCache) Get(ctx context.Context, key string) (interface{}, error) {
+// Get читает значение в dest. Попаданием считается только значение,
+// которое удалось разобрать: его вызывающий и использует.
+func (r *RedisCache) Get(ctx context.Context, key string, dest interface{}) (bool, error) {
 	data, err := r.client.Get(ctx, key).Bytes()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return nil, nil
+			metrics.ObserveCache(cacheName, metrics.CacheMiss)
+			return false, nil
 		}
-		return nil, err
+		metrics.ObserveCache(cacheName, metrics.CacheError)
+		return false, err
 	}
 
-	var result interface{}
-	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal cached data: %w", err)
+	if err := json.Unmarshal(data, dest); err != nil {
+		metrics.ObserveCache(cacheName, metrics.CacheError)
+		return false, fmt.Errorf("failed to unmarshal cached data: %w", err)
 	}
 
-	return result, nil
+	metrics.ObserveCache(cacheName, metrics.CacheHit)
+	return true, nil
 }
 
 func (r *RedisCache) Set(ctx context.Context, key string, value interface{}, ttl int) error {
@@ -57,6 +68,10 @@ func (r *RedisCache) Delete(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
 }
 
+func (r *RedisCache) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
 func (r *RedisCache) Close() {
 	err := r.client.Close()
 	if err != nil {
@@ -0,0 +1,95 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/deeelis/platform/tracing/redistrace"
+	"github.com/go-redis/redis/v8"
+	"log/slog"
+	"storage_service/internal/config"
+	"storage_service/internal/domain/models"
+	"storage_service/logger"
+	"sync"
+)
+
+const channelPrefix = "content:events:"
+
+// RedisEventBus рассылает переходы статусов контента через Redis pub/sub,
+// по одному каналу на каждый content_id.
+type RedisEventBus struct {
+	client *redis.Client
+	log    *slog.Logger
+}
+
+func NewRedisEventBus(cfg *config.CacheConfig, log *slog.Logger) (*RedisEventBus, error) {
+	redisOpts, err := redis.ParseURL(cfg.URL)
+	if err != nil {
+		log.Error("failed to parse Redis URL", logger.Err(err))
+		return nil, err
+	}
+
+	client := redis.NewClient(redisOpts)
+	client.AddHook(redistrace.Hook{})
+	return &RedisEventBus{
+		client: client,
+		log:    log,
+	}, nil
+}
+
+func (b *RedisEventBus) Publish(ctx context.Context, event *models.ContentEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	return b.client.Publish(ctx, channelPrefix+event.ContentID, data).Err()
+}
+
+func (b *RedisEventBus) Subscribe(ctx context.Context, contentID string) (<-chan *models.ContentEvent, func(), error) {
+	pubsub := b.client.Subscribe(ctx, channelPrefix+contentID)
+	// дожидаемся подтверждения подписки, чтобы не потерять событие,
+	// опубликованное сразу после чтения текущего состояния
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	out := make(chan *models.ContentEvent)
+	done := make(chan struct{})
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-done:
+				return
+			case msg, ok := <-pubsub.Channel():
+				if !ok {
+					return
+				}
+				var event models.ContentEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					b.log.Warn("failed to unmarshal event",
+						slog.String("content_id", contentID),
+						logger.Err(err))
+					continue
+				}
+				select {
+				case out <- &event:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			close(done)
+			_ = pubsub.Close()
+		})
+	}
+
+	return out, cancel, nil
+}
@@ -8,10 +8,14 @@ import (
 	"fmt"
 	"log/slog"
 	"storage_service/internal/config"
+	"storage_service/internal/metrics"
 	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/XSAM/otelsql"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	domainerrors "storage_service/internal/domain/errors"
 	"storage_service/internal/domain/models"
 )
 
@@ -26,12 +30,25 @@ type PostgresContentRepository struct {
 const driverName = "postgres"
 
 func NewPostgresContentRepository(ctx context.Context, cfg *config.RepoConfig, log *slog.Logger) (*PostgresContentRepository, error) {
+	db, err := openDB(ctx, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostgresContentRepository{cfg: cfg,
+		db:  db,
+		log: log}, nil
+}
+
+func openDB(ctx context.Context, cfg *config.RepoConfig, log *slog.Logger) (*sql.DB, error) {
 	log.Info("connecting to database",
 		slog.String("driver", driverName),
 		slog.String("dsn_mask", maskDSN(cfg.DSN)), // Функция для маскирования чувствительных данных
 	)
 
-	db, err := sql.Open(driverName, cfg.DSN)
+	db, err := otelsql.Open(driverName, cfg.DSN,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true}))
 	if err != nil {
 		log.Error("failed to open database connection",
 			slog.Any("error", err),
@@ -50,20 +67,23 @@ func NewPostgresContentRepository(ctx context.Context, cfg *config.RepoConfig, l
 	}
 
 	log.Info("database connection established successfully")
-	return &PostgresContentRepository{cfg: cfg,
-		db:  db,
-		log: log}, nil
+	return db, nil
+}
+
+func (r *PostgresContentRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
 }
 
 func (r *PostgresContentRepository) CreateContent(ctx context.Context, content *models.Content) error {
+	defer metrics.ObserveQuery("content.CreateContent")()
 	metadataJSON, err := json.Marshal(content.Metadata)
 	if err != nil {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
 	query, args, err := psql.Insert("content").
-		Columns("id", "type", "status", "metadata", "created_at", "updated_at").
-		Values(content.ID, content.Type, content.Status, metadataJSON, content.CreatedAt, content.UpdatedAt).
+		Columns("id", "user_id", "request_id", "type", "status", "metadata", "created_at", "updated_at").
+		Values(content.ID, content.UserID, content.RequestID, content.Type, content.Status, metadataJSON, content.CreatedAt, content.UpdatedAt).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("failed to build query: %w", err)
@@ -74,9 +94,10 @@ func (r *PostgresContentRepository) CreateContent(ctx context.Context, content *
 }
 
 func (r *PostgresContentRepository) GetContent(ctx context.Context, id string) (*models.Content, error) {
-	query, args, err := psql.Select("id", "type", "status", "metadata", "created_at", "updated_at").
+	defer metrics.ObserveQuery("content.GetContent")()
+	query, args, err := psql.Select("id", "user_id", "request_id", "type", "status", "metadata", "created_at", "updated_at").
 		From("content").
-		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build query: %w", err)
@@ -87,6 +108,8 @@ func (r *PostgresContentRepository) GetContent(ctx context.Context, id string) (
 
 	err = r.db.QueryRowContext(ctx, query, args...).Scan(
 		&content.ID,
+		&content.UserID,
+		&content.RequestID,
 		&content.Type,
 		&content.Status,
 		&metadataJSON,
@@ -96,7 +119,7 @@ func (r *PostgresContentRepository) GetContent(ctx context.Context, id string) (
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("content not found")
+			return nil, domainerrors.ErrContentNotFound
 		}
 		return nil, err
 	}
@@ -109,6 +132,7 @@ func (r *PostgresContentRepository) GetContent(ctx context.Context, id string) (
 }
 
 func (r *PostgresContentRepository) UpdateContentStatus(ctx context.Context, id string, status models.ProcessingStatus) error {
+	defer metrics.ObserveQuery("content.UpdateContentStatus")()
 	query, args, err := psql.Update("content").
 		Set("status", status).
 		Set("updated_at", time.Now()).
@@ -122,7 +146,8 @@ func (r *PostgresContentRepository) UpdateContentStatus(ctx context.Context, id
 	return err
 }
 
-func (r *PostgresContentRepository) UpdateTextContent(ctx context.Context, content *models.TextContent) error {
+func (r *PostgresContentRepository) UpdateTextContent(ctx context.Context, content *models.TextContent, deliveries []*models.WebhookDelivery) error {
+	defer metrics.ObserveQuery("content.UpdateTextContent")()
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -143,13 +168,14 @@ func (r *PostgresContentRepository) UpdateTextContent(ctx context.Context, conte
 		Set("status", content.Status).
 		Set("metadata", metadataJSON).
 		Set("updated_at", content.UpdatedAt).
-		Where(sq.Eq{"id": content.ID}).
+		Where(sq.Eq{"id": content.ID, "deleted_at": nil}).
+		Suffix("RETURNING created_at").
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("failed to build update content query: %w", err)
 	}
 
-	if _, err = tx.ExecContext(ctx, updateContentQuery, updateArgs...); err != nil {
+	if err = scanCreatedAt(tx.QueryRowContext(ctx, updateContentQuery, updateArgs...), &content.Content); err != nil {
 		r.log.Error("first exec " + err.Error())
 		return err
 	}
@@ -169,10 +195,15 @@ func (r *PostgresContentRepository) UpdateTextContent(ctx context.Context, conte
 		return err
 	}
 
+	if err = insertDeliveries(ctx, tx, deliveries); err != nil {
+		return fmt.Errorf("failed to create webhook deliveries: %w", err)
+	}
+
 	return tx.Commit()
 }
 
-func (r *PostgresContentRepository) UpdateImageContent(ctx context.Context, content *models.ImageContent) error {
+func (r *PostgresContentRepository) UpdateImageContent(ctx context.Context, content *models.ImageContent, deliveries []*models.WebhookDelivery) error {
+	defer metrics.ObserveQuery("content.UpdateImageContent")()
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -192,13 +223,14 @@ func (r *PostgresContentRepository) UpdateImageContent(ctx context.Context, cont
 		Set("status", content.Status).
 		Set("metadata", metadataJSON).
 		Set("updated_at", content.UpdatedAt).
-		Where(sq.Eq{"id": content.ID}).
+		Where(sq.Eq{"id": content.ID, "deleted_at": nil}).
+		Suffix("RETURNING created_at").
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("failed to build update content query: %w", err)
 	}
 
-	if _, err = tx.ExecContext(ctx, updateContentQuery, updateArgs...); err != nil {
+	if err = scanCreatedAt(tx.QueryRowContext(ctx, updateContentQuery, updateArgs...), &content.Content); err != nil {
 		return err
 	}
 
@@ -215,10 +247,28 @@ func (r *PostgresContentRepository) UpdateImageContent(ctx context.Context, cont
 		return err
 	}
 
+	if err = insertDeliveries(ctx, tx, deliveries); err != nil {
+		return fmt.Errorf("failed to create webhook deliveries: %w", err)
+	}
+
 	return tx.Commit()
 }
 
+// scanCreatedAt читает время регистрации из RETURNING. Если обновление ничего
+// не затронуло, запись удалена или не зарегистрирована, и результат анализа
+// записывать некуда.
+func scanCreatedAt(row *sql.Row, content *models.Content) error {
+	if err := row.Scan(&content.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domainerrors.ErrContentNotFound
+		}
+		return err
+	}
+	return nil
+}
+
 func (r *PostgresContentRepository) GetImageContent(ctx context.Context, id string) (*models.ImageContent, error) {
+	defer metrics.ObserveQuery("content.GetImageContent")()
 	content, err := r.GetContent(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get content: %w", err)
@@ -245,6 +295,7 @@ func (r *PostgresContentRepository) GetImageContent(ctx context.Context, id stri
 		return nil, err
 	}
 
+	imageContent.UserID = content.UserID
 	imageContent.Type = content.Type
 	imageContent.Status = content.Status
 	imageContent.Metadata = content.Metadata
@@ -255,6 +306,7 @@ func (r *PostgresContentRepository) GetImageContent(ctx context.Context, id stri
 }
 
 func (r *PostgresContentRepository) GetTextContent(ctx context.Context, id string) (*models.TextContent, error) {
+	defer metrics.ObserveQuery("content.GetTextContent")()
 	r.log.Debug(id)
 	content, err := r.GetContent(ctx, id)
 	if err != nil {
@@ -284,6 +336,7 @@ func (r *PostgresContentRepository) GetTextContent(ctx context.Context, id strin
 		return nil, err
 	}
 
+	textContent.UserID = content.UserID
 	textContent.Type = content.Type
 	textContent.Status = content.Status
 	textContent.Metadata = content.Metadata
@@ -294,19 +347,286 @@ func (r *PostgresContentRepository) GetTextContent(ctx context.Context, id strin
 }
 
 func (r *PostgresContentRepository) CreateContentRecord(ctx context.Context, content *models.Content) error {
+	defer metrics.ObserveQuery("content.CreateContentRecord")()
+	_, err := r.createContentRecords(ctx, []*models.Content{content})
+	return err
+}
+
+func (r *PostgresContentRepository) ListContent(ctx context.Context, filter *models.ContentFilter) ([]*models.Content, error) {
+	defer metrics.ObserveQuery("content.ListContent")()
+	builder := psql.Select("id", "user_id", "type", "status", "created_at", "updated_at").
+		From("content").
+		Where(sq.Eq{"user_id": filter.UserID, "deleted_at": nil})
+
+	if filter.Status != nil {
+		builder = builder.Where(sq.Eq{"status": *filter.Status})
+	}
+	if filter.Type != nil {
+		builder = builder.Where(sq.Eq{"type": *filter.Type})
+	}
+	if filter.CreatedAfter != nil {
+		builder = builder.Where(sq.GtOrEq{"created_at": *filter.CreatedAfter})
+	}
+	if filter.CreatedBefore != nil {
+		builder = builder.Where(sq.Lt{"created_at": *filter.CreatedBefore})
+	}
+	if filter.Cursor != nil {
+		builder = builder.Where("(created_at, id) < (?, ?)", filter.Cursor.CreatedAt, filter.Cursor.ID)
+	}
+
+	query, args, err := builder.
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(filter.Limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*models.Content, 0, filter.Limit)
+	for rows.Next() {
+		var content models.Content
+		if err := rows.Scan(
+			&content.ID,
+			&content.UserID,
+			&content.Type,
+			&content.Status,
+			&content.CreatedAt,
+			&content.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &content)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *PostgresContentRepository) CreateContentRecords(ctx context.Context, contents []*models.Content) (int, error) {
+	defer metrics.ObserveQuery("content.CreateContentRecords")()
+	if len(contents) == 0 {
+		return 0, nil
+	}
+	return r.createContentRecords(ctx, contents)
+}
+
+// createContentRecords вместе с записями сохраняет объекты изображений, чтобы
+// ключ был известен до вердикта модерации.
+func (r *PostgresContentRepository) createContentRecords(ctx context.Context, contents []*models.Content) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
 	metadata, _ := json.Marshal(make(map[string]string))
 
-	query, args, err := psql.Insert("content").
-		Columns("id", "type", "status", "created_at", "updated_at", "metadata").
-		Values(content.ID, content.Type, content.Status, content.CreatedAt, content.UpdatedAt, metadata).
+	builder := psql.Insert("content").
+		Columns("id", "user_id", "request_id", "type", "status", "created_at", "updated_at", "metadata")
+	images := psql.Insert("image_content").
+		Columns("content_id", "s3_key", "bucket", "checksum")
+	hasImages := false
+	for _, content := range contents {
+		builder = builder.Values(content.ID, content.UserID, content.RequestID, content.Type, content.Status, content.CreatedAt, content.UpdatedAt, metadata)
+		if content.Image != nil {
+			images = images.Values(content.ID, content.Image.Key, content.Image.Bucket, content.Image.Checksum)
+			hasImages = true
+		}
+	}
+
+	query, args, err := builder.
 		Suffix("ON CONFLICT (id) DO NOTHING").
 		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if hasImages {
+		query, args, err := images.
+			Suffix("ON CONFLICT (content_id) DO NOTHING").
+			ToSql()
+		if err != nil {
+			return 0, fmt.Errorf("failed to build image query: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return int(affected), nil
+}
+
+func (r *PostgresContentRepository) SoftDeleteContent(ctx context.Context, id string, userID string, deletedAt time.Time) error {
+	defer metrics.ObserveQuery("content.SoftDeleteContent")()
+	query, args, err := psql.Update("content").
+		Set("deleted_at", deletedAt).
+		Set("updated_at", deletedAt).
+		Where(sq.Eq{"id": id, "user_id": userID, "deleted_at": nil}).
+		ToSql()
 	if err != nil {
 		return fmt.Errorf("failed to build query: %w", err)
 	}
 
-	_, err = r.db.ExecContext(ctx, query, args...)
-	return err
+	return r.execAffectingOne(ctx, query, args...)
+}
+
+func (r *PostgresContentRepository) RestoreContent(ctx context.Context, id string, userID string, deletedAfter time.Time) error {
+	defer metrics.ObserveQuery("content.RestoreContent")()
+	query, args, err := psql.Update("content").
+		Set("deleted_at", nil).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": id, "user_id": userID}).
+		Where(sq.Gt{"deleted_at": deletedAfter}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return r.execAffectingOne(ctx, query, args...)
+}
+
+func (r *PostgresContentRepository) ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]*models.Content, error) {
+	defer metrics.ObserveQuery("content.ListDeletedBefore")()
+	query, args, err := psql.Select("id", "user_id", "type", "status", "created_at", "updated_at", "deleted_at").
+		From("content").
+		Where(sq.Lt{"deleted_at": before}).
+		OrderBy("deleted_at").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*models.Content, 0, limit)
+	for rows.Next() {
+		var content models.Content
+		if err := rows.Scan(
+			&content.ID,
+			&content.UserID,
+			&content.Type,
+			&content.Status,
+			&content.CreatedAt,
+			&content.UpdatedAt,
+			&content.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &content)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *PostgresContentRepository) GetImageKey(ctx context.Context, id string) (string, error) {
+	defer metrics.ObserveQuery("content.GetImageKey")()
+	query, args, err := psql.Select("s3_key").
+		From("image_content").
+		Where(sq.Eq{"content_id": id}).
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var s3Key string
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&s3Key); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return s3Key, nil
+}
+
+func (r *PostgresContentRepository) DeleteContent(ctx context.Context, id string) error {
+	defer metrics.ObserveQuery("content.DeleteContent")()
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	for _, table := range []string{"text_content", "image_content"} {
+		var query string
+		var args []interface{}
+		query, args, err = psql.Delete(table).
+			Where(sq.Eq{"content_id": id}).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build delete %s query: %w", table, err)
+		}
+
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	deleteContentQuery, contentArgs, err := psql.Delete("content").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build delete content query: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, deleteContentQuery, contentArgs...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgresContentRepository) execAffectingOne(ctx context.Context, query string, args ...interface{}) error {
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domainerrors.ErrContentNotFound
+	}
+
+	return nil
 }
 
 func maskDSN(dsn string) string {
@@ -0,0 +1,339 @@
+package repos
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"storage_service/internal/config"
+	"storage_service/internal/metrics"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	domainerrors "storage_service/internal/domain/errors"
+	"storage_service/internal/domain/models"
+)
+
+var deliveryColumns = []string{
+	"id", "webhook_id", "content_id", "event", "payload", "status",
+	"attempts", "response_code", "last_error", "next_attempt_at", "created_at", "updated_at",
+}
+
+type PostgresWebhookRepository struct {
+	cfg *config.RepoConfig
+	db  *sql.DB
+	log *slog.Logger
+}
+
+func NewPostgresWebhookRepository(ctx context.Context, cfg *config.RepoConfig, log *slog.Logger) (*PostgresWebhookRepository, error) {
+	db, err := openDB(ctx, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostgresWebhookRepository{
+		cfg: cfg,
+		db:  db,
+		log: log,
+	}, nil
+}
+
+func (r *PostgresWebhookRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	defer metrics.ObserveQuery("webhook.CreateWebhook")()
+	query, args, err := psql.Insert("webhooks").
+		Columns("id", "user_id", "url", "secret", "created_at").
+		Values(webhook.ID, webhook.UserID, webhook.URL, webhook.Secret, webhook.CreatedAt).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *PostgresWebhookRepository) GetWebhook(ctx context.Context, id string, userID string) (*models.Webhook, error) {
+	defer metrics.ObserveQuery("webhook.GetWebhook")()
+	query, args, err := psql.Select("id", "user_id", "url", "secret", "created_at").
+		From("webhooks").
+		Where(sq.Eq{"id": id, "user_id": userID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var webhook models.Webhook
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&webhook.ID,
+		&webhook.UserID,
+		&webhook.URL,
+		&webhook.Secret,
+		&webhook.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domainerrors.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (r *PostgresWebhookRepository) ListWebhooks(ctx context.Context, userID string) ([]*models.Webhook, error) {
+	defer metrics.ObserveQuery("webhook.ListWebhooks")()
+	query, args, err := psql.Select("id", "user_id", "url", "secret", "created_at").
+		From("webhooks").
+		Where(sq.Eq{"user_id": userID, "deleted_at": nil}).
+		OrderBy("created_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := make([]*models.Webhook, 0)
+	for rows.Next() {
+		var webhook models.Webhook
+		if err := rows.Scan(
+			&webhook.ID,
+			&webhook.UserID,
+			&webhook.URL,
+			&webhook.Secret,
+			&webhook.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, &webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (r *PostgresWebhookRepository) DeleteWebhook(ctx context.Context, id string, userID string) error {
+	defer metrics.ObserveQuery("webhook.DeleteWebhook")()
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+	deleteQuery, deleteArgs, err := psql.Update("webhooks").
+		Set("deleted_at", now).
+		Where(sq.Eq{"id": id, "user_id": userID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build delete webhook query: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, deleteQuery, deleteArgs...)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		err = domainerrors.ErrWebhookNotFound
+		return err
+	}
+
+	cancelQuery, cancelArgs, err := psql.Update("webhook_deliveries").
+		Set("status", models.DeliveryFailed).
+		Set("last_error", "webhook deleted").
+		Set("updated_at", now).
+		Where(sq.Eq{"webhook_id": id, "status": models.DeliveryPending}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build cancel deliveries query: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, cancelQuery, cancelArgs...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertDeliveries пишет доставки в транзакции обновления контента.
+func insertDeliveries(ctx context.Context, tx *sql.Tx, deliveries []*models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	builder := psql.Insert("webhook_deliveries").Columns(deliveryColumns...)
+	for _, d := range deliveries {
+		builder = builder.Values(d.ID, d.WebhookID, d.ContentID, d.Event, []byte(d.Payload), d.Status,
+			d.Attempts, d.ResponseCode, d.LastError, d.NextAttemptAt, d.CreatedAt, d.UpdatedAt)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *PostgresWebhookRepository) GetDelivery(ctx context.Context, id string, webhookID string) (*models.WebhookDelivery, error) {
+	defer metrics.ObserveQuery("webhook.GetDelivery")()
+	query, args, err := psql.Select(deliveryColumns...).
+		From("webhook_deliveries").
+		Where(sq.Eq{"id": id, "webhook_id": webhookID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	delivery, err := scanDelivery(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domainerrors.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (r *PostgresWebhookRepository) ListDeliveries(ctx context.Context, webhookID string, status *models.DeliveryStatus, limit int) ([]*models.WebhookDelivery, error) {
+	defer metrics.ObserveQuery("webhook.ListDeliveries")()
+	builder := psql.Select(deliveryColumns...).
+		From("webhook_deliveries").
+		Where(sq.Eq{"webhook_id": webhookID})
+	if status != nil {
+		builder = builder.Where(sq.Eq{"status": *status})
+	}
+
+	query, args, err := builder.
+		OrderBy("created_at DESC").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]*models.WebhookDelivery, 0, limit)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// ClaimDueDeliveries забирает готовые к отправке доставки и сдвигает их next_attempt_at
+// на время аренды, чтобы другой экземпляр сервиса не отправил их повторно.
+func (r *PostgresWebhookRepository) ClaimDueDeliveries(ctx context.Context, leaseUntil time.Time, limit int) ([]*models.DueDelivery, error) {
+	defer metrics.ObserveQuery("webhook.ClaimDueDeliveries")()
+	now := time.Now()
+	due := sq.Select("id").
+		From("webhook_deliveries").
+		Where(sq.Eq{"status": models.DeliveryPending}).
+		Where(sq.LtOrEq{"next_attempt_at": now}).
+		OrderBy("next_attempt_at").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query, args, err := psql.Update("webhook_deliveries AS d").
+		Set("next_attempt_at", leaseUntil).
+		Set("updated_at", now).
+		From("webhooks AS w").
+		Where("w.id = d.webhook_id").
+		Where(due.Prefix("d.id IN (").Suffix(")")).
+		Suffix("RETURNING d.id, d.webhook_id, d.content_id, d.event, d.payload, d.status, " +
+			"d.attempts, d.response_code, d.last_error, d.next_attempt_at, d.created_at, d.updated_at, w.url, w.secret").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]*models.DueDelivery, 0, limit)
+	for rows.Next() {
+		var d models.DueDelivery
+		var payload []byte
+		if err := rows.Scan(
+			&d.ID, &d.WebhookID, &d.ContentID, &d.Event, &payload, &d.Status,
+			&d.Attempts, &d.ResponseCode, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &d.UpdatedAt,
+			&d.URL, &d.Secret,
+		); err != nil {
+			return nil, err
+		}
+		d.Payload = payload
+		deliveries = append(deliveries, &d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *PostgresWebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	defer metrics.ObserveQuery("webhook.UpdateDelivery")()
+	query, args, err := psql.Update("webhook_deliveries").
+		Set("status", delivery.Status).
+		Set("attempts", delivery.Attempts).
+		Set("response_code", delivery.ResponseCode).
+		Set("last_error", delivery.LastError).
+		Set("next_attempt_at", delivery.NextAttemptAt).
+		Set("updated_at", delivery.UpdatedAt).
+		Where(sq.Eq{"id": delivery.ID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func scanDelivery(row sq.RowScanner) (*models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	var payload []byte
+	if err := row.Scan(
+		&d.ID, &d.WebhookID, &d.ContentID, &d.Event, &payload, &d.Status,
+		&d.Attempts, &d.ResponseCode, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &d.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	d.Payload = payload
+	return &d, nil
+}
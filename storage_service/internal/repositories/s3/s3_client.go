@@ -10,6 +10,9 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/deeelis/platform/tracing/awstrace"
 	"io"
 	"mime"
 	"net/http"
@@ -23,7 +26,6 @@ type S3Client struct {
 	client   *s3.Client
 	bucket   string
 	endpoint string
-	url      string
 }
 
 func NewS3Client(cfgS3 *config2.S3Config) (*S3Client, error) {
@@ -43,6 +45,7 @@ func NewS3Client(cfgS3 *config2.S3Config) (*S3Client, error) {
 			"",
 		)),
 		config.WithRegion(cfgS3.Region),
+		config.WithAPIOptions([]func(*middleware.Stack) error{awstrace.Middleware}),
 	)
 	if err != nil {
 		return nil, err
@@ -62,14 +65,46 @@ func NewS3Client(cfgS3 *config2.S3Config) (*S3Client, error) {
 		return nil, fmt.Errorf("не удалось создать бакет: %v", err)
 	}
 
+	if err := disableACLs(context.TODO(), client, cfgS3.Bucket); err != nil {
+		return nil, fmt.Errorf("не удалось отключить ACL бакета: %v", err)
+	}
+
 	return &S3Client{
 		client:   client,
 		bucket:   cfgS3.Bucket,
 		endpoint: cfgS3.Endpoint,
-		url:      cfgS3.URL,
 	}, nil
 }
 
+// disableACLs переводит бакет в режим BucketOwnerEnforced. Раньше изображения
+// загружались с public-read, и без этого такие объекты остались бы открытыми:
+// в этом режиме S3 игнорирует ACL, в том числе уже выданные. Хранилища без
+// поддержки ownership controls (MinIO) ACL объектов не применяют вовсе.
+func disableACLs(ctx context.Context, client *s3.Client, bucket string) error {
+	_, err := client.PutBucketOwnershipControls(ctx, &s3.PutBucketOwnershipControlsInput{
+		Bucket: aws.String(bucket),
+		OwnershipControls: &types.OwnershipControls{
+			Rules: []types.OwnershipControlsRule{{
+				ObjectOwnership: types.ObjectOwnershipBucketOwnerEnforced,
+			}},
+		},
+	})
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotImplemented" {
+		return nil
+	}
+	return err
+}
+
+// Ping проверяет, что бакет доступен с текущими учётными данными.
+func (s *S3Client) Ping(ctx context.Context) error {
+	_, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(s.bucket),
+	})
+	return err
+}
+
 func (s *S3Client) UploadImage(ctx context.Context, data []byte, objectKey string) error {
 	contentType := http.DetectContentType(data)
 	if contentType == "application/octet-stream" {
@@ -85,7 +120,6 @@ func (s *S3Client) UploadImage(ctx context.Context, data []byte, objectKey strin
 		Key:         aws.String(objectKey),
 		Body:        bytes.NewReader(data),
 		ContentType: aws.String(contentType),
-		ACL:         types.ObjectCannedACLPublicRead,
 	})
 	return err
 }
@@ -118,6 +152,20 @@ func (s *S3Client) DeleteImage(ctx context.Context, objectKey string) error {
 	return err
 }
 
+func (s *S3Client) TagImage(ctx context.Context, objectKey string, tags map[string]string) error {
+	tagSet := make([]types.Tag, 0, len(tags))
+	for k, v := range tags {
+		tagSet = append(tagSet, types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
+	_, err := s.client.PutObjectTagging(ctx, &s3.PutObjectTaggingInput{
+		Bucket:  aws.String(s.bucket),
+		Key:     aws.String(objectKey),
+		Tagging: &types.Tagging{TagSet: tagSet},
+	})
+	return err
+}
+
 func (s *S3Client) ListImages(ctx context.Context, prefix string) ([]string, error) {
 	result, err := s.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
 		Bucket: aws.String(s.bucket),
@@ -137,11 +185,3 @@ func (s *S3Client) ListImages(ctx context.Context, prefix string) ([]string, err
 	}
 	return objects, nil
 }
-
-func (s *S3Client) GetImageURL(objectKey string) string {
-	return strings.Join([]string{
-		s.url,
-		s.bucket,
-		objectKey,
-	}, "/")
-}
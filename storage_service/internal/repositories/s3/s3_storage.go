@@ -3,10 +3,8 @@ package s3
 import (
 	"context"
 	"fmt"
-	"github.com/google/uuid"
 	"log/slog"
 	config2 "storage_service/internal/config"
-	"time"
 )
 
 type S3ImageStorage struct {
@@ -24,29 +22,27 @@ func NewS3ImageStorage(cfg *config2.S3Config, log *slog.Logger) (*S3ImageStorage
 	}, nil
 }
 
-//func (s *S3ImageStorage) StoreImage(ctx context.Context, data []byte) (string, error) {
-//	key := fmt.Sprintf("images/%d/%s", time.Now().Unix(), uuid.New().String())
-//
-//	err := s.client.UploadImage(ctx, s.client.bucket, key)
-//	if err != nil {
-//		return "", fmt.Errorf("failed to upload image to S3: %w", err)
-//	}
-//
-//	return key, nil
-//}
-
-func (s *S3ImageStorage) StoreImage(ctx context.Context, data []byte) (string, error) {
-	key := fmt.Sprintf("images/%d/%s.jpg", time.Now().Unix(), uuid.New().String())
-
-	err := s.client.UploadImage(ctx, data, key)
-	if err != nil {
-		return "", fmt.Errorf("failed to upload image to S3: %w", err)
+func (s *S3ImageStorage) Bucket() string {
+	return s.client.bucket
+}
+
+func (s *S3ImageStorage) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx)
+}
+
+// TagImage прикрепляет вердикт модерации к уже загруженному объекту.
+func (s *S3ImageStorage) TagImage(ctx context.Context, key string, tags map[string]string) error {
+	if err := s.client.TagImage(ctx, key, tags); err != nil {
+		return fmt.Errorf("failed to tag image in S3: %w", err)
 	}
 
-	return key, nil
+	return nil
 }
 
-func (s *S3ImageStorage) GetImageURL(ctx context.Context, key string) (string, error) {
-	imageURL := s.client.GetImageURL(key)
-	return imageURL, nil
+func (s *S3ImageStorage) DeleteImage(ctx context.Context, key string) error {
+	if err := s.client.DeleteImage(ctx, key); err != nil {
+		return fmt.Errorf("failed to delete image from S3: %w", err)
+	}
+
+	return nil
 }
@@ -2,17 +2,21 @@ package services
 
 import (
 	"context"
-	"encoding/base64"
 	"errors"
 	"fmt"
+	"github.com/deeelis/platform/requestid"
 	"log"
 	"log/slog"
 	"storage_service/internal/config"
+	domainerrors "storage_service/internal/domain/errors"
 	"storage_service/internal/domain/models"
 	"storage_service/internal/domain/repositories"
+	"storage_service/internal/metrics"
 	"storage_service/internal/repositories/cache"
+	"storage_service/internal/repositories/events"
 	repos "storage_service/internal/repositories/repos/postgres"
 	"storage_service/internal/repositories/s3"
+	"storage_service/logger"
 	"time"
 )
 
@@ -21,6 +25,8 @@ type storageUsecase struct {
 	contentRepo repositories.ContentRepository
 	cacheRepo   repositories.CacheRepository
 	imageStore  repositories.ImageStorage
+	events      repositories.EventBus
+	webhookRepo repositories.WebhookRepository
 	log         *slog.Logger
 }
 
@@ -40,42 +46,36 @@ func NewStorageUsecase(ctx context.Context, cfg *config.Config, log *slog.Logger
 		return nil, err
 	}
 
+	eventBus, err := events.NewRedisEventBus(&cfg.Cache, log)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookRepo, err := repos.NewPostgresWebhookRepository(ctx, &cfg.Repo, log)
+	if err != nil {
+		return nil, err
+	}
+
 	return &storageUsecase{
 		contentRepo: contentRepo,
 		cacheRepo:   cacheRepo,
 		imageStore:  imageStore,
+		events:      eventBus,
+		webhookRepo: webhookRepo,
 		log:         log,
 		cfg:         cfg,
 	}, nil
 }
 
 func (s *storageUsecase) GetTextContent(ctx context.Context, id string) (*models.TextContent, error) {
-	var content *models.Content
-	if cached, err := s.cacheRepo.Get(ctx, id); err == nil {
-		c, ok := cached.(*models.Content)
-		if !ok {
-			content, err = s.contentRepo.GetContent(ctx, id)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			content = c
-		}
-	} else {
-		content, err = s.contentRepo.GetContent(ctx, id)
-		if err != nil {
-			return nil, err
-		}
+	content, err := s.GetContent(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	//content, err := s.contentRepo.GetContent(ctx, id)
-	//if err != nil {
-	//	return nil, err
-	//}
 	s.log.Debug(content.ID)
 	s.log.Debug(string(content.Status))
 
-	var err error
 	var textContent *models.TextContent
 	if content.Status == models.StatusCompleted {
 		textContent, err = s.contentRepo.GetTextContent(ctx, content.ID)
@@ -100,67 +100,43 @@ func (s *storageUsecase) GetTextContent(ctx context.Context, id string) (*models
 		}
 	}
 
-	if err := s.cacheRepo.Set(ctx, id, content, 300); err != nil {
-		log.Println(err.Error())
-	}
-
 	return textContent, nil
 }
 
 func (s *storageUsecase) GetImageContent(ctx context.Context, id string) (*models.ImageContent, error) {
-	var content *models.Content
-	if cached, err := s.cacheRepo.Get(ctx, id); err == nil {
-		c, ok := cached.(*models.Content)
-		if !ok {
-			content, err = s.contentRepo.GetContent(ctx, id)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			content = c
-		}
-	} else {
-		content, err = s.contentRepo.GetContent(ctx, id)
-		if err != nil {
-			return nil, err
-		}
+	content, err := s.GetContent(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	var err error
-	var imageContent *models.ImageContent
 	if content.Status == models.StatusCompleted {
-		imageContent, err = s.contentRepo.GetImageContent(ctx, content.ID)
-		if err != nil {
-			return nil, err
-		}
-		return imageContent, nil
-	} else {
-		imageContent = &models.ImageContent{
-			Content: models.Content{
-				ID:        content.ID,
-				UserID:    content.UserID,
-				Type:      content.Type,
-				Status:    content.Status,
-				CreatedAt: content.CreatedAt,
-				UpdatedAt: content.UpdatedAt,
-				Metadata:  make(map[string]string),
-			},
-			S3Key: "",
-		}
+		return s.contentRepo.GetImageContent(ctx, content.ID)
 	}
 
-	if err := s.cacheRepo.Set(ctx, id, content, 300); err != nil {
-		log.Println(err.Error())
+	// ключ нужен и до конца модерации: по нему шлюз отличает ещё не
+	// обработанное изображение от отсутствующего и отдаёт его модератору
+	s3Key, err := s.contentRepo.GetImageKey(ctx, content.ID)
+	if err != nil {
+		return nil, err
 	}
-
-	return imageContent, nil
+	return &models.ImageContent{
+		Content: models.Content{
+			ID:        content.ID,
+			UserID:    content.UserID,
+			Type:      content.Type,
+			Status:    content.Status,
+			CreatedAt: content.CreatedAt,
+			UpdatedAt: content.UpdatedAt,
+			Metadata:  make(map[string]string),
+		},
+		S3Key: s3Key,
+	}, nil
 }
 
 func (s *storageUsecase) GetContent(ctx context.Context, id string) (*models.Content, error) {
-	if cached, err := s.cacheRepo.Get(ctx, id); err == nil {
-		if content, ok := cached.(*models.Content); ok {
-			return content, nil
-		}
+	var cached models.Content
+	if ok, err := s.cacheRepo.Get(ctx, id, &cached); err == nil && ok {
+		return &cached, nil
 	}
 
 	content, err := s.contentRepo.GetContent(ctx, id)
@@ -186,13 +162,10 @@ func (s *storageUsecase) StoreTextAnalysis(ctx context.Context, content *models.
 	content.Status = models.StatusCompleted
 	content.UpdatedAt = time.Now()
 
-	if err := s.contentRepo.UpdateTextContent(ctx, content); err != nil {
+	if err := s.contentRepo.UpdateTextContent(ctx, content, nil); err != nil {
 		return err
 	}
-
-	if err := s.cacheRepo.Set(ctx, content.ID, content, 300); err != nil {
-		log.Println(err.Error())
-	}
+	s.evict(ctx, content.ID)
 
 	return nil
 }
@@ -208,12 +181,10 @@ func (s *storageUsecase) StoreImageAnalysis(ctx context.Context, content *models
 	content.Status = models.StatusCompleted
 	content.UpdatedAt = time.Now()
 
-	if err := s.contentRepo.UpdateImageContent(ctx, content); err != nil {
+	if err := s.contentRepo.UpdateImageContent(ctx, content, nil); err != nil {
 		return err
 	}
-
-	if err := s.cacheRepo.Set(ctx, content.ID, content, 300); err != nil {
-	}
+	s.evict(ctx, content.ID)
 
 	return nil
 }
@@ -232,73 +203,376 @@ func (s *storageUsecase) ProcessTextMessage(ctx context.Context, msg *models.Tex
 		OriginalText: msg.Content,
 	}
 
-	if err := s.contentRepo.UpdateTextContent(ctx, textContent); err != nil {
-		return fmt.Errorf("failed to update text content: %w", err)
+	event := &models.ContentEvent{
+		ContentID:       msg.ID,
+		UserID:          msg.UserID,
+		Type:            models.ContentTypeText,
+		Status:          models.StatusCompleted,
+		Metadata:        msg.Analysis,
+		OriginalContent: msg.Content,
+		OccurredAt:      time.Now(),
+	}
+	deliveries, err := newWebhookDeliveries(ctx, s.webhookRepo, event)
+	if err != nil {
+		return err
 	}
 
-	if err := s.cacheRepo.Set(ctx, textContent.ID, textContent, 300); err != nil {
-		log.Printf("cache set error: %v", err)
+	if err := s.contentRepo.UpdateTextContent(ctx, textContent, deliveries); err != nil {
+		// результат пришёл после удаления: событие и кеш снова показали бы запись
+		if errors.Is(err, domainerrors.ErrContentNotFound) {
+			s.log.Info("analysis result for deleted content dropped", slog.String("content_id", msg.ID))
+			return nil
+		}
+		return fmt.Errorf("failed to update text content: %w", err)
 	}
+	metrics.ObserveCompletion(string(models.ContentTypeText), textContent.CreatedAt)
+	s.evict(ctx, textContent.ID)
+
+	s.publishEvent(ctx, event)
 	return nil
 }
 
 func (s *storageUsecase) ProcessImageMessage(ctx context.Context, msg *models.ImageMessage) error {
-	data, err := base64.StdEncoding.DecodeString(msg.Data)
-	if err != nil {
-		return fmt.Errorf("can't decode image")
+	if msg.Key == "" {
+		return errors.New("image key cannot be empty")
 	}
-
-	key, err := s.imageStore.StoreImage(ctx, data)
-	if err != nil {
-		return err
+	if msg.Bucket != "" && msg.Bucket != s.imageStore.Bucket() {
+		return fmt.Errorf("image stored in unknown bucket %q", msg.Bucket)
 	}
 
-	url, err := s.imageStore.GetImageURL(ctx, key)
-	if err != nil {
+	status := models.StatusCompleted
+	verdict := "safe"
+	metadata := make(map[string]string)
+	if msg.Error != "" {
+		status = models.StatusFailed
+		verdict = "failed"
+		metadata["error"] = msg.Error
+	} else {
+		if msg.IsNsfw {
+			verdict = "nsfw"
+		}
+		metadata["drawings"] = fmt.Sprintf("%f", msg.NsfwScores.Drawings)
+		metadata["sexy"] = fmt.Sprintf("%f", msg.NsfwScores.Sexy)
+		metadata["porn"] = fmt.Sprintf("%f", msg.NsfwScores.Porn)
+		metadata["neutral"] = fmt.Sprintf("%f", msg.NsfwScores.Neutral)
+		metadata["hentai"] = fmt.Sprintf("%f", msg.NsfwScores.Hentai)
+		metadata["is_nsfw"] = fmt.Sprintf("%t", msg.IsNsfw)
+	}
+
+	// объект загружен шлюзом при приёме, здесь к нему только прикрепляется вердикт
+	if err := s.imageStore.TagImage(ctx, msg.Key, map[string]string{
+		"content_id": msg.ID,
+		"moderation": verdict,
+	}); err != nil {
 		return err
 	}
 
-	metadata := make(map[string]string)
-	metadata["drawings"] = fmt.Sprintf("%f", msg.NsfwScores.Drawings)
-	metadata["sexy"] = fmt.Sprintf("%f", msg.NsfwScores.Sexy)
-	metadata["porn"] = fmt.Sprintf("%f", msg.NsfwScores.Porn)
-	metadata["neutral"] = fmt.Sprintf("%f", msg.NsfwScores.Neutral)
-	metadata["hentai"] = fmt.Sprintf("%f", msg.NsfwScores.Hentai)
-
 	content := &models.ImageContent{
 		Content: models.Content{
 			ID:        msg.ID,
 			UserID:    msg.UserID,
 			Type:      models.ContentTypeImage,
-			Status:    models.StatusCompleted,
+			Status:    status,
 			Metadata:  metadata,
 			UpdatedAt: time.Now(),
 		},
-		S3Key: url,
+		S3Key: msg.Key,
 	}
 
-	if err := s.contentRepo.UpdateImageContent(ctx, content); err != nil {
-		return fmt.Errorf("failed to update image content: %w", err)
+	// ключ объекта в событие не попадает: изображение выдаёт шлюз после проверки доступа
+	event := &models.ContentEvent{
+		ContentID:  content.ID,
+		UserID:     content.UserID,
+		Type:       models.ContentTypeImage,
+		Status:     content.Status,
+		Metadata:   content.Metadata,
+		OccurredAt: time.Now(),
+	}
+	deliveries, err := newWebhookDeliveries(ctx, s.webhookRepo, event)
+	if err != nil {
+		return err
 	}
 
-	if err := s.cacheRepo.Set(ctx, content.ID, content, 300); err != nil {
-		log.Printf("cache set error: %v", err)
+	if err := s.contentRepo.UpdateImageContent(ctx, content, deliveries); err != nil {
+		if errors.Is(err, domainerrors.ErrContentNotFound) {
+			s.log.Info("analysis result for deleted content dropped", slog.String("content_id", content.ID))
+			return nil
+		}
+		return fmt.Errorf("failed to update image content: %w", err)
+	}
+	if content.Status == models.StatusCompleted {
+		metrics.ObserveCompletion(string(models.ContentTypeImage), content.CreatedAt)
 	}
+	s.evict(ctx, content.ID)
+
+	s.publishEvent(ctx, event)
 	return nil
 }
 
-func (s *storageUsecase) CreateContentRecord(ctx context.Context, contentType models.ContentType, contentID string) error {
-	content := &models.Content{
-		ID:        contentID,
-		Type:      contentType,
-		Status:    models.StatusProcessing,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+func (s *storageUsecase) CreateContentRecord(ctx context.Context, content *models.Content) error {
+	if err := s.checkImageObject(content); err != nil {
+		return err
 	}
 
+	content.RequestID = requestid.FromContext(ctx)
+	content.Status = models.StatusProcessing
+	content.CreatedAt = time.Now()
+	content.UpdatedAt = content.CreatedAt
+
 	if err := s.contentRepo.CreateContentRecord(ctx, content); err != nil {
 		return fmt.Errorf("failed to create content record: %w", err)
 	}
 
 	return nil
 }
+
+func (s *storageUsecase) CreateContentRecords(ctx context.Context, contents []*models.Content) (int, error) {
+	now := time.Now()
+	requestID := requestid.FromContext(ctx)
+	for _, content := range contents {
+		if content.ID == "" {
+			return 0, errors.New("content ID cannot be empty")
+		}
+		if err := s.checkImageObject(content); err != nil {
+			return 0, err
+		}
+		content.RequestID = requestID
+		content.Status = models.StatusProcessing
+		content.CreatedAt = now
+		content.UpdatedAt = now
+	}
+
+	registered, err := s.contentRepo.CreateContentRecords(ctx, contents)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create content records: %w", err)
+	}
+
+	return registered, nil
+}
+
+// checkImageObject не даёт зарегистрировать объект из чужого бакета:
+// очистка удаляет объекты только в своём.
+func (s *storageUsecase) checkImageObject(content *models.Content) error {
+	if content.Image == nil {
+		return nil
+	}
+	if content.Image.Bucket != "" && content.Image.Bucket != s.imageStore.Bucket() {
+		return fmt.Errorf("%w: image stored in unknown bucket %q", domainerrors.ErrInvalidContent, content.Image.Bucket)
+	}
+	return nil
+}
+
+func (s *storageUsecase) ListContent(ctx context.Context, filter *models.ContentFilter) ([]*models.Content, error) {
+	if filter.UserID == "" {
+		return nil, errors.New("user ID cannot be empty")
+	}
+
+	items, err := s.contentRepo.ListContent(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list content: %w", err)
+	}
+
+	return items, nil
+}
+
+func (s *storageUsecase) DeleteContent(ctx context.Context, userID string, contentID string) (time.Time, error) {
+	const op = "services.storageUsecase.DeleteContent"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("content_id", contentID),
+	)
+
+	deletedAt := time.Now()
+	if err := s.contentRepo.SoftDeleteContent(ctx, contentID, userID, deletedAt); err != nil {
+		return time.Time{}, fmt.Errorf("failed to delete content: %w", err)
+	}
+
+	if err := s.cacheRepo.Delete(ctx, contentID); err != nil {
+		log.Warn("cache evict error", logger.Err(err))
+	}
+
+	if s.cfg.Purge.GracePeriod <= 0 {
+		if err := s.purgeContent(ctx, contentID); err != nil {
+			// запись уже помечена удалённой, очистка повторит попытку
+			log.Warn("immediate purge failed", logger.Err(err))
+			return deletedAt, nil
+		}
+		log.Info("content purged without grace period")
+		return deletedAt, nil
+	}
+
+	log.Info("content marked as deleted")
+	return deletedAt.Add(s.cfg.Purge.GracePeriod), nil
+}
+
+// RestoreContent возвращает запись, пока не истёк срок, после которого её
+// удаляет очистка; позже запись считается не найденной, даже если очистка
+// до неё ещё не дошла.
+func (s *storageUsecase) RestoreContent(ctx context.Context, userID string, contentID string) error {
+	if err := s.contentRepo.RestoreContent(ctx, contentID, userID, time.Now().Add(-s.cfg.Purge.GracePeriod)); err != nil {
+		return fmt.Errorf("failed to restore content: %w", err)
+	}
+
+	s.evict(ctx, contentID)
+
+	return nil
+}
+
+func (s *storageUsecase) PurgeDeletedContent(ctx context.Context) (int, error) {
+	const op = "services.storageUsecase.PurgeDeletedContent"
+	log := s.log.With(slog.String("op", op))
+
+	items, err := s.contentRepo.ListDeletedBefore(ctx, time.Now().Add(-s.cfg.Purge.GracePeriod), s.cfg.Purge.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list deleted content: %w", err)
+	}
+
+	purged := 0
+	for _, item := range items {
+		if err := s.purgeContent(ctx, item.ID); err != nil {
+			log.Error("failed to purge content",
+				slog.String("content_id", item.ID),
+				logger.Err(err))
+			continue
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// purgeContent сначала удаляет объект, потом строки: удаление объекта
+// идемпотентно, и при сбое запись остаётся помеченной, так что следующий
+// проход очистки повторит его, а не оставит объект сиротой.
+func (s *storageUsecase) purgeContent(ctx context.Context, contentID string) error {
+	s3Key, err := s.contentRepo.GetImageKey(ctx, contentID)
+	if err != nil {
+		return fmt.Errorf("failed to get image key: %w", err)
+	}
+
+	if s3Key != "" {
+		if err := s.imageStore.DeleteImage(ctx, s3Key); err != nil {
+			return fmt.Errorf("failed to delete image object %s: %w", s3Key, err)
+		}
+	}
+
+	if err := s.contentRepo.DeleteContent(ctx, contentID); err != nil {
+		return fmt.Errorf("failed to purge content: %w", err)
+	}
+
+	s.evict(ctx, contentID)
+
+	return nil
+}
+
+func (s *storageUsecase) WatchContent(ctx context.Context, userID string, contentID string) (<-chan *models.ContentEvent, func(), error) {
+	// подписываемся до чтения состояния, чтобы не пропустить переход между ними
+	updates, unsubscribe, err := s.events.Subscribe(ctx, contentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to subscribe to content events: %w", err)
+	}
+
+	snapshot, err := s.contentSnapshot(ctx, contentID)
+	if err != nil {
+		unsubscribe()
+		return nil, nil, err
+	}
+	if snapshot.UserID != userID {
+		unsubscribe()
+		return nil, nil, domainerrors.ErrAccessDenied
+	}
+
+	out := make(chan *models.ContentEvent, 1)
+	out <- snapshot
+	if snapshot.Status.IsTerminal() {
+		unsubscribe()
+		close(out)
+		return out, func() {}, nil
+	}
+
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-updates:
+				if !ok {
+					return
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+				if event.Status.IsTerminal() {
+					return
+				}
+			}
+		}
+	}()
+
+	return out, unsubscribe, nil
+}
+
+func (s *storageUsecase) contentSnapshot(ctx context.Context, contentID string) (*models.ContentEvent, error) {
+	content, err := s.contentRepo.GetContent(ctx, contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &models.ContentEvent{
+		ContentID:  content.ID,
+		UserID:     content.UserID,
+		Type:       content.Type,
+		Status:     content.Status,
+		OccurredAt: content.UpdatedAt,
+	}
+
+	if content.Status != models.StatusCompleted {
+		return event, nil
+	}
+
+	switch content.Type {
+	case models.ContentTypeText:
+		textContent, err := s.contentRepo.GetTextContent(ctx, contentID)
+		if err != nil {
+			return nil, err
+		}
+		event.Metadata = textContent.Metadata
+		event.OriginalContent = textContent.OriginalText
+	case models.ContentTypeImage:
+		imageContent, err := s.contentRepo.GetImageContent(ctx, contentID)
+		if err != nil {
+			return nil, err
+		}
+		event.Metadata = imageContent.Metadata
+	}
+
+	return event, nil
+}
+
+// evict сбрасывает запись из кеша после обновления. Класть в кеш само
+// обновление нельзя: в нём нет владельца и времени регистрации, и GetContent
+// отдал бы такую запись как полную.
+func (s *storageUsecase) evict(ctx context.Context, contentID string) {
+	if err := s.cacheRepo.Delete(ctx, contentID); err != nil {
+		s.log.Warn("cache evict error", slog.String("content_id", contentID), logger.Err(err))
+	}
+}
+
+func (s *storageUsecase) publishEvent(ctx context.Context, event *models.ContentEvent) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	log := s.log.With(slog.String("request_id", requestid.FromContext(ctx)))
+
+	if err := s.events.Publish(ctx, event); err != nil {
+		log.Warn("failed to publish content event",
+			slog.String("content_id", event.ContentID),
+			slog.String("status", string(event.Status)),
+			logger.Err(err))
+	}
+}
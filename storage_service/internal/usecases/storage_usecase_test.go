@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"storage_service/internal/config"
+	domainerrors "storage_service/internal/domain/errors"
+	"storage_service/internal/domain/models"
+	"storage_service/internal/domain/repositories"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeContentRepo хранит записи в памяти; обновление анализа меняет только
+// статус и метаданные, как и UPDATE в Postgres.
+type fakeContentRepo struct {
+	repositories.ContentRepository
+
+	mu        sync.Mutex
+	contents  map[string]models.Content
+	imageKeys map[string]string
+}
+
+func (f *fakeContentRepo) GetContent(ctx context.Context, id string) (*models.Content, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	content, ok := f.contents[id]
+	if !ok {
+		return nil, domainerrors.ErrContentNotFound
+	}
+	return &content, nil
+}
+
+func (f *fakeContentRepo) UpdateTextContent(ctx context.Context, content *models.TextContent, deliveries []*models.WebhookDelivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stored, ok := f.contents[content.ID]
+	if !ok {
+		return domainerrors.ErrContentNotFound
+	}
+	stored.Status = content.Status
+	stored.Metadata = content.Metadata
+	stored.UpdatedAt = content.UpdatedAt
+	f.contents[content.ID] = stored
+	content.CreatedAt = stored.CreatedAt
+	return nil
+}
+
+func (f *fakeContentRepo) GetImageKey(ctx context.Context, id string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.imageKeys[id], nil
+}
+
+func (f *fakeContentRepo) RestoreContent(ctx context.Context, id string, userID string, deletedAfter time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stored, ok := f.contents[id]
+	if !ok || stored.UserID != userID || stored.DeletedAt == nil || !stored.DeletedAt.After(deletedAfter) {
+		return domainerrors.ErrContentNotFound
+	}
+	stored.DeletedAt = nil
+	f.contents[id] = stored
+	return nil
+}
+
+// fakeCache, как и Redis, хранит значения в JSON.
+type fakeCache struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func (f *fakeCache) Get(ctx context.Context, key string, dest interface{}) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, ok := f.data[key]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(data, dest)
+}
+
+func (f *fakeCache) Set(ctx context.Context, key string, value interface{}, ttl int) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[key] = data
+	return nil
+}
+
+func (f *fakeCache) Delete(ctx context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.data, key)
+	return nil
+}
+
+type fakeEventBus struct {
+	repositories.EventBus
+	published []*models.ContentEvent
+}
+
+func (f *fakeEventBus) Publish(ctx context.Context, event *models.ContentEvent) error {
+	f.published = append(f.published, event)
+	return nil
+}
+
+type noWebhooksRepo struct {
+	repositories.WebhookRepository
+}
+
+func (noWebhooksRepo) ListWebhooks(ctx context.Context, userID string) ([]*models.Webhook, error) {
+	return nil, nil
+}
+
+func TestGetContentAfterProcessTextMessageKeepsOwner(t *testing.T) {
+	const owner = "user-1"
+	createdAt := time.Now().Add(-time.Minute).UTC()
+
+	repo := &fakeContentRepo{contents: map[string]models.Content{
+		"c1": {
+			ID:        "c1",
+			UserID:    owner,
+			Type:      models.ContentTypeText,
+			Status:    models.StatusProcessing,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		},
+	}}
+	s := &storageUsecase{
+		contentRepo: repo,
+		cacheRepo:   &fakeCache{data: make(map[string][]byte)},
+		events:      &fakeEventBus{},
+		webhookRepo: noWebhooksRepo{},
+		log:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	ctx := context.Background()
+
+	// запись в состоянии PROCESSING уже лежит в кеше
+	if _, err := s.GetContent(ctx, "c1"); err != nil {
+		t.Fatalf("GetContent: %v", err)
+	}
+
+	if err := s.ProcessTextMessage(ctx, &models.TextMessage{
+		ID:       "c1",
+		UserID:   owner,
+		Content:  "hello",
+		Analysis: map[string]string{"toxicity": "0.01"},
+	}); err != nil {
+		t.Fatalf("ProcessTextMessage: %v", err)
+	}
+
+	got, err := s.GetContent(ctx, "c1")
+	if err != nil {
+		t.Fatalf("GetContent: %v", err)
+	}
+	if got.UserID != owner {
+		t.Errorf("UserID = %q, want %q", got.UserID, owner)
+	}
+	if got.Status != models.StatusCompleted {
+		t.Errorf("Status = %s, want %s", got.Status, models.StatusCompleted)
+	}
+	if !got.CreatedAt.Equal(createdAt) {
+		t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, createdAt)
+	}
+}
+
+func TestProcessTextMessageDropsResultForDeletedContent(t *testing.T) {
+	// удалённой записи UPDATE не находит, как и несуществующей
+	repo := &fakeContentRepo{contents: map[string]models.Content{}}
+	cache := &fakeCache{data: make(map[string][]byte)}
+	events := &fakeEventBus{}
+	s := &storageUsecase{
+		contentRepo: repo,
+		cacheRepo:   cache,
+		events:      events,
+		webhookRepo: noWebhooksRepo{},
+		log:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	if err := s.ProcessTextMessage(context.Background(), &models.TextMessage{
+		ID:      "c1",
+		UserID:  "user-1",
+		Content: "hello",
+	}); err != nil {
+		t.Fatalf("ProcessTextMessage: %v", err)
+	}
+
+	if _, ok := cache.data["c1"]; ok {
+		t.Error("result for deleted content was cached")
+	}
+	if len(events.published) != 0 {
+		t.Errorf("published %d events for deleted content", len(events.published))
+	}
+}
+
+func TestGetImageContentReturnsKeyBeforeModeration(t *testing.T) {
+	repo := &fakeContentRepo{
+		contents: map[string]models.Content{
+			"i1": {ID: "i1", UserID: "user-1", Type: models.ContentTypeImage, Status: models.StatusProcessing},
+		},
+		imageKeys: map[string]string{"i1": "images/user-1/i1"},
+	}
+	s := &storageUsecase{
+		contentRepo: repo,
+		cacheRepo:   &fakeCache{data: make(map[string][]byte)},
+		log:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	image, err := s.GetImageContent(context.Background(), "i1")
+	if err != nil {
+		t.Fatalf("GetImageContent: %v", err)
+	}
+	if image.S3Key != "images/user-1/i1" {
+		t.Errorf("S3Key = %q, want the registered key", image.S3Key)
+	}
+	if image.Status != models.StatusProcessing {
+		t.Errorf("Status = %s, want %s", image.Status, models.StatusProcessing)
+	}
+}
+
+func TestRestoreContentOnlyWithinGracePeriod(t *testing.T) {
+	recent := time.Now().Add(-10 * time.Minute)
+	expired := time.Now().Add(-2 * time.Hour)
+	repo := &fakeContentRepo{contents: map[string]models.Content{
+		"recent":  {ID: "recent", UserID: "user-1", DeletedAt: &recent},
+		"expired": {ID: "expired", UserID: "user-1", DeletedAt: &expired},
+	}}
+	s := &storageUsecase{
+		cfg:         &config.Config{Purge: config.PurgeConfig{GracePeriod: time.Hour}},
+		contentRepo: repo,
+		cacheRepo:   &fakeCache{data: make(map[string][]byte)},
+		log:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	ctx := context.Background()
+
+	if err := s.RestoreContent(ctx, "user-1", "recent"); err != nil {
+		t.Errorf("RestoreContent within grace period: %v", err)
+	}
+	// очистка могла ещё не дойти до записи, но срок восстановления уже истёк
+	if err := s.RestoreContent(ctx, "user-1", "expired"); !errors.Is(err, domainerrors.ErrContentNotFound) {
+		t.Errorf("RestoreContent after grace period = %v, want %v", err, domainerrors.ErrContentNotFound)
+	}
+}
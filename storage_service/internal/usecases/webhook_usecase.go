@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	mathrand "math/rand"
+	"storage_service/internal/config"
+	domainerrors "storage_service/internal/domain/errors"
+	"storage_service/internal/domain/models"
+	"storage_service/internal/domain/repositories"
+	repos "storage_service/internal/repositories/repos/postgres"
+	"storage_service/internal/webhooks"
+	"storage_service/logger"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	minSecretLength      = 16
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 200
+)
+
+type webhookUsecase struct {
+	cfg         *config.Config
+	webhookRepo repositories.WebhookRepository
+	addresses   *webhooks.AddressPolicy
+	sender      *webhooks.Sender
+	log         *slog.Logger
+}
+
+func NewWebhookUsecase(ctx context.Context, cfg *config.Config, log *slog.Logger) (repositories.WebhookUsecase, error) {
+	webhookRepo, err := repos.NewPostgresWebhookRepository(ctx, &cfg.Repo, log)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := webhooks.NewAddressPolicy(cfg.Webhooks.AllowedNetworks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookUsecase{
+		cfg:         cfg,
+		webhookRepo: webhookRepo,
+		addresses:   addresses,
+		sender:      webhooks.NewSender(webhooks.NewClient(cfg.Webhooks.Timeout, addresses)),
+		log:         log,
+	}, nil
+}
+
+func (w *webhookUsecase) CreateWebhook(ctx context.Context, userID string, rawURL string, secret string) (*models.Webhook, error) {
+	u, err := w.addresses.CheckURL(ctx, rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domainerrors.ErrInvalidWebhook, err)
+	}
+
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("failed to generate secret: %w", err)
+		}
+		secret = hex.EncodeToString(buf)
+	}
+	if len(secret) < minSecretLength {
+		return nil, fmt.Errorf("%w: secret must be at least %d characters", domainerrors.ErrInvalidWebhook, minSecretLength)
+	}
+
+	webhook := &models.Webhook{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		URL:       u.String(),
+		Secret:    secret,
+		CreatedAt: time.Now(),
+	}
+
+	if err := w.webhookRepo.CreateWebhook(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+func (w *webhookUsecase) ListWebhooks(ctx context.Context, userID string) ([]*models.Webhook, error) {
+	hooks, err := w.webhookRepo.ListWebhooks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	return hooks, nil
+}
+
+func (w *webhookUsecase) DeleteWebhook(ctx context.Context, userID string, webhookID string) error {
+	if err := w.webhookRepo.DeleteWebhook(ctx, webhookID, userID); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+func (w *webhookUsecase) ListDeliveries(ctx context.Context, userID string, webhookID string, status string, limit int) ([]*models.WebhookDelivery, error) {
+	if _, err := w.webhookRepo.GetWebhook(ctx, webhookID, userID); err != nil {
+		return nil, err
+	}
+
+	var filter *models.DeliveryStatus
+	if status != "" {
+		st := models.DeliveryStatus(status)
+		switch st {
+		case models.DeliveryPending, models.DeliverySucceeded, models.DeliveryFailed:
+			filter = &st
+		default:
+			return nil, fmt.Errorf("%w: unknown delivery status %q", domainerrors.ErrInvalidWebhook, status)
+		}
+	}
+
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	}
+	if limit > maxDeliveryLimit {
+		limit = maxDeliveryLimit
+	}
+
+	deliveries, err := w.webhookRepo.ListDeliveries(ctx, webhookID, filter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (w *webhookUsecase) Redeliver(ctx context.Context, userID string, webhookID string, deliveryID string) (*models.WebhookDelivery, error) {
+	if _, err := w.webhookRepo.GetWebhook(ctx, webhookID, userID); err != nil {
+		return nil, err
+	}
+
+	delivery, err := w.webhookRepo.GetDelivery(ctx, deliveryID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status != models.DeliveryFailed {
+		return nil, domainerrors.ErrNotRedeliverable
+	}
+
+	now := time.Now()
+	delivery.Status = models.DeliveryPending
+	delivery.Attempts = 0
+	delivery.LastError = ""
+	delivery.NextAttemptAt = now
+	delivery.UpdatedAt = now
+
+	if err := w.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to requeue delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+func (w *webhookUsecase) DispatchDue(ctx context.Context) (int, error) {
+	const op = "services.webhookUsecase.DispatchDue"
+	log := w.log.With(slog.String("op", op))
+
+	// аренда с запасом на таймаут запроса: если процесс упадёт, доставка вернётся в очередь
+	leaseUntil := time.Now().Add(2 * w.cfg.Webhooks.Timeout)
+	due, err := w.webhookRepo.ClaimDueDeliveries(ctx, leaseUntil, w.cfg.Webhooks.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+
+	// вся пачка отправляется параллельно и укладывается в полтаймаута до
+	// конца аренды, чтобы результат был записан раньше, чем доставку заберёт
+	// другая реплика
+	sendCtx, cancel := context.WithDeadline(ctx, leaseUntil.Add(-w.cfg.Webhooks.Timeout/2))
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, d := range due {
+		wg.Add(1)
+		go func(d *models.DueDelivery) {
+			defer wg.Done()
+			w.attempt(ctx, sendCtx, log, d)
+		}(d)
+	}
+	wg.Wait()
+
+	return len(due), nil
+}
+
+// attempt отправляет одну доставку и сохраняет результат. Запрос ограничен
+// sendCtx, запись результата — только ctx.
+func (w *webhookUsecase) attempt(ctx, sendCtx context.Context, log *slog.Logger, d *models.DueDelivery) {
+	startTime := time.Now()
+	code, sendErr := w.sender.Send(sendCtx, &webhooks.Delivery{
+		ID:      d.ID,
+		Event:   d.Event,
+		URL:     d.URL,
+		Secret:  d.Secret,
+		Payload: d.Payload,
+	})
+
+	delivery := d.WebhookDelivery
+	delivery.Attempts++
+	delivery.ResponseCode = code
+	delivery.UpdatedAt = time.Now()
+
+	switch {
+	case sendErr == nil:
+		delivery.Status = models.DeliverySucceeded
+		delivery.LastError = ""
+	case delivery.Attempts >= w.cfg.Webhooks.MaxAttempts:
+		delivery.Status = models.DeliveryFailed
+		delivery.LastError = sendErr.Error()
+	default:
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = time.Now().Add(w.backoff(delivery.Attempts))
+	}
+
+	log.Info("webhook delivery attempted",
+		slog.String("delivery_id", delivery.ID),
+		slog.String("webhook_id", delivery.WebhookID),
+		slog.Int("attempt", delivery.Attempts),
+		slog.Int("response_code", code),
+		slog.String("status", string(delivery.Status)),
+		slog.Duration("duration", time.Since(startTime)))
+
+	if err := w.webhookRepo.UpdateDelivery(ctx, &delivery); err != nil {
+		log.Error("failed to update delivery",
+			slog.String("delivery_id", delivery.ID),
+			logger.Err(err))
+	}
+}
+
+// backoff — экспоненциальная задержка с 10% случайного разброса.
+func (w *webhookUsecase) backoff(attempt int) time.Duration {
+	delay := w.cfg.Webhooks.InitialBackoff
+	for i := 1; i < attempt && delay < w.cfg.Webhooks.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > w.cfg.Webhooks.MaxBackoff {
+		delay = w.cfg.Webhooks.MaxBackoff
+	}
+	return delay + time.Duration(mathrand.Int63n(int64(delay)/10+1))
+}
+
+// newWebhookDeliveries готовит доставки события для всех вебхуков владельца.
+// Записываются они вместе с результатом анализа, поэтому событие не теряется,
+// если процесс упадёт сразу после обновления статуса.
+func newWebhookDeliveries(ctx context.Context, repo repositories.WebhookRepository, event *models.ContentEvent) ([]*models.WebhookDelivery, error) {
+	if !event.Status.IsTerminal() || event.UserID == "" {
+		return nil, nil
+	}
+
+	hooks, err := repo.ListWebhooks(ctx, event.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	if len(hooks) == 0 {
+		return nil, nil
+	}
+
+	eventName := models.EventContentCompleted
+	if event.Status == models.StatusFailed {
+		eventName = models.EventContentFailed
+	}
+
+	now := time.Now()
+	deliveries := make([]*models.WebhookDelivery, 0, len(hooks))
+	for _, hook := range hooks {
+		deliveryID := uuid.New().String()
+		payload, err := json.Marshal(models.WebhookPayload{
+			DeliveryID: deliveryID,
+			Event:      eventName,
+			ContentID:  event.ContentID,
+			Type:       event.Type,
+			Status:     event.Status,
+			Analysis:   event.Metadata,
+			OccurredAt: event.OccurredAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		}
+
+		deliveries = append(deliveries, &models.WebhookDelivery{
+			ID:            deliveryID,
+			WebhookID:     hook.ID,
+			ContentID:     event.ContentID,
+			Event:         eventName,
+			Payload:       payload,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+
+	return deliveries, nil
+}
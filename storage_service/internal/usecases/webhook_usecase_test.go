@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"storage_service/internal/config"
+	"storage_service/internal/domain/models"
+	"storage_service/internal/domain/repositories"
+	"storage_service/internal/webhooks"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeWebhookRepo отдаёт доставки из due и запоминает последнее обновление каждой.
+type fakeWebhookRepo struct {
+	repositories.WebhookRepository
+
+	mu      sync.Mutex
+	due     []*models.DueDelivery
+	updated map[string]models.WebhookDelivery
+}
+
+func (f *fakeWebhookRepo) ClaimDueDeliveries(ctx context.Context, leaseUntil time.Time, limit int) ([]*models.DueDelivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var out []*models.DueDelivery
+	for _, d := range f.due {
+		if d.Status != models.DeliveryPending {
+			continue
+		}
+		if u, ok := f.updated[d.ID]; ok {
+			d.WebhookDelivery = u
+			if d.Status != models.DeliveryPending {
+				continue
+			}
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+func (f *fakeWebhookRepo) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updated[delivery.ID] = *delivery
+	return nil
+}
+
+func newTestWebhookUsecase(repo repositories.WebhookRepository, client *http.Client) *webhookUsecase {
+	return &webhookUsecase{
+		cfg: &config.Config{Webhooks: config.WebhooksConfig{
+			MaxAttempts:    3,
+			InitialBackoff: 10 * time.Second,
+			MaxBackoff:     time.Minute,
+			Timeout:        time.Second,
+			BatchSize:      10,
+		}},
+		webhookRepo: repo,
+		sender:      webhooks.NewSender(client),
+		log:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	w := newTestWebhookUsecase(nil, http.DefaultClient)
+
+	tests := []struct {
+		attempt int
+		base    time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{3, 40 * time.Second},
+		{4, time.Minute},
+		{10, time.Minute},
+	}
+	for _, tt := range tests {
+		got := w.backoff(tt.attempt)
+		if got < tt.base || got > tt.base+tt.base/10 {
+			t.Errorf("backoff(%d) = %v, want within [%v, %v]", tt.attempt, got, tt.base, tt.base+tt.base/10)
+		}
+	}
+}
+
+func TestDispatchDueMarksFailedAfterMaxAttempts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	repo := &fakeWebhookRepo{
+		due: []*models.DueDelivery{{
+			WebhookDelivery: models.WebhookDelivery{
+				ID:        "d1",
+				WebhookID: "w1",
+				Payload:   []byte(`{}`),
+				Status:    models.DeliveryPending,
+			},
+			URL:    srv.URL,
+			Secret: "secret-secret-secret",
+		}},
+		updated: make(map[string]models.WebhookDelivery),
+	}
+	w := newTestWebhookUsecase(repo, srv.Client())
+
+	for attempt := 1; attempt <= w.cfg.Webhooks.MaxAttempts; attempt++ {
+		n, err := w.DispatchDue(context.Background())
+		if err != nil {
+			t.Fatalf("DispatchDue: %v", err)
+		}
+		if n != 1 {
+			t.Fatalf("attempt %d: dispatched %d deliveries, want 1", attempt, n)
+		}
+
+		got := repo.updated["d1"]
+		if got.Attempts != attempt || got.ResponseCode != http.StatusInternalServerError || got.LastError == "" {
+			t.Fatalf("attempt %d: unexpected delivery state %+v", attempt, got)
+		}
+		wantStatus := models.DeliveryPending
+		if attempt == w.cfg.Webhooks.MaxAttempts {
+			wantStatus = models.DeliveryFailed
+		}
+		if got.Status != wantStatus {
+			t.Fatalf("attempt %d: status = %s, want %s", attempt, got.Status, wantStatus)
+		}
+	}
+
+	if n, _ := w.DispatchDue(context.Background()); n != 0 {
+		t.Errorf("failed delivery dispatched again")
+	}
+}
@@ -0,0 +1,142 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var ErrForbiddenAddress = errors.New("address is not publicly routable")
+
+// nonPublic — адреса специального назначения из реестров IANA: частные,
+// служебные, документационные и транслирующие сети. Проверки net.IP вроде
+// IsPrivate покрывают их не все: мимо прошли бы CGNAT, 0.0.0.0/8 и NAT64,
+// через который IPv6-адрес ведёт на любой IPv4.
+var nonPublic = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("169.254.0.0/16"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("192.88.99.0/24"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("224.0.0.0/4"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+
+	netip.MustParsePrefix("::/128"),
+	netip.MustParsePrefix("::1/128"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001::/23"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("2002::/16"),
+	netip.MustParsePrefix("fc00::/7"),
+	netip.MustParsePrefix("fe80::/10"),
+	netip.MustParsePrefix("fec0::/10"),
+	netip.MustParsePrefix("ff00::/8"),
+}
+
+// AddressPolicy решает, на какие адреса можно отправлять вебхуки: на публичные
+// и на сети из allowed, например на приёмник во внутренней сети стенда.
+type AddressPolicy struct {
+	allowed []netip.Prefix
+}
+
+// NewAddressPolicy принимает сети в нотации CIDR.
+func NewAddressPolicy(allowed []string) (*AddressPolicy, error) {
+	p := &AddressPolicy{}
+	for _, s := range allowed {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed network %q: %w", s, err)
+		}
+		p.allowed = append(p.allowed, prefix.Masked())
+	}
+	return p, nil
+}
+
+func (p *AddressPolicy) Allowed(addr netip.Addr) bool {
+	// ::ffff:10.0.0.1 — тот же 10.0.0.1
+	addr = addr.Unmap().WithZone("")
+	for _, prefix := range p.allowed {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return isPublic(addr)
+}
+
+// CheckURL проверяет, что URL абсолютный http(s) и все адреса его хоста
+// разрешены: иначе вебхуком можно было бы достучаться до внутренних сервисов.
+func (p *AddressPolicy) CheckURL(ctx context.Context, rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, fmt.Errorf("url must be an absolute http(s) url")
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve host %q: %w", u.Hostname(), err)
+	}
+	for _, addr := range addrs {
+		if !p.Allowed(addr) {
+			return nil, fmt.Errorf("%w: %s resolves to %s", ErrForbiddenAddress, u.Hostname(), addr)
+		}
+	}
+
+	return u, nil
+}
+
+// NewClient возвращает клиент, который проверяет адрес при каждом соединении.
+// Проверки при создании вебхука мало: DNS может вернуть другой адрес к моменту
+// отправки, а получатель — перенаправить запрос на внутренний хост.
+func NewClient(timeout time.Duration, policy *AddressPolicy) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, address)
+			}
+			if !policy.Allowed(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// через прокси соединение шло бы к адресу прокси, и проверка ничего бы не дала
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
+
+func isPublic(addr netip.Addr) bool {
+	if !addr.IsValid() {
+		return false
+	}
+	for _, prefix := range nonPublic {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestAddressPolicyDefault(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"::ffff:127.0.0.1", false},
+		{"100.64.0.1", false},
+		{"0.1.2.3", false},
+		{"198.18.0.1", false},
+		{"192.0.0.170", false},
+		{"64:ff9b::a9fe:a9fe", false},
+		{"2002:a00:1::1", false},
+		{"ff02::1", false},
+		{"fe80::1%eth0", false},
+	}
+
+	policy, err := NewAddressPolicy(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		if got := policy.Allowed(netip.MustParseAddr(tt.ip)); got != tt.want {
+			t.Errorf("Allowed(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestAddressPolicyAllowedNetworks(t *testing.T) {
+	policy, err := NewAddressPolicy([]string{"10.20.0.0/16", "fd12::/64"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.20.1.2", true},
+		{"::ffff:10.20.1.2", true},
+		{"fd12::5", true},
+		{"10.21.0.1", false},
+		{"127.0.0.1", false},
+		{"8.8.8.8", true},
+	}
+	for _, tt := range tests {
+		if got := policy.Allowed(netip.MustParseAddr(tt.ip)); got != tt.want {
+			t.Errorf("Allowed(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+
+	if _, err := NewAddressPolicy([]string{"10.20.0.1"}); err == nil {
+		t.Error("NewAddressPolicy accepted an address without prefix length")
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	ctx := context.Background()
+	policy, err := NewAddressPolicy(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, raw := range []string{
+		"ftp://example.com/hook",
+		"/relative",
+		"http://127.0.0.1:8080/hook",
+		"http://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://localhost/hook",
+	} {
+		if _, err := policy.CheckURL(ctx, raw); err == nil {
+			t.Errorf("CheckURL(%q) accepted", raw)
+		}
+	}
+
+	if _, err := policy.CheckURL(ctx, "https://8.8.8.8/hook"); err != nil {
+		t.Errorf("CheckURL rejected a public address: %v", err)
+	}
+}
+
+func TestNewClientRefusesLoopback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback server")
+	}))
+	defer srv.Close()
+
+	policy, err := NewAddressPolicy(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewClient(time.Second, policy).Get(srv.URL)
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Fatalf("err = %v, want ErrForbiddenAddress", err)
+	}
+}
+
+func TestNewClientAllowedNetwork(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	policy, err := NewAddressPolicy([]string{"127.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := NewClient(time.Second, policy).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("request to an allowed network failed: %v", err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,63 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSenderSend(t *testing.T) {
+	const secret = "secret-secret-secret"
+	payload := []byte(`{"delivery_id":"d1"}`)
+
+	var verified bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verified = Verify(secret, r.Header.Get(SignatureHeader), r.Header.Get(TimestampHeader), body, time.Minute)
+		if r.Header.Get(DeliveryHeader) != "d1" || r.Header.Get(EventHeader) != "content.completed" {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	code, err := NewSender(srv.Client()).Send(context.Background(), &Delivery{
+		ID:      "d1",
+		Event:   "content.completed",
+		URL:     srv.URL,
+		Secret:  secret,
+		Payload: payload,
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if code != http.StatusNoContent {
+		t.Errorf("code = %d, want %d", code, http.StatusNoContent)
+	}
+	if !verified {
+		t.Error("receiver could not verify the signature")
+	}
+}
+
+func TestSenderSendNon2xx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	code, err := NewSender(srv.Client()).Send(context.Background(), &Delivery{
+		ID:      "d1",
+		URL:     srv.URL,
+		Secret:  "secret-secret-secret",
+		Payload: []byte(`{}`),
+	})
+	if err == nil {
+		t.Fatal("expected an error for a 503 response")
+	}
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("code = %d, want %d", code, http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,48 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	EventHeader     = "X-Webhook-Event"
+
+	signaturePrefix = "sha256="
+)
+
+// Sign считает подпись тела запроса: HMAC-SHA256 от "<timestamp>.<body>".
+// Метка времени входит в подпись, чтобы получатель мог отбрасывать повторы.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись, пришедшую в заголовках, и допустимый возраст метки времени.
+func Verify(secret string, signature string, timestamp string, body []byte, tolerance time.Duration) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	ts := time.Unix(unix, 0)
+	if tolerance > 0 && time.Since(ts).Abs() > tolerance {
+		return false
+	}
+
+	expected := Sign(secret, ts, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
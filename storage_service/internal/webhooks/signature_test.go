@@ -0,0 +1,44 @@
+package webhooks
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	body := []byte(`{"event":"content.completed"}`)
+	now := time.Now()
+	ts := strconv.FormatInt(now.Unix(), 10)
+	sig := Sign("secret-secret-secret", now, body)
+
+	if !Verify("secret-secret-secret", sig, ts, body, time.Minute) {
+		t.Fatal("valid signature rejected")
+	}
+	if Verify("another-secret-value", sig, ts, body, time.Minute) {
+		t.Error("signature accepted with a wrong secret")
+	}
+	if Verify("secret-secret-secret", sig, ts, []byte(`{"event":"content.failed"}`), time.Minute) {
+		t.Error("signature accepted for a modified body")
+	}
+	if Verify("secret-secret-secret", sig[len(signaturePrefix):], ts, body, time.Minute) {
+		t.Error("signature accepted without prefix")
+	}
+}
+
+func TestVerifyTolerance(t *testing.T) {
+	body := []byte(`{}`)
+	old := time.Now().Add(-10 * time.Minute)
+	ts := strconv.FormatInt(old.Unix(), 10)
+	sig := Sign("secret-secret-secret", old, body)
+
+	if Verify("secret-secret-secret", sig, ts, body, 5*time.Minute) {
+		t.Error("stale signature accepted")
+	}
+	if !Verify("secret-secret-secret", sig, ts, body, 0) {
+		t.Error("zero tolerance should skip the age check")
+	}
+	if Verify("secret-secret-secret", sig, "not-a-number", body, 0) {
+		t.Error("malformed timestamp accepted")
+	}
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_content_created_at;
+DROP INDEX IF EXISTS idx_content_type;
+DROP INDEX IF EXISTS idx_content_status;
@@ -0,0 +1,3 @@
+CREATE INDEX idx_content_status ON content (status);
+CREATE INDEX idx_content_type ON content (type);
+CREATE INDEX idx_content_created_at ON content (created_at);
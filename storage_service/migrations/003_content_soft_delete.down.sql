@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_content_deleted_at;
+
+ALTER TABLE content
+    DROP COLUMN IF EXISTS deleted_at;
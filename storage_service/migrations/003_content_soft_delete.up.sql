@@ -0,0 +1,4 @@
+ALTER TABLE content
+    ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_content_deleted_at ON content (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,30 @@
+CREATE TABLE webhooks
+(
+    id         VARCHAR(36) PRIMARY KEY,
+    user_id    VARCHAR(36)  NOT NULL,
+    url        VARCHAR(2048) NOT NULL,
+    secret     VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP    NOT NULL,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX idx_webhooks_user_id ON webhooks (user_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE webhook_deliveries
+(
+    id              VARCHAR(36) PRIMARY KEY,
+    webhook_id      VARCHAR(36) NOT NULL REFERENCES webhooks (id),
+    content_id      VARCHAR(36) NOT NULL,
+    event           VARCHAR(50) NOT NULL,
+    payload         JSONB       NOT NULL,
+    status          VARCHAR(20) NOT NULL,
+    attempts        INTEGER     NOT NULL DEFAULT 0,
+    response_code   INTEGER     NOT NULL DEFAULT 0,
+    last_error      TEXT        NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP   NOT NULL,
+    created_at      TIMESTAMP   NOT NULL,
+    updated_at      TIMESTAMP   NOT NULL
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at DESC);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_content_request_id;
+
+ALTER TABLE content
+    DROP COLUMN IF EXISTS request_id;
@@ -0,0 +1,4 @@
+ALTER TABLE content
+    ADD COLUMN request_id VARCHAR(128) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_content_request_id ON content (request_id) WHERE request_id <> '';
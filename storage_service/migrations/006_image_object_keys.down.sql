@@ -0,0 +1,2 @@
+-- публичные URL не восстанавливаются: бакет больше не отдаёт объекты анонимно
+SELECT 1;
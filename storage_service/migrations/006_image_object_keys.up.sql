@@ -0,0 +1,11 @@
+-- раньше в s3_key сохранялся публичный URL объекта: {url}/{bucket}/{key}
+--
+-- Сами объекты загружались с ACL public-read, и миграция данных их не закрывает.
+-- При старте storage_service переводит бакет в BucketOwnerEnforced, после чего
+-- S3 перестаёт применять ACL, включая уже выданные. Если хранилище это
+-- не поддерживает, но ACL применяет, старые объекты закрываются вручную:
+--   aws s3api list-objects-v2 --bucket <bucket> --query 'Contents[].Key' --output text \
+--     | tr '\t' '\n' | xargs -I{} aws s3api put-object-acl --bucket <bucket> --key {} --acl private
+UPDATE image_content
+SET s3_key = regexp_replace(s3_key, '^https?://[^/]+/[^/]+/', '')
+WHERE s3_key ~ '^https?://';
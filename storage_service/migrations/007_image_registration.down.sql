@@ -0,0 +1,3 @@
+ALTER TABLE image_content
+    DROP COLUMN IF EXISTS checksum,
+    DROP COLUMN IF EXISTS bucket;
@@ -0,0 +1,5 @@
+-- объект изображения записывается при регистрации, до вердикта модерации,
+-- чтобы очистка могла удалить его и у контента, так и не получившего вердикт
+ALTER TABLE image_content
+    ADD COLUMN bucket VARCHAR(63) NOT NULL DEFAULT '',
+    ADD COLUMN checksum VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_content_user_created_at;
+
+ALTER TABLE content
+    DROP COLUMN IF EXISTS user_id;
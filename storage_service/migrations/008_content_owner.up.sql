@@ -0,0 +1,5 @@
+-- IF NOT EXISTS: в ранних сборках колонка добавлялась в 002
+ALTER TABLE content
+    ADD COLUMN IF NOT EXISTS user_id VARCHAR(36) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_content_user_created_at ON content (user_id, created_at DESC, id DESC);
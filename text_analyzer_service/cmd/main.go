@@ -2,12 +2,17 @@ package main
 
 import (
 	"context"
+	"github.com/deeelis/platform/health"
+	"github.com/deeelis/platform/tracing"
 	"os"
 	"os/signal"
 	"syscall"
 	"text_analyzer_service/internal/config"
 	"text_analyzer_service/internal/consumer/kafka"
+	kafka2 "text_analyzer_service/internal/kafka"
+	"text_analyzer_service/internal/metrics"
 	"text_analyzer_service/logger"
+	"time"
 )
 
 func main() {
@@ -25,6 +30,11 @@ func main() {
 
 	log.Info("Text analyzer service started", "env", cfg.Env)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), "text_analyzer_service", cfg.Tracing)
+	if err != nil {
+		panic("failed to set up tracing: " + err.Error())
+	}
+
 	consumer, err := kafka.NewConsumer(context.Background(), cfg.Kafka, log)
 	if err != nil {
 
@@ -37,6 +47,16 @@ func main() {
 		}
 	}()
 
+	checker := health.NewChecker(cfg.Health.Timeout, log)
+	checker.Register("kafka", func(ctx context.Context) error {
+		return kafka2.Ping(ctx, cfg.Kafka.Brokers)
+	})
+
+	metricsServer := metrics.NewServer(cfg.Metrics, log)
+	metricsServer.Handle("/healthz", checker.LivenessHandler())
+	metricsServer.Handle("/readyz", checker.ReadinessHandler())
+	go metricsServer.Start()
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
@@ -45,5 +65,11 @@ func main() {
 
 	log.Info("Shutting down...")
 	cancel()
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	metricsServer.Stop(shutdownCtx)
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Error("failed to flush traces", logger.Err(err))
+	}
 	log.Info("Service stopped")
 }
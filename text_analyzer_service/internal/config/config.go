@@ -3,13 +3,18 @@ package config
 import (
 	"errors"
 	"flag"
+	"github.com/deeelis/platform/tracing"
 	"github.com/ilyakaznacheev/cleanenv"
 	"os"
+	"time"
 )
 
 type Config struct {
-	Env   string       `yaml:"env" env-default:"local"`
-	Kafka *KafkaConfig `yaml:"kafka"`
+	Env     string         `yaml:"env" env-default:"local"`
+	Kafka   *KafkaConfig   `yaml:"kafka"`
+	Metrics *MetricsConfig `yaml:"metrics"`
+	Health  *HealthConfig  `yaml:"health"`
+	Tracing *TracingConfig `yaml:"tracing"`
 }
 
 type KafkaConfig struct {
@@ -20,6 +25,17 @@ type KafkaConfig struct {
 	GroupID     string   `yaml:"group_id" env-default:"text_analyzer_group"`
 }
 
+type MetricsConfig struct {
+	Port int `yaml:"port" env-default:"9090"`
+}
+
+type HealthConfig struct {
+	Timeout time.Duration `yaml:"timeout" env-default:"2s"`
+}
+
+// TracingConfig — настройки экспорта трейсов, общие для всех сервисов
+type TracingConfig = tracing.Config
+
 func MustLoad() (*Config, error) {
 	configPath := fetchConfigPath()
 	if configPath == "" {
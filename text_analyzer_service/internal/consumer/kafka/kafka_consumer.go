@@ -3,11 +3,16 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"github.com/deeelis/platform/requestid"
+	"github.com/deeelis/platform/tracing"
+	"github.com/deeelis/platform/tracing/kafkatrace"
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
 	"log/slog"
 	"text_analyzer_service/internal/config"
 	"text_analyzer_service/internal/domain/models"
 	kafka2 "text_analyzer_service/internal/kafka"
+	"text_analyzer_service/internal/metrics"
 	"text_analyzer_service/internal/usecases"
 	"text_analyzer_service/internal/usecases/text_analyzer_usecase"
 	"text_analyzer_service/logger"
@@ -15,6 +20,7 @@ import (
 )
 
 type Consumer struct {
+	cfg         *config.KafkaConfig
 	fromGateway *kafka.Conn
 	toStorage   *kafka.Conn
 	usecase     usecases.TextAnalyzerUsecase
@@ -24,12 +30,12 @@ type Consumer struct {
 func NewConsumer(ctx context.Context, cfg *config.KafkaConfig, log *slog.Logger) (*Consumer, error) {
 	conn1, err := kafka2.ConnectKafka(ctx, cfg.Brokers[0], cfg.InputTopic, 0)
 	if err != nil {
-		log.Error("Kafka connect error", err.Error())
+		log.Error("Kafka connect error", logger.Err(err))
 		return nil, err
 	}
 	conn2, err := kafka2.ConnectKafka(ctx, cfg.Brokers[0], cfg.ResultTopic, 0)
 	if err != nil {
-		log.Error("Kafka connect error", err.Error())
+		log.Error("Kafka connect error", logger.Err(err))
 		return nil, err
 	}
 
@@ -39,7 +45,7 @@ func NewConsumer(ctx context.Context, cfg *config.KafkaConfig, log *slog.Logger)
 	for _, broker := range cfg.Brokers {
 		conn, err := kafka.DialContext(ctx, "tcp", broker)
 		if err != nil {
-			log.Error("failed to connect to kafka broker", err.Error(),
+			log.Error("failed to connect to kafka broker", logger.Err(err),
 				slog.String("broker", broker))
 			continue
 		}
@@ -47,7 +53,7 @@ func NewConsumer(ctx context.Context, cfg *config.KafkaConfig, log *slog.Logger)
 
 		brokers, err := conn.Brokers()
 		if err != nil {
-			log.Error("failed to get brokers list", err.Error())
+			log.Error("failed to get brokers list", logger.Err(err))
 			continue
 		}
 
@@ -59,6 +65,7 @@ func NewConsumer(ctx context.Context, cfg *config.KafkaConfig, log *slog.Logger)
 
 	usecase := text_analyzer_usecase.NewTextAnalyzerUseCase(log)
 	return &Consumer{
+		cfg:         cfg,
 		fromGateway: conn1,
 		toStorage:   conn2,
 		log:         log,
@@ -81,52 +88,80 @@ func (c *Consumer) ConsumeContent(ctx context.Context) error {
 			return err
 		}
 
-		var text models.TextContent
-		if err := json.Unmarshal(msg, &text); err != nil {
-			c.log.Error("failed to unmarshal message",
-				logger.Err(err),
-			)
-			continue
+		if err := c.handle(ctx, log, &msg); err != nil {
+			return err
 		}
+	}
+}
 
-		c.log.Debug("received message",
-			"text_id", text.ID,
+// handle анализирует одно сообщение в спане, продолжающем трейс шлюза, и передаёт
+// контекст дальше в storage. Ошибка возвращается только если продолжать чтение нельзя.
+func (c *Consumer) handle(ctx context.Context, log *slog.Logger, msg *kafka.Message) (err error) {
+	startTime := time.Now()
+	ctx, span := kafkatrace.StartConsumer(ctx, c.cfg.InputTopic, msg)
+	defer func() { tracing.End(span, err) }()
+	requestID := requestid.FromMessage(msg)
+	ctx = requestid.WithContext(ctx, requestID)
+	log = log.With(
+		slog.String("trace_id", tracing.TraceID(ctx)),
+		slog.String("request_id", requestID))
+
+	var text models.TextContent
+	if err := json.Unmarshal(msg.Value, &text); err != nil {
+		log.Error("failed to unmarshal message",
+			logger.Err(err),
 		)
+		span.RecordError(err)
+		metrics.ObserveConsumed(c.cfg.InputTopic, metrics.ResultInvalid, startTime)
+		return nil
+	}
+	span.SetAttributes(attribute.String("content.id", text.ID))
 
-		result, err := c.usecase.ProcessText(ctx, &text)
-		if err != nil {
-			c.log.Error("failed to process text",
-				"text_id", text.ID,
-				logger.Err(err),
-			)
-			continue
-		}
+	log.Debug("received message",
+		slog.String("text_id", text.ID),
+	)
 
-		message := models.TextAnalysisContent{
-			ID:     text.ID,
-			Data:   text.Data,
-			UserID: text.UserID,
-			Result: *result,
-		}
+	result, err := c.usecase.ProcessText(ctx, &text)
+	if err != nil {
+		log.Error("failed to process text",
+			slog.String("text_id", text.ID),
+			logger.Err(err),
+		)
+		span.RecordError(err)
+		metrics.ObserveConsumed(c.cfg.InputTopic, metrics.ResultError, startTime)
+		return nil
+	}
 
-		log.Info(message.Data, text.Data, message.UserID, text.UserID)
-		msg, err = json.Marshal(message)
-		if err != nil {
-			log.Error("failed to marshal content", logger.Err(err))
-			return err
-		}
+	message := models.TextAnalysisContent{
+		ID:     text.ID,
+		Data:   text.Data,
+		UserID: text.UserID,
+		Result: *result,
+	}
 
-		err = kafka2.SendToTopic(c.toStorage, msg)
-		if err != nil {
-			log.Error("failed to produce message",
-				logger.Err(err))
-			return err
-		}
+	data, err := json.Marshal(message)
+	if err != nil {
+		log.Error("failed to marshal content", logger.Err(err))
+		metrics.ObserveConsumed(c.cfg.InputTopic, metrics.ResultError, startTime)
+		return err
+	}
 
-		log.Info("content successfully produced",
-			slog.String("topic", text.Data),
-			slog.String("content_id", text.ID))
+	produceStart := time.Now()
+	produceCtx, produceSpan := kafkatrace.StartProducer(ctx, c.cfg.ResultTopic, 1)
+	err = kafka2.SendToTopic(produceCtx, c.toStorage, data)
+	tracing.End(produceSpan, err)
+	metrics.ObserveProduced(c.cfg.ResultTopic, produceStart, err)
+	if err != nil {
+		log.Error("failed to produce message",
+			logger.Err(err))
+		metrics.ObserveConsumed(c.cfg.InputTopic, metrics.ResultError, startTime)
+		return err
 	}
+	metrics.ObserveConsumed(c.cfg.InputTopic, metrics.ResultOK, startTime)
+
+	log.Info("content successfully produced",
+		slog.String("content_id", text.ID))
+	return nil
 }
 
 func (c *Consumer) Close() error {
@@ -2,6 +2,9 @@ package kafka
 
 import (
 	"context"
+	"errors"
+	"github.com/deeelis/platform/requestid"
+	"github.com/deeelis/platform/tracing/kafkatrace"
 	"github.com/segmentio/kafka-go"
 )
 
@@ -9,18 +12,37 @@ func ConnectKafka(ctx context.Context, address string, topic string, partition i
 	return kafka.DialLeader(ctx, "tcp", "kafka:9092", topic, partition)
 }
 
-func SendToTopic(conn *kafka.Conn, message []byte) error {
-	_, err := conn.WriteMessages(
-		kafka.Message{Value: message},
-	)
+// Ping проверяет, что хотя бы один брокер отвечает на запрос метаданных.
+func Ping(ctx context.Context, brokers []string) error {
+	var err error
+	for _, broker := range brokers {
+		var conn *kafka.Conn
+		conn, err = kafka.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			continue
+		}
+		_, err = conn.Brokers()
+		_ = conn.Close()
+		if err == nil {
+			return nil
+		}
+	}
+	if err == nil {
+		err = errors.New("no kafka brokers configured")
+	}
 	return err
 }
 
-func ReadFromTopic(conn *kafka.Conn) ([]byte, error) {
-	b := make([]byte, 10e3)
-	n, err := conn.Read(b)
-	if err != nil {
-		return nil, err
-	}
-	return b[:n], nil
+// SendToTopic пишет сообщение, передавая контекст трейса и id запроса из ctx в заголовках.
+func SendToTopic(ctx context.Context, conn *kafka.Conn, message []byte) error {
+	msg := kafka.Message{Value: message}
+	kafkatrace.Inject(ctx, &msg)
+	requestid.Inject(ctx, &msg)
+	_, err := conn.WriteMessages(msg)
+	return err
+}
+
+// ReadFromTopic возвращает сообщение целиком: в заголовках приходят контекст трейса и id запроса.
+func ReadFromTopic(conn *kafka.Conn) (kafka.Message, error) {
+	return conn.ReadMessage(10e3)
 }
@@ -0,0 +1,75 @@
+// Package metrics описывает метрики Prometheus анализатора текста
+// и отдаёт их на отдельном HTTP-порту.
+package metrics
+
+import (
+	"text_analyzer_service/internal/domain/models"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	ResultOK      = "ok"
+	ResultError   = "error"
+	ResultInvalid = "invalid"
+)
+
+var (
+	kafkaConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_consumed_messages_total",
+		Help: "Kafka messages consumed, by topic and processing result.",
+	}, []string{"topic", "result"})
+
+	kafkaConsumeDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kafka_consume_duration_seconds",
+		Help:    "Time spent processing a consumed Kafka message.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"topic"})
+
+	kafkaProduced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_produced_messages_total",
+		Help: "Kafka messages produced, by topic and result.",
+	}, []string{"topic", "result"})
+
+	kafkaProduceDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kafka_produce_duration_seconds",
+		Help:    "Kafka produce latency.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"topic"})
+
+	verdicts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "text_analyzer_verdicts_total",
+		Help: "Analyzer verdicts, by detected language and category.",
+	}, []string{"language", "category"})
+)
+
+func ObserveConsumed(topic string, result string, startTime time.Time) {
+	kafkaConsumed.WithLabelValues(topic, result).Inc()
+	kafkaConsumeDuration.WithLabelValues(topic).Observe(time.Since(startTime).Seconds())
+}
+
+func ObserveProduced(topic string, startTime time.Time, err error) {
+	result := ResultOK
+	if err != nil {
+		result = ResultError
+	}
+	kafkaProduced.WithLabelValues(topic, result).Inc()
+	kafkaProduceDuration.WithLabelValues(topic).Observe(time.Since(startTime).Seconds())
+}
+
+// ObserveVerdict учитывает каждую сработавшую категорию,
+// одобренный текст попадает в категорию approved
+func ObserveVerdict(result *models.AnalysisResult) {
+	if result.IsApproved {
+		verdicts.WithLabelValues(result.Language, "approved").Inc()
+		return
+	}
+	if result.IsSpam {
+		verdicts.WithLabelValues(result.Language, "spam").Inc()
+	}
+	if result.HasSensitive {
+		verdicts.WithLabelValues(result.Language, "sensitive").Inc()
+	}
+}
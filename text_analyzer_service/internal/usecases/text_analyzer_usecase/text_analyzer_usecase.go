@@ -2,8 +2,10 @@ package text_analyzer_usecase
 
 import (
 	"context"
+	"github.com/deeelis/platform/requestid"
 	"log/slog"
 	"text_analyzer_service/internal/domain/models"
+	"text_analyzer_service/internal/metrics"
 	models2 "text_analyzer_service/internal/text_analyzer"
 	"text_analyzer_service/internal/text_analyzer/basic_analyzer"
 	"text_analyzer_service/logger"
@@ -23,16 +25,19 @@ func NewTextAnalyzerUseCase(log *slog.Logger) *TextAnalyzerUsecase {
 }
 
 func (uc *TextAnalyzerUsecase) ProcessText(ctx context.Context, text *models.TextContent) (*models.AnalysisResult, error) {
+	log := uc.log.With(slog.String("request_id", requestid.FromContext(ctx)))
+
 	result, err := uc.analyzer.Analyze(text)
 	if err != nil {
-		uc.log.Error("text analysis failed",
+		log.Error("text analysis failed",
 			"text_id", text.ID,
 			logger.Err(err),
 		)
 		return nil, err
 	}
 
-	uc.log.Info("text processed successfully",
+	metrics.ObserveVerdict(result)
+	log.Info("text processed successfully",
 		"text_id", text.ID,
 		"is_approved", result.IsApproved,
 		"has_sensitive", result.HasSensitive,